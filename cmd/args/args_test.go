@@ -48,3 +48,20 @@ func TestBind(t *testing.T) {
 	}
 
 }
+
+func TestOfferSource_Validate(t *testing.T) {
+	assert.NoError(t, args.OfferSource{FilePath: "offer.json"}.Validate())
+	assert.NoError(t, args.OfferSource{Publisher: "foo", Offer: "bar"}.Validate())
+	assert.Error(t, args.OfferSource{}.Validate())
+	assert.Error(t, args.OfferSource{Publisher: "foo"}.Validate())
+	assert.Error(t, args.OfferSource{FilePath: "offer.json", Offer: "bar"}.Validate())
+}
+
+func TestVersionOrNext_Validate(t *testing.T) {
+	assert.NoError(t, args.VersionOrNext{Version: "1.0.0"}.Validate())
+	assert.NoError(t, args.VersionOrNext{Next: "minor"}.Validate())
+	assert.Error(t, args.VersionOrNext{}.Validate())
+	assert.Error(t, args.VersionOrNext{Version: "1.0"}.Validate())
+	assert.Error(t, args.VersionOrNext{Next: "build"}.Validate())
+	assert.Error(t, args.VersionOrNext{Version: "1.0.0", Next: "minor"}.Validate())
+}
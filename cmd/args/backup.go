@@ -0,0 +1,51 @@
+package args
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/backup"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+type (
+	// BackupArgs configure the local backups of offers taken before they are overwritten
+	BackupArgs struct {
+		Dir  string
+		Skip bool
+	}
+)
+
+// BindBackupDir will add the optional backup directory flag to the command
+func BindBackupDir(c *cobra.Command, b *BackupArgs) {
+	c.Flags().StringVar(&b.Dir, "backup-dir", "", fmt.Sprintf("(optional) Directory of the offer backups (default is $%s or $HOME/.pub/backups)", backup.DirEnvVar))
+}
+
+// BindBackupArgs will add the optional backup directory and no backup flags to a command which overwrites an offer
+func BindBackupArgs(c *cobra.Command, b *BackupArgs) {
+	BindBackupDir(c, b)
+	c.Flags().BoolVar(&b.Skip, "no-backup", false, "(optional) Overwrite the offer without backing it up first")
+}
+
+// Store returns the backup store in the backup directory
+func (b BackupArgs) Store() (*backup.Store, error) {
+	return backup.NewStore(b.Dir)
+}
+
+// Save backs up the offer before it is overwritten, unless backups are skipped
+func (b BackupArgs) Save(offer *partner.Offer) error {
+	if b.Skip {
+		return nil
+	}
+
+	store, err := b.Store()
+	if err == nil {
+		_, err = store.Save(offer)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to back up offer %s/%s; use --no-backup to skip the backup: %v", offer.PublisherID, offer.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package args
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/devigned/pub/pkg/diff"
+	"github.com/devigned/pub/pkg/service"
+)
+
+// PrintChanges prints each change to stderr, indented under a heading printed by the caller
+func PrintChanges(sl service.CommandServicer, changes []diff.Change) {
+	for _, c := range changes {
+		sl.GetPrinter().ErrPrintf("  %v\n", c)
+	}
+}
+
+// Confirm prompts on stderr and returns true if the answer read from in is yes
+func Confirm(sl service.CommandServicer, in io.Reader, prompt string) (bool, error) {
+	sl.GetPrinter().ErrPrintf("%s", prompt)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
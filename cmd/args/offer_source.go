@@ -0,0 +1,96 @@
+package args
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/document"
+	"github.com/devigned/pub/pkg/layout"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/service"
+)
+
+type (
+	// OfferSource identifies an offer either by a local file or by publisher and offer ID for the live draft
+	OfferSource struct {
+		Publisher string
+		Offer     string
+		FilePath  string
+		stdin     func() io.Reader
+	}
+)
+
+// BindOfferSource will add optional publisher, offer and offer-file flags to the command. Either offer-file or both
+// publisher and offer must be specified.
+func BindOfferSource(c *cobra.Command, src *OfferSource) {
+	c.Flags().StringVarP(&src.Publisher, "publisher", "p", "", "Publisher ID; For example, Contoso. Used with --offer to load the live draft.")
+	c.Flags().StringVarP(&src.Offer, "offer", "o", "", "String that uniquely identifies the offer. Used with --publisher to load the live draft.")
+	c.Flags().StringVarP(&src.FilePath, "offer-file", "f", "", "File path to the JSON or YAML file containing the offer, - for stdin, or a directory in the exploded layout")
+	src.stdin = c.InOrStdin
+}
+
+// Validate ensures either a file or a publisher and offer were specified, but not both
+func (src OfferSource) Validate() error {
+	switch {
+	case src.FilePath != "" && (src.Publisher != "" || src.Offer != ""):
+		return errors.New("specify either --offer-file or --publisher and --offer, but not both")
+	case src.FilePath != "":
+		return nil
+	case src.Publisher == "" || src.Offer == "":
+		return errors.New("specify either --offer-file or both --publisher and --offer")
+	default:
+		return nil
+	}
+}
+
+// IsFile returns true if the offer will be loaded from a local file
+func (src OfferSource) IsFile() bool {
+	return src.FilePath != ""
+}
+
+// Load reads the offer from the local file or fetches the draft from the Cloud Partner Portal
+func (src OfferSource) Load(ctx context.Context, sl service.CommandServicer) (*partner.Offer, error) {
+	if err := src.Validate(); err != nil {
+		return nil, err
+	}
+
+	if src.IsFile() {
+		stdin := io.Reader(os.Stdin)
+		if src.stdin != nil {
+			stdin = src.stdin()
+		}
+
+		var bits []byte
+		var err error
+		if layout.IsDir(src.FilePath) {
+			bits, err = layout.ReadDir(src.FilePath, nil)
+		} else {
+			bits, err = document.ReadOneJSON(src.FilePath, stdin)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var offer partner.Offer
+		if err := json.Unmarshal(bits, &offer); err != nil {
+			return nil, err
+		}
+		return &offer, nil
+	}
+
+	client, err := sl.GetCloudPartnerService()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Cloud Partner Portal client: %v", err)
+	}
+
+	return client.GetOffer(ctx, partner.ShowOfferParams{
+		PublisherID: src.Publisher,
+		OfferID:     src.Offer,
+	})
+}
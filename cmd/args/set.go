@@ -0,0 +1,114 @@
+package args
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/setpath"
+)
+
+type (
+	// SetArgs are edits to a JSON document given on the command line. Removals are applied first, followed by the
+	// --set, --set-string, --set-json and --set-file assignments in that order.
+	SetArgs struct {
+		Set       []string
+		SetString []string
+		SetJSON   []string
+		SetFile   []string
+		Unset     []string
+	}
+)
+
+// BindSetArgs will add the optional set, set-string, set-json, set-file and unset flags to the command
+func BindSetArgs(c *cobra.Command, s *SetArgs) {
+	c.Flags().StringArrayVar(&s.Set, "set", []string{}, "set a value on the command line as path=value; true, false, null and numbers are typed (can specify multiple)")
+	c.Flags().StringArrayVar(&s.SetString, "set-string", []string{}, "set a string value on the command line as path=value (can specify multiple)")
+	c.Flags().StringArrayVar(&s.SetJSON, "set-json", []string{}, "set a JSON value, such as an array or object, on the command line as path=json (can specify multiple)")
+	c.Flags().StringArrayVar(&s.SetFile, "set-file", []string{}, "set a string value to the contents of a file as path=filepath (can specify multiple)")
+	c.Flags().StringArrayVar(&s.Unset, "unset", []string{}, "remove the key or array element at a path (can specify multiple)")
+}
+
+// IsEmpty returns true if no edits were specified
+func (s SetArgs) IsEmpty() bool {
+	return len(s.Set)+len(s.SetString)+len(s.SetJSON)+len(s.SetFile)+len(s.Unset) == 0
+}
+
+// Apply applies the edits to a decoded JSON document and returns the updated document
+func (s SetArgs) Apply(doc interface{}) (interface{}, error) {
+	for _, item := range s.Unset {
+		p, err := setpath.Parse(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if doc, err = setpath.Unset(doc, p); err != nil {
+			return nil, err
+		}
+	}
+
+	assignments := []struct {
+		items []string
+		value func(string) (interface{}, error)
+	}{
+		{items: s.Set, value: func(raw string) (interface{}, error) {
+			return setpath.ParseValue(raw), nil
+		}},
+		{items: s.SetString, value: func(raw string) (interface{}, error) {
+			return raw, nil
+		}},
+		{items: s.SetJSON, value: func(raw string) (interface{}, error) {
+			var v interface{}
+			if err := json.Unmarshal([]byte(raw), &v); err != nil {
+				return nil, fmt.Errorf("unable to parse JSON value %q: %v", raw, err)
+			}
+			return v, nil
+		}},
+		{items: s.SetFile, value: func(raw string) (interface{}, error) {
+			bits, err := ioutil.ReadFile(raw)
+			if err != nil {
+				return nil, err
+			}
+			return string(bits), nil
+		}},
+	}
+
+	for _, a := range assignments {
+		for _, item := range a.items {
+			p, raw, err := setpath.ParseAssignment(item)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := a.value(raw)
+			if err != nil {
+				return nil, err
+			}
+
+			if doc, err = setpath.Set(doc, p, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return doc, nil
+}
+
+// ApplyJSON applies the edits to a JSON document and returns the updated JSON
+func (s SetArgs) ApplyJSON(bits []byte) ([]byte, error) {
+	if s.IsEmpty() {
+		return bits, nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(bits, &doc); err != nil {
+		return nil, err
+	}
+
+	doc, err := s.Apply(doc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
@@ -0,0 +1,127 @@
+package args
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/document"
+	"github.com/devigned/pub/pkg/render"
+	"github.com/devigned/pub/pkg/schema"
+)
+
+type (
+	// TemplateArgs render an offer or SKU file as a template before it is parsed. Values files are merged in order,
+	// followed by --var values, and environment variables are used for ${name} references without a value.
+	TemplateArgs struct {
+		Template    bool
+		ValuesFiles []string
+		Vars        []string
+		StrictVars  bool
+	}
+)
+
+// BindTemplateArgs will add the optional template, values, var and strict-vars flags to the command
+func BindTemplateArgs(c *cobra.Command, t *TemplateArgs) {
+	c.Flags().BoolVar(&t.Template, "template", false, "render the file as a template with environment variables; implied by --values and --var")
+	c.Flags().StringArrayVar(&t.ValuesFiles, "values", []string{}, "JSON or YAML file of template values (can specify multiple; later files take precedence)")
+	c.Flags().StringArrayVar(&t.Vars, "var", []string{}, "template value as name=value, where name may be a dotted path (can specify multiple)")
+	c.Flags().BoolVar(&t.StrictVars, "strict-vars", false, "fail if the template refers to a value which is not defined")
+}
+
+// Enabled returns true if the file should be rendered as a template
+func (t TemplateArgs) Enabled() bool {
+	return t.Template || len(t.ValuesFiles) > 0 || len(t.Vars) > 0
+}
+
+// Values loads the values files and applies the --var values. The values are loaded once for each command, since stdin
+// can only be read once; for the same reason, a values file can not be read from stdin if input, the file which is
+// rendered, is.
+func (t TemplateArgs) Values(input string, stdin io.Reader) (map[string]interface{}, error) {
+	readers := 0
+	for _, file := range append([]string{input}, t.ValuesFiles...) {
+		if file == document.Stdin {
+			readers++
+		}
+	}
+
+	if readers > 1 {
+		return nil, errors.New("stdin can only be read once; use - for only one of the file and the values files")
+	}
+
+	values := make(map[string]interface{})
+	for _, file := range t.ValuesFiles {
+		bits, err := document.ReadOneJSON(file, stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		var fileValues map[string]interface{}
+		if err := json.Unmarshal(bits, &fileValues); err != nil {
+			return nil, fmt.Errorf("values file %s must contain an object: %v", file, err)
+		}
+		values = render.Merge(values, fileValues)
+	}
+
+	for _, v := range t.Vars {
+		splits := strings.SplitN(v, "=", 2)
+		if len(splits) != 2 || splits[0] == "" {
+			return nil, fmt.Errorf("the var %s was not in name=value format", v)
+		}
+
+		keys := strings.Split(splits[0], ".")
+		var vars interface{} = splits[1]
+		for i := len(keys) - 1; i >= 0; i-- {
+			vars = map[string]interface{}{keys[i]: vars}
+		}
+		values = render.Merge(values, vars.(map[string]interface{}))
+	}
+	return values, nil
+}
+
+// Render renders the file content as a template with the values if templating is enabled, otherwise the content is
+// returned as is
+func (t TemplateArgs) Render(path string, bits []byte, values map[string]interface{}) ([]byte, error) {
+	if !t.Enabled() {
+		return bits, nil
+	}
+
+	rendered, err := render.Render(path, bits, render.Options{Values: values, Strict: t.StrictVars})
+	if err != nil {
+		return nil, fmt.Errorf("unable to render %s: %v", path, err)
+	}
+	return rendered, nil
+}
+
+// ReadJSON reads the file at path, or stdin if path is -, renders it and returns each document it contains as JSON
+func (t TemplateArgs) ReadJSON(path string, stdin io.Reader) ([][]byte, error) {
+	return t.ReadValidJSON(path, stdin, nil)
+}
+
+// ReadValidJSON is ReadJSON which also validates the rendered file, if the validator is not nil
+func (t TemplateArgs) ReadValidJSON(path string, stdin io.Reader, v *schema.Validator) ([][]byte, error) {
+	values, err := t.Values(path, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	bits, err := document.Read(path, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	if bits, err = t.Render(path, bits, values); err != nil {
+		return nil, err
+	}
+
+	if v != nil {
+		if err := v.ValidateFile(path, bits); err != nil {
+			return nil, err
+		}
+	}
+	return document.ToJSON(path, bits)
+}
@@ -0,0 +1,45 @@
+package args
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/devigned/pub/pkg/document"
+	"github.com/devigned/pub/pkg/schema"
+	"github.com/devigned/pub/pkg/service"
+)
+
+type (
+	// ValidateArgs control how offer and SKU files are validated against the schema of their partner type
+	ValidateArgs struct {
+		Strict bool
+		flag   *pflag.Flag
+	}
+)
+
+// BindValidateArgs will add the optional strict flag to the command, which is on by default for files and directories,
+// but not for stdin
+func BindValidateArgs(c *cobra.Command, v *ValidateArgs) {
+	c.Flags().BoolVar(&v.Strict, "strict", false, "fail on unknown or misspelled keys in the file (default true for files and directories, false for stdin)")
+	v.flag = c.Flags().Lookup("strict")
+}
+
+// Validator returns a validator for the schema of the file at path which prints warnings to stderr
+func (v ValidateArgs) Validator(path string, s *schema.Schema, sl service.CommandServicer) *schema.Validator {
+	return &schema.Validator{
+		Schema: s,
+		Strict: v.strict(path),
+		Warn: func(w schema.ValidationError) {
+			sl.GetPrinter().ErrPrintf("warning: %v\n", w)
+		},
+	}
+}
+
+// strict returns true if the file at path fails on unknown keys. Unless --strict is given, stdin is not strict, since
+// it is often an offer piped from offers show, which has fields that are not modeled.
+func (v ValidateArgs) strict(path string) bool {
+	if v.flag != nil && v.flag.Changed {
+		return v.Strict
+	}
+	return path != document.Stdin
+}
@@ -0,0 +1,52 @@
+package args
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/partner"
+)
+
+type (
+	// VersionOrNext is either an explicit Major.Minor.Patch version or the part of the latest version to increment
+	VersionOrNext struct {
+		Version string
+		Next    string
+	}
+)
+
+// BindVersionOrNext will add the version and next flags to the command
+func BindVersionOrNext(c *cobra.Command, arg *VersionOrNext) {
+	c.Flags().StringVar(&arg.Version, "version", "", "Version in Major.Minor.Patch format; for example, 1.0.0. Required unless --next is specified.")
+	c.Flags().StringVar(&arg.Next, "next", "", "Compute the version by incrementing the latest existing version, can be one of: major, minor, patch.")
+}
+
+// Validate returns an error unless exactly one of version or next is specified and it is well formed
+func (v VersionOrNext) Validate() error {
+	switch {
+	case v.Version != "" && v.Next != "":
+		return errors.New("only one of --version or --next may be specified")
+	case v.Version != "":
+		_, err := partner.ParseImageVersion(v.Version)
+		return err
+	case v.Next != "":
+		_, err := partner.ImageVersion{}.Next(partner.ImageVersionPart(v.Next))
+		return err
+	default:
+		return errors.New("one of --version or --next must be specified")
+	}
+}
+
+// Resolve returns the version, incrementing the latest version with next if an explicit version was not specified
+func (v VersionOrNext) Resolve(next func(part partner.ImageVersionPart) (partner.ImageVersion, error)) (string, error) {
+	if v.Next == "" {
+		return v.Version, nil
+	}
+
+	version, err := next(partner.ImageVersionPart(v.Next))
+	if err != nil {
+		return "", err
+	}
+	return version.String(), nil
+}
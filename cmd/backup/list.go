@@ -0,0 +1,49 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	listBackupsArgs struct {
+		Publisher string
+		Offer     string
+		Backup    args.BackupArgs
+	}
+)
+
+func newListCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs listBackupsArgs
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list the backups of an offer, or of every offer of a publisher, newest first",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, _ []string) error {
+			store, err := oArgs.Backup.Store()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			backups, err := store.List(oArgs.Publisher, oArgs.Offer)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to list backups: %v\n", err)
+				return err
+			}
+			return sl.GetPrinter().Print(backups)
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().StringVarP(&oArgs.Offer, "offer", "o", "", "(optional) String that uniquely identifies the offer. By default, the backups of every offer are listed.")
+	args.BindBackupDir(cmd, &oArgs.Backup)
+	return cmd, nil
+}
@@ -0,0 +1,59 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/backup"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+// newTmpBackup saves a backup of the offer to a temporary backup directory
+func newTmpBackup(t *testing.T, offer *partner.Offer) (string, *backup.Backup, func()) {
+	dir, del := test.NewTmpDir(t)
+	b, err := (&backup.Store{Dir: dir}).Save(offer)
+	require.NoError(t, err)
+	return dir, b, del
+}
+
+func TestListCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newListCommand)
+}
+
+func TestListCommand_Success(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	dir, b, del := newTmpBackup(t, offer)
+	defer del()
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	for _, args := range [][]string{{"-p", offer.PublisherID}, {"-p", offer.PublisherID, "-o", offer.ID}} {
+		cmd, err := test.QuietCommand(newListCommand(rm))
+		require.NoError(t, err)
+		cmd.SetArgs(append(args, "--backup-dir", dir))
+		require.NoError(t, cmd.Execute())
+		prtMock.AssertCalled(t, "Print", []backup.Backup{*b})
+	}
+}
+
+func TestListCommand_EmptyWithoutBackups(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newListCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", "publisher", "-o", "offer", "--backup-dir", dir})
+	require.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", []backup.Backup{})
+}
@@ -0,0 +1,115 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/diff"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	restoreBackupArgs struct {
+		Publisher string
+		Offer     string
+		ID        string
+		Yes       bool
+		Backup    args.BackupArgs
+	}
+)
+
+func newRestoreCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs restoreBackupArgs
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "restore the draft of an offer to the definition saved in a backup",
+		Long: `Restore the definition of the draft of an offer to the definition saved in a backup. Fields set by the Cloud
+Partner Portal, like the version and ETag, are kept from the draft. The changes to the draft are shown for
+confirmation, the draft is backed up, and it is put with the ETag of the fetched draft, so concurrent changes are not
+overwritten.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, _ []string) error {
+			store, err := oArgs.Backup.Store()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			saved, err := store.Load(oArgs.Publisher, oArgs.Offer, oArgs.ID)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			draft, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v\n", err)
+				return err
+			}
+
+			restored := *draft
+			restored.Definition = saved.Definition
+			changes, err := diff.Values(draft.Definition, restored.Definition)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if len(changes) == 0 {
+				sl.GetPrinter().ErrPrintf("the Draft slot already matches backup %s\n", oArgs.ID)
+				return sl.GetPrinter().Print(draft)
+			}
+
+			sl.GetPrinter().ErrPrintf("changes to the Draft slot:\n")
+			args.PrintChanges(sl, changes)
+
+			if !oArgs.Yes {
+				ok, err := args.Confirm(sl, cmd.InOrStdin(), fmt.Sprintf("Restore offer %s/%s to backup %s? [y/N]: ", oArgs.Publisher, oArgs.Offer, oArgs.ID))
+				if err != nil {
+					sl.GetPrinter().ErrPrintf("%v\n", err)
+					return err
+				}
+
+				if !ok {
+					err := errors.New("restore was not confirmed")
+					sl.GetPrinter().ErrPrintf("%v\n", err)
+					return err
+				}
+			}
+
+			if err := oArgs.Backup.Save(draft); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			updated, err := client.PutOfferIfMatch(ctx, &restored)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to put offer: %v\n", err)
+				return err
+			}
+			return sl.GetPrinter().Print(updated)
+		}),
+	}
+
+	if err := bindBackupID(cmd, &oArgs.Publisher, &oArgs.Offer, &oArgs.ID); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().BoolVarP(&oArgs.Yes, "yes", "y", false, "(optional) Restore without asking for confirmation")
+	args.BindBackupArgs(cmd, &oArgs.Backup)
+	return cmd, nil
+}
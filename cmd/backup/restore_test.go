@@ -0,0 +1,83 @@
+package backup
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/backup"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+// newRestoreMocks returns mocks for a draft which has changed since the backup of it was taken
+func newRestoreMocks(draft *partner.Offer) (*test.RegistryMock, *test.CloudPartnerServiceMock, *test.PrinterMock) {
+	rm, svcMock, prtMock := test.NewOfferRegistryMocks(draft)
+	svcMock.On("PutOfferIfMatch", mock.Anything, mock.Anything).Return(draft, nil)
+	return rm, svcMock, prtMock
+}
+
+func newChangedDraft() *partner.Offer {
+	draft := test.NewMarketplaceVMOffer()
+	draft.Version = 4
+	draft.Etag = "draft-etag"
+	draft.Definition.DisplayText = "overwritten"
+	return draft
+}
+
+func TestRestoreCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newRestoreCommand)
+	test.VerifyFailsOnArgs(t, newRestoreCommand, "-p", "foo", "-o", "bar")
+}
+
+func TestRestoreCommand_FailOnCloudPartnerServiceError(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	dir, b, del := newTmpBackup(t, offer)
+	defer del()
+
+	test.VerifyCloudPartnerServiceCommand(t, newRestoreCommand, "-p", offer.PublisherID, "-o", offer.ID, "--id", b.ID, "--backup-dir", dir)
+}
+
+func TestRestoreCommand_FailWhenNotConfirmed(t *testing.T) {
+	dir, b, del := newTmpBackup(t, test.NewMarketplaceVMOffer())
+	defer del()
+
+	draft := newChangedDraft()
+	rm, svcMock, _ := newRestoreMocks(draft)
+
+	cmd, err := test.QuietCommand(newRestoreCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader("n\n"))
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--id", b.ID, "--backup-dir", dir})
+	assert.Error(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+}
+
+func TestRestoreCommand_Success(t *testing.T) {
+	saved := test.NewMarketplaceVMOffer()
+	dir, b, del := newTmpBackup(t, saved)
+	defer del()
+
+	draft := newChangedDraft()
+	rm, svcMock, prtMock := newRestoreMocks(draft)
+
+	cmd, err := test.QuietCommand(newRestoreCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader("y\n"))
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--id", b.ID, "--backup-dir", dir})
+	require.NoError(t, cmd.Execute())
+
+	svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, mock.MatchedBy(func(offer *partner.Offer) bool {
+		return offer.Definition.DisplayText == saved.Definition.DisplayText && offer.Version == 4 && offer.Etag == "draft-etag"
+	}))
+	prtMock.AssertCalled(t, "Print", draft)
+
+	// the overwritten draft is backed up before it is restored
+	backups, err := (&backup.Store{Dir: dir}).List(draft.PublisherID, draft.ID)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, 4, backups[0].Version)
+}
@@ -0,0 +1,32 @@
+package backup
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+)
+
+// NewRootCmd returns the root backups cmd
+func NewRootCmd(sl service.CommandServicer) (*cobra.Command, error) {
+	rootCmd := &cobra.Command{
+		Use:              "backups",
+		Short:            "a group of actions for working with the local backups of offers taken before they are overwritten",
+		TraverseChildren: true,
+	}
+
+	cmdFuncs := []func(locator service.CommandServicer) (*cobra.Command, error){
+		newListCommand,
+		newShowCommand,
+		newRestoreCommand,
+	}
+
+	for _, f := range cmdFuncs {
+		cmd, err := f(sl)
+		if err != nil {
+			return rootCmd, err
+		}
+		rootCmd.AddCommand(cmd)
+	}
+
+	return rootCmd, nil
+}
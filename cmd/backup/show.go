@@ -0,0 +1,63 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	showBackupArgs struct {
+		Publisher string
+		Offer     string
+		ID        string
+		Backup    args.BackupArgs
+	}
+)
+
+func newShowCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs showBackupArgs
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "show the offer saved in a backup",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, _ []string) error {
+			store, err := oArgs.Backup.Store()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			offer, err := store.Load(oArgs.Publisher, oArgs.Offer, oArgs.ID)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+			return sl.GetPrinter().Print(offer.TypedView())
+		}),
+	}
+
+	if err := bindBackupID(cmd, &oArgs.Publisher, &oArgs.Offer, &oArgs.ID); err != nil {
+		return cmd, err
+	}
+
+	args.BindBackupDir(cmd, &oArgs.Backup)
+	return cmd, nil
+}
+
+// bindBackupID will add the required publisher, offer and backup ID flags to the command
+func bindBackupID(cmd *cobra.Command, publisher, offer, id *string) error {
+	if err := args.BindPublisher(cmd, publisher); err != nil {
+		return err
+	}
+
+	if err := args.BindOffer(cmd, offer); err != nil {
+		return err
+	}
+
+	cmd.Flags().StringVar(id, "id", "", "ID of the backup, as shown by backups list")
+	return cmd.MarkFlagRequired("id")
+}
@@ -0,0 +1,49 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+)
+
+func TestShowCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newShowCommand)
+	test.VerifyFailsOnArgs(t, newShowCommand, "-p", "foo", "-o", "bar")
+}
+
+func TestShowCommand_FailOnMissingBackup(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newShowCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", "foo", "-o", "bar", "--id", "missing", "--backup-dir", dir})
+	assert.Error(t, cmd.Execute())
+	prtMock.AssertNotCalled(t, "Print", mock.Anything)
+}
+
+func TestShowCommand_Success(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	dir, b, del := newTmpBackup(t, offer)
+	defer del()
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newShowCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--id", b.ID, "--backup-dir", dir})
+	require.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", offer.TypedView())
+}
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/cache"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+func newClearCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "remove every offer from the local cache",
+		Long: `Remove every offer from the local cache in PUB_CACHE_DIR, or in $HOME/.pub/cache if it is not set. The cache
+is filled again as offers are fetched while it is enabled.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			store, err := cache.NewStore("")
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if err := store.Clear(); err != nil {
+				sl.GetPrinter().ErrPrintf("unable to clear the cache: %v\n", err)
+				return err
+			}
+			return nil
+		}),
+	}
+	return cmd, nil
+}
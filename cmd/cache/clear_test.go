@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/cache"
+)
+
+func TestClearCommand_Success(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	defer os.Setenv(cache.DirEnvVar, os.Getenv(cache.DirEnvVar))
+	cacheDir := filepath.Join(dir, "cache")
+	require.NoError(t, os.Setenv(cache.DirEnvVar, cacheDir))
+	require.NoError(t, (&cache.Store{Dir: cacheDir}).Put("publisher", "offer", "Draft", "etag", []byte(`{}`)))
+
+	rm := new(test.RegistryMock)
+	cmd, err := test.QuietCommand(newClearCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{})
+	require.NoError(t, cmd.Execute())
+
+	_, err = os.Stat(cacheDir)
+	assert.True(t, os.IsNotExist(err))
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, infos)
+}
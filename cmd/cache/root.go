@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+)
+
+// NewRootCmd returns the root cache cmd
+func NewRootCmd(sl service.CommandServicer) (*cobra.Command, error) {
+	rootCmd := &cobra.Command{
+		Use:              "cache",
+		Short:            "a group of actions for working with the local cache of fetched offers",
+		TraverseChildren: true,
+	}
+
+	cmdFuncs := []func(locator service.CommandServicer) (*cobra.Command, error){
+		newClearCommand,
+	}
+
+	for _, f := range cmdFuncs {
+		cmd, err := f(sl)
+		if err != nil {
+			return rootCmd, err
+		}
+		rootCmd.AddCommand(cmd)
+	}
+
+	return rootCmd, nil
+}
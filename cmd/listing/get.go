@@ -0,0 +1,99 @@
+package listing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/jsonpath"
+	"github.com/devigned/pub/pkg/listing"
+	"github.com/devigned/pub/pkg/service"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	getListingArgs struct {
+		Publisher string
+		Offer     string
+		Field     string
+	}
+)
+
+func newGetCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs getListingArgs
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "get the marketplace listing of an offer",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			offer, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v", err)
+				return err
+			}
+
+			l, err := listing.Get(offer)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if oArgs.Field == "" {
+				return sl.GetPrinter().Print(l)
+			}
+
+			value, err := listingField(l, oArgs.Field)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+			return sl.GetPrinter().Print(value)
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().StringVar(&oArgs.Field, "field", "", "(optional) Only print the listing field, for example description")
+	return cmd, nil
+}
+
+// listingField returns the value of the listing field by its JSON name
+func listingField(l *listing.Listing, field string) (interface{}, error) {
+	doc, err := jsonpath.ToDocument(l)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := doc.(map[string]interface{})
+	if value, ok := fields[field]; ok {
+		return value, nil
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown listing field %q; must be one of %s", field, strings.Join(names, ", "))
+}
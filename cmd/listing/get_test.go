@@ -0,0 +1,51 @@
+package listing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func newListingMocks(offer, updated *partner.Offer) (*test.RegistryMock, *test.CloudPartnerServiceMock, *test.PrinterMock) {
+	rm, svcMock, prtMock := test.NewOfferRegistryMocks(offer)
+	if updated != nil {
+		svcMock.On("PutOfferIfMatch", mock.Anything, updated).Return(updated, nil)
+	}
+	return rm, svcMock, prtMock
+}
+
+func TestGetCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newGetCommand)
+	test.VerifyFailsOnArgs(t, newGetCommand, "-p", "foo")
+}
+
+func TestGetCommand_FailOnCloudPartnerServiceError(t *testing.T) {
+	test.VerifyCloudPartnerServiceCommand(t, newGetCommand, "-p", "foo", "-o", "bar")
+}
+
+func TestGetCommand_SuccessWithField(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	rm, _, prtMock := newListingMocks(offer, nil)
+
+	cmd, err := test.QuietCommand(newGetCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--field", "longSummary"})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", "longSummary")
+}
+
+func TestGetCommand_FailOnUnknownField(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	rm, _, prtMock := newListingMocks(offer, nil)
+
+	cmd, err := test.QuietCommand(newGetCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--field", "price"})
+	assert.Error(t, cmd.Execute())
+	prtMock.AssertNotCalled(t, "Print", mock.Anything)
+}
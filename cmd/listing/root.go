@@ -0,0 +1,31 @@
+package listing
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+)
+
+// NewRootCmd returns the root listing cmd
+func NewRootCmd(sl service.CommandServicer) (*cobra.Command, error) {
+	rootCmd := &cobra.Command{
+		Use:              "listing",
+		Short:            "a group of actions for working with the marketplace listing text and media of an offer",
+		TraverseChildren: true,
+	}
+
+	cmdFuncs := []func(locator service.CommandServicer) (*cobra.Command, error){
+		newGetCommand,
+		newSetCommand,
+	}
+
+	for _, f := range cmdFuncs {
+		cmd, err := f(sl)
+		if err != nil {
+			return rootCmd, err
+		}
+		rootCmd.AddCommand(cmd)
+	}
+
+	return rootCmd, nil
+}
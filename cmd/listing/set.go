@@ -0,0 +1,218 @@
+package listing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/listing"
+	"github.com/devigned/pub/pkg/service"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	setListingArgs struct {
+		Publisher    string
+		Offer        string
+		Listing      listing.Listing
+		FromFile     string
+		LogoFiles    map[listing.LogoKind]*string
+		ChangedFlags []string
+		Backup       args.BackupArgs
+	}
+)
+
+var (
+	// listingFlags are the flags which change a listing field
+	listingFlags = []string{"title", "summary", "long-summary", "description", "from-file", "small-logo", "medium-logo", "wide-logo", "screenshots", "videos"}
+
+	// logoFlags are the flags of the logo URLs by logo kind
+	logoFlags = map[listing.LogoKind]string{
+		listing.SmallLogo:  "small-logo",
+		listing.MediumLogo: "medium-logo",
+		listing.WideLogo:   "wide-logo",
+	}
+)
+
+func newSetCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	oArgs := setListingArgs{
+		LogoFiles: map[listing.LogoKind]*string{
+			listing.SmallLogo:  new(string),
+			listing.MediumLogo: new(string),
+			listing.WideLogo:   new(string),
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "set fields of the marketplace listing of an offer",
+		Long: `set fields of the marketplace listing of an offer
+
+Only the fields which are specified are changed. The description can be read from an HTML or markdown (.md) file
+with --from-file; markdown is converted to the HTML allowed in the marketplace. Logos are set by URL, and the local
+PNG file which was uploaded to the URL can be given with --small-logo-file, --medium-logo-file or --wide-logo-file
+to check its dimensions first.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			if err := readListingArgs(cmd, &oArgs); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			offer, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v", err)
+				return err
+			}
+
+			l, err := listing.Get(offer)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			applyListingArgs(oArgs, l)
+			if err := l.Validate(); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if err := oArgs.Backup.Save(offer); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if err := listing.Set(offer, l); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			offer, err = client.PutOfferIfMatch(ctx, offer)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to put offer: %v", err)
+				return err
+			}
+
+			l, err = listing.Get(offer)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+			return sl.GetPrinter().Print(l)
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().StringVar(&oArgs.Listing.Title, "title", "", "(optional) Title of the offer")
+	cmd.Flags().StringVar(&oArgs.Listing.Summary, "summary", "", "(optional) Summary of the offer")
+	cmd.Flags().StringVar(&oArgs.Listing.LongSummary, "long-summary", "", "(optional) Long summary of the offer")
+	cmd.Flags().StringVar(&oArgs.Listing.Description, "description", "", "(optional) HTML description of the offer")
+	cmd.Flags().StringVar(&oArgs.FromFile, "from-file", "", "(optional) HTML or markdown (.md) file containing the description of the offer")
+	cmd.Flags().StringVar(&oArgs.Listing.SmallLogo, "small-logo", "", "(optional) HTTPS URL of the 48x48 PNG logo")
+	cmd.Flags().StringVar(&oArgs.Listing.MediumLogo, "medium-logo", "", "(optional) HTTPS URL of the 90x90 PNG logo")
+	cmd.Flags().StringVar(&oArgs.Listing.WideLogo, "wide-logo", "", "(optional) HTTPS URL of the 255x115 PNG logo")
+	cmd.Flags().StringVar(oArgs.LogoFiles[listing.SmallLogo], "small-logo-file", "", "(optional) Local copy of the small logo to validate")
+	cmd.Flags().StringVar(oArgs.LogoFiles[listing.MediumLogo], "medium-logo-file", "", "(optional) Local copy of the medium logo to validate")
+	cmd.Flags().StringVar(oArgs.LogoFiles[listing.WideLogo], "wide-logo-file", "", "(optional) Local copy of the wide logo to validate")
+	cmd.Flags().StringSliceVar(&oArgs.Listing.Screenshots, "screenshots", nil, "(optional) HTTPS URLs of the screenshots, which replace the screenshots of the offer")
+	cmd.Flags().StringSliceVar(&oArgs.Listing.Videos, "videos", nil, "(optional) URLs of the videos, which replace the videos of the offer")
+	args.BindBackupArgs(cmd, &oArgs.Backup)
+	return cmd, nil
+}
+
+// readListingArgs checks the flags, validates the local logo files and reads the description file
+func readListingArgs(cmd *cobra.Command, oArgs *setListingArgs) error {
+	flags := cmd.Flags()
+	oArgs.ChangedFlags = nil
+	for _, name := range listingFlags {
+		if flags.Changed(name) {
+			oArgs.ChangedFlags = append(oArgs.ChangedFlags, name)
+		}
+	}
+
+	if len(oArgs.ChangedFlags) == 0 {
+		return fmt.Errorf("no listing changes were specified; set at least one of --%s", strings.Join(listingFlags, ", --"))
+	}
+
+	if flags.Changed("description") && flags.Changed("from-file") {
+		return errors.New("only one of --description or --from-file can be specified")
+	}
+
+	for _, kind := range []listing.LogoKind{listing.SmallLogo, listing.MediumLogo, listing.WideLogo} {
+		file := *oArgs.LogoFiles[kind]
+		if file == "" {
+			continue
+		}
+
+		if !flags.Changed(logoFlags[kind]) {
+			return fmt.Errorf("--%s-file validates the logo set with --%s, which is required", logoFlags[kind], logoFlags[kind])
+		}
+
+		if err := listing.ValidateLogoFile(file, kind); err != nil {
+			return err
+		}
+	}
+
+	if oArgs.FromFile != "" {
+		bits, err := ioutil.ReadFile(oArgs.FromFile)
+		if err != nil {
+			return err
+		}
+
+		oArgs.Listing.Description = string(bits)
+		switch strings.ToLower(filepath.Ext(oArgs.FromFile)) {
+		case ".md", ".markdown":
+			oArgs.Listing.Description = listing.MarkdownToHTML(oArgs.Listing.Description)
+		}
+	}
+	return nil
+}
+
+// applyListingArgs sets each listing field which was specified on the listing
+func applyListingArgs(oArgs setListingArgs, l *listing.Listing) {
+	for _, name := range oArgs.ChangedFlags {
+		switch name {
+		case "title":
+			l.Title = oArgs.Listing.Title
+		case "summary":
+			l.Summary = oArgs.Listing.Summary
+		case "long-summary":
+			l.LongSummary = oArgs.Listing.LongSummary
+		case "description", "from-file":
+			l.Description = oArgs.Listing.Description
+		case "small-logo":
+			l.SmallLogo = oArgs.Listing.SmallLogo
+		case "medium-logo":
+			l.MediumLogo = oArgs.Listing.MediumLogo
+		case "wide-logo":
+			l.WideLogo = oArgs.Listing.WideLogo
+		case "screenshots":
+			l.Screenshots = oArgs.Listing.Screenshots
+		case "videos":
+			l.Videos = oArgs.Listing.Videos
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package listing
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+// newListingOffer returns a VM offer with https logo URLs, which pass listing validation
+func newListingOffer() *partner.Offer {
+	offer := test.NewMarketplaceVMOffer()
+	md := &offer.Definition.OfferDetail.MarketplaceDetail
+	md.SmallLogo = "https://contoso.com/small.png"
+	md.MediumLogo = "https://contoso.com/medium.png"
+	md.WideLogo = "https://contoso.com/wide.png"
+	md.ScreenShots = []string{}
+	md.Videos = []string{}
+	return offer
+}
+
+func TestSetCommand_FailWithoutChanges(t *testing.T) {
+	rm, _, _ := newListingMocks(newListingOffer(), nil)
+
+	cmd, err := test.QuietCommand(newSetCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", "foo", "-o", "bar"})
+	assert.Error(t, cmd.Execute())
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestSetCommand_SuccessFromMarkdownFile(t *testing.T) {
+	file, cleanup := test.NewTmpFileWithContent(t, "description*.md", "# Contoso\n\nFast **and** secure.\n")
+	defer cleanup()
+
+	offer := newListingOffer()
+	updated := newListingOffer()
+	updated.Definition.OfferDetail.MarketplaceDetail.Title = "Contoso Server"
+	updated.Definition.OfferDetail.MarketplaceDetail.Description = "<h1>Contoso</h1>\n<p>Fast <strong>and</strong> secure.</p>"
+	rm, svcMock, _ := newListingMocks(offer, updated)
+
+	cmd, err := test.QuietCommand(newSetCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--title", "Contoso Server", "--from-file", file})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, updated)
+}
+
+func TestSetCommand_FailOnInvalidListing(t *testing.T) {
+	file, cleanup := test.NewTmpFileWithContent(t, "description*.html", "<p><script>alert(1)</script></p>")
+	defer cleanup()
+
+	cases := map[string][]string{
+		"HTML":        {"--from-file", file},
+		"SummaryLong": {"--summary", string(make([]byte, 101))},
+		"HTTPLogo":    {"--small-logo", "http://contoso.com/small.png"},
+	}
+
+	for name, args := range cases {
+		a := args
+		t.Run(name, func(t *testing.T) {
+			offer := newListingOffer()
+			rm, svcMock, _ := newListingMocks(offer, nil)
+
+			cmd, err := test.QuietCommand(newSetCommand(rm))
+			require.NoError(t, err)
+			cmd.SetArgs(append([]string{"-p", offer.PublisherID, "-o", offer.ID}, a...))
+			assert.Error(t, cmd.Execute())
+			svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSetCommand_FailOnLogoDimensions(t *testing.T) {
+	logo, cleanup := test.NewTmpFile(t, "logo")
+	defer cleanup()
+	f, err := os.Create(logo)
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(f, image.NewRGBA(image.Rect(0, 0, 90, 90))))
+	require.NoError(t, f.Close())
+
+	rm, _, prtMock := newListingMocks(newListingOffer(), nil)
+
+	cmd, err := test.QuietCommand(newSetCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", "foo", "-o", "bar", "--small-logo", "https://contoso.com/new.png", "--small-logo-file", logo})
+	assert.Error(t, cmd.Execute())
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+	prtMock.AssertCalled(t, "ErrPrintf", "%v\n", mock.Anything)
+}
@@ -0,0 +1,298 @@
+package offer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	audienceArgs struct {
+		Publisher       string
+		Offer           string
+		SubscriptionIDs []string
+		FromFile        string
+		Backup          args.BackupArgs
+	}
+
+	// audienceChange returns the allowed subscriptions resulting from changing the current allowed subscriptions
+	audienceChange func(current []string) ([]string, error)
+)
+
+func newAudienceCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "audience",
+		Short: "a group of actions for working with the subscriptions which can see an offer in preview",
+	}
+
+	cmdFuncs := []func(locator service.CommandServicer) (*cobra.Command, error){
+		newAudienceListCommand,
+		newAudienceAddCommand,
+		newAudienceRemoveCommand,
+		newAudienceSyncCommand,
+	}
+
+	for _, f := range cmdFuncs {
+		c, err := f(sl)
+		if err != nil {
+			return cmd, err
+		}
+		cmd.AddCommand(c)
+	}
+
+	return cmd, nil
+}
+
+func newAudienceListCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs audienceArgs
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list the subscriptions which can see an offer in preview",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			offer, offerType, err := getAudienceOffer(ctx, sl, client, oArgs)
+			if err != nil {
+				return err
+			}
+
+			return sl.GetPrinter().Print(allowedSubscriptions(offerType, offer))
+		}),
+	}
+
+	return bindAudienceArgs(cmd, &oArgs)
+}
+
+func newAudienceAddCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs audienceArgs
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "add subscriptions which can see an offer in preview",
+		Run: runAudienceChange(sl, &oArgs, func(current []string) ([]string, error) {
+			added, err := partner.ParseSubscriptionIDs(oArgs.SubscriptionIDs)
+			if err != nil {
+				return nil, err
+			}
+			return dedupeSubscriptionIDs(append(current, added...)), nil
+		}),
+	}
+
+	return bindAudienceSubscriptionArgs(cmd, &oArgs)
+}
+
+func newAudienceRemoveCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs audienceArgs
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "remove subscriptions which can see an offer in preview",
+		Run: runAudienceChange(sl, &oArgs, func(current []string) ([]string, error) {
+			removed, err := partner.ParseSubscriptionIDs(oArgs.SubscriptionIDs)
+			if err != nil {
+				return nil, err
+			}
+
+			remove := make(map[string]bool, len(removed))
+			for _, id := range removed {
+				remove[id] = true
+			}
+
+			var remaining []string
+			for _, id := range current {
+				if !remove[strings.ToLower(id)] {
+					remaining = append(remaining, id)
+				}
+			}
+			return dedupeSubscriptionIDs(remaining), nil
+		}),
+	}
+
+	return bindAudienceSubscriptionArgs(cmd, &oArgs)
+}
+
+func newAudienceSyncCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs audienceArgs
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "make the subscriptions which can see an offer in preview match a file",
+		Long: `make the subscriptions which can see an offer in preview match a file
+
+The file has a subscription ID on each line. Blank lines and lines starting with # are ignored.`,
+		Run: runAudienceChange(sl, &oArgs, func(current []string) ([]string, error) {
+			return readSubscriptionIDsFile(oArgs.FromFile)
+		}),
+	}
+
+	if _, err := bindAudienceArgs(cmd, &oArgs); err != nil {
+		return cmd, err
+	}
+
+	args.BindBackupArgs(cmd, &oArgs.Backup)
+	cmd.Flags().StringVar(&oArgs.FromFile, "from-file", "", "File with the subscription ID on each line which should be able to see the offer in preview")
+	err := cmd.MarkFlagRequired("from-file")
+	return cmd, err
+}
+
+func bindAudienceArgs(cmd *cobra.Command, oArgs *audienceArgs) (*cobra.Command, error) {
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	err := args.BindOffer(cmd, &oArgs.Offer)
+	return cmd, err
+}
+
+func bindAudienceSubscriptionArgs(cmd *cobra.Command, oArgs *audienceArgs) (*cobra.Command, error) {
+	if _, err := bindAudienceArgs(cmd, oArgs); err != nil {
+		return cmd, err
+	}
+
+	args.BindBackupArgs(cmd, &oArgs.Backup)
+	cmd.Flags().StringSliceVar(&oArgs.SubscriptionIDs, "subscription", nil, "Subscription ID (GUID); may be repeated or comma separated")
+	err := cmd.MarkFlagRequired("subscription")
+	return cmd, err
+}
+
+// runAudienceChange fetches the offer, applies the change to its allowed subscriptions and puts the offer if they
+// changed. The offer is put with its Etag, so changes made to the offer after it was fetched are not overwritten.
+func runAudienceChange(sl service.CommandServicer, oArgs *audienceArgs, change audienceChange) func(cmd *cobra.Command, args []string) {
+	return xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+		client, err := sl.GetCloudPartnerService()
+		if err != nil {
+			sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+			return err
+		}
+
+		offer, offerType, err := getAudienceOffer(ctx, sl, client, *oArgs)
+		if err != nil {
+			return err
+		}
+
+		current := allowedSubscriptions(offerType, offer)
+		updated, err := change(current)
+		if err != nil {
+			sl.GetPrinter().ErrPrintf("%v\n", err)
+			return err
+		}
+
+		if updated == nil {
+			updated = []string{}
+		}
+
+		if equalSubscriptionIDs(current, updated) {
+			sl.GetPrinter().ErrPrintf("the allowed subscriptions of offer %s/%s are unchanged\n", oArgs.Publisher, oArgs.Offer)
+			return sl.GetPrinter().Print(current)
+		}
+
+		if err := oArgs.Backup.Save(offer); err != nil {
+			sl.GetPrinter().ErrPrintf("%v\n", err)
+			return err
+		}
+
+		offerType.SetAllowedSubscriptions(offer, updated)
+		offer, err = client.PutOfferIfMatch(ctx, offer)
+		if err != nil {
+			sl.GetPrinter().ErrPrintf("unable to put offer: %v", err)
+			return err
+		}
+
+		return sl.GetPrinter().Print(allowedSubscriptions(offerType, offer))
+	})
+}
+
+func getAudienceOffer(ctx context.Context, sl service.CommandServicer, client service.CloudPartnerServicer, oArgs audienceArgs) (*partner.Offer, partner.OfferType, error) {
+	offer, err := client.GetOffer(ctx, partner.ShowOfferParams{
+		PublisherID: oArgs.Publisher,
+		OfferID:     oArgs.Offer,
+	})
+
+	if err != nil {
+		sl.GetPrinter().ErrPrintf("unable to get offer: %v", err)
+		return nil, nil, err
+	}
+
+	offerType, err := offer.OfferType()
+	if err != nil {
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return nil, nil, err
+	}
+	return offer, offerType, nil
+}
+
+// allowedSubscriptions returns the allowed subscriptions of the offer, or an empty list rather than nil so the list
+// prints as an empty JSON array
+func allowedSubscriptions(offerType partner.OfferType, offer *partner.Offer) []string {
+	if ids := offerType.AllowedSubscriptions(offer); ids != nil {
+		return ids
+	}
+	return []string{}
+}
+
+// dedupeSubscriptionIDs removes IDs which differ only by case from an earlier ID
+func dedupeSubscriptionIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	deduped := make([]string, 0, len(ids))
+	for _, id := range ids {
+		key := strings.ToLower(id)
+		if !seen[key] {
+			seen[key] = true
+			deduped = append(deduped, id)
+		}
+	}
+	return deduped
+}
+
+func equalSubscriptionIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func readSubscriptionIDsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("the subscriptions file has no subscription IDs; an offer in preview must be visible to at least one subscription")
+	}
+	return partner.ParseSubscriptionIDs(ids)
+}
@@ -0,0 +1,126 @@
+package offer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+const (
+	existingSubscription = "4145cbfe-cd94-439d-aa3c-1ec6c7e53074"
+	qaSubscription       = "9f4f6b3e-7c6f-4a8d-9d5c-0b7b0c9a2f10"
+)
+
+func newAudienceMocks(offer, updated *partner.Offer) (*test.RegistryMock, *test.CloudPartnerServiceMock, *test.PrinterMock) {
+	rm, svcMock, prtMock := test.NewOfferRegistryMocks(offer)
+	if updated != nil {
+		svcMock.On("PutOfferIfMatch", mock.Anything, updated).Return(updated, nil)
+	}
+	return rm, svcMock, prtMock
+}
+
+func TestAudienceCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newAudienceCommand, "list", "-p", "foo")
+	test.VerifyFailsOnArgs(t, newAudienceCommand, "add", "-p", "foo", "-o", "bar")
+	test.VerifyFailsOnArgs(t, newAudienceCommand, "remove", "-p", "foo", "-o", "bar")
+	test.VerifyFailsOnArgs(t, newAudienceCommand, "sync", "-p", "foo", "-o", "bar")
+}
+
+func TestAudienceListCommand_Success(t *testing.T) {
+	offer := test.NewMarketplaceCoreVMOffer()
+	rm, _, prtMock := newAudienceMocks(offer, nil)
+
+	cmd, err := test.QuietCommand(newAudienceCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"list", "-p", offer.PublisherID, "-o", offer.ID})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", []string{existingSubscription})
+}
+
+func TestAudienceAddCommand_Success(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	updated := test.NewMarketplaceVMOffer()
+	updated.Definition.OfferDetail.MarketplaceDetail.AllowedSubscriptions = []string{existingSubscription, qaSubscription}
+	rm, svcMock, prtMock := newAudienceMocks(offer, updated)
+
+	cmd, err := test.QuietCommand(newAudienceCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"add", "-p", offer.PublisherID, "-o", offer.ID, "--subscription", "9F4F6B3E-7C6F-4A8D-9D5C-0B7B0C9A2F10," + existingSubscription, "--subscription", qaSubscription})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, updated)
+	svcMock.AssertNotCalled(t, "PutOffer", mock.Anything, mock.Anything)
+	prtMock.AssertCalled(t, "Print", []string{existingSubscription, qaSubscription})
+}
+
+func TestAudienceAddCommand_FailOnInvalidSubscription(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	rm, svcMock, _ := newAudienceMocks(offer, nil)
+
+	cmd, err := test.QuietCommand(newAudienceCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"add", "-p", offer.PublisherID, "-o", offer.ID, "--subscription", "not-a-guid"})
+	assert.Error(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+}
+
+func TestAudienceAddCommand_SkipsUnchanged(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	rm, svcMock, prtMock := newAudienceMocks(offer, nil)
+
+	cmd, err := test.QuietCommand(newAudienceCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"add", "-p", offer.PublisherID, "-o", offer.ID, "--subscription", existingSubscription})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+	prtMock.AssertCalled(t, "Print", []string{existingSubscription})
+}
+
+func TestAudienceRemoveCommand_Success(t *testing.T) {
+	offer := test.NewMarketplaceCoreVMOffer()
+	updated := test.NewMarketplaceCoreVMOffer()
+	updated.Definition.OfferDetail.CoreVMOfferDetail.AllowedSubscriptions = []string{}
+	rm, svcMock, _ := newAudienceMocks(offer, updated)
+
+	cmd, err := test.QuietCommand(newAudienceCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"remove", "-p", offer.PublisherID, "-o", offer.ID, "--subscription", existingSubscription})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, updated)
+}
+
+func TestAudienceSyncCommand_Success(t *testing.T) {
+	file, cleanup := test.NewTmpFileWithContent(t, "audience", "# QA subscriptions\n"+qaSubscription+"\n\n"+qaSubscription+"\n")
+	defer cleanup()
+
+	offer := test.NewMarketplaceCoreVMOffer()
+	updated := test.NewMarketplaceCoreVMOffer()
+	updated.Definition.OfferDetail.CoreVMOfferDetail.AllowedSubscriptions = []string{qaSubscription}
+	rm, svcMock, prtMock := newAudienceMocks(offer, updated)
+
+	cmd, err := test.QuietCommand(newAudienceCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"sync", "-p", offer.PublisherID, "-o", offer.ID, "--from-file", file})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, updated)
+	prtMock.AssertCalled(t, "Print", []string{qaSubscription})
+}
+
+func TestAudienceSyncCommand_FailOnChangedOffer(t *testing.T) {
+	file, cleanup := test.NewTmpFileWithContent(t, "audience", qaSubscription)
+	defer cleanup()
+
+	offer := test.NewMarketplaceVMOffer()
+	rm, svcMock, prtMock := newAudienceMocks(offer, nil)
+	svcMock.On("PutOfferIfMatch", mock.Anything, mock.Anything).Return(new(partner.Offer), partner.ErrOfferChanged)
+
+	cmd, err := test.QuietCommand(newAudienceCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"sync", "-p", offer.PublisherID, "-o", offer.ID, "--from-file", file})
+	assert.Equal(t, partner.ErrOfferChanged, cmd.Execute())
+	prtMock.AssertCalled(t, "ErrPrintf", "unable to put offer: %v", []interface{}{partner.ErrOfferChanged})
+}
@@ -0,0 +1,184 @@
+package offer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/diff"
+	"github.com/devigned/pub/pkg/parallel"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	historyOfferArgs struct {
+		Publisher   string
+		Offer       string
+		Diff        bool
+		Parallelism int
+	}
+
+	// historyEntry is a version of an offer in the timeline, with the submissions made from it
+	historyEntry struct {
+		Version     int                 `json:"version"`
+		ChangedTime date.Time           `json:"changedTime"`
+		Status      string              `json:"status,omitempty"`
+		Submissions []historySubmission `json:"submissions,omitempty"`
+		Changes     []diff.Change       `json:"changes,omitempty"`
+		Error       string              `json:"error,omitempty"`
+	}
+
+	// historySubmission is an operation which submitted a version of an offer to a slot
+	historySubmission struct {
+		ID             string    `json:"id"`
+		SubmissionType string    `json:"submissionType"`
+		Slot           string    `json:"slot,omitempty"`
+		State          string    `json:"state,omitempty"`
+		ChangedTime    date.Time `json:"changedTime"`
+	}
+)
+
+func newHistoryCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs historyOfferArgs
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "show a timeline of the versions of an offer and their submissions",
+		Long: `Show a timeline of each version of an offer, from version 1 to the version of the draft, with the operations
+which submitted it to a slot. Versions are fetched concurrently. With --diff, each version shows the changes to the
+offer definition from the version before it.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			draft, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v\n", err)
+				return err
+			}
+
+			ops, err := client.ListOperations(ctx, partner.ListOperationsParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to fetch operations: %v\n", err)
+				return err
+			}
+
+			versions, versionErrs, err := fetchVersions(ctx, client, draft, oArgs.Parallelism)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			timeline, err := newTimeline(versions, versionErrs, ops, oArgs.Diff)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+			return sl.GetPrinter().Print(timeline)
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().BoolVar(&oArgs.Diff, "diff", false, "(optional) Show the changes from the previous version in each version")
+	cmd.Flags().IntVar(&oArgs.Parallelism, "parallelism", parallel.DefaultLimit, "(optional) Number of versions to fetch at once")
+	return cmd, nil
+}
+
+// fetchVersions fetches each version of the offer up to the version of the draft, which is used as is. A version
+// which cannot be fetched is nil with its error, so the rest of the history can still be shown.
+func fetchVersions(ctx context.Context, client service.CloudPartnerServicer, draft *partner.Offer, limit int) ([]*partner.Offer, []error, error) {
+	if draft.Version < 1 {
+		return nil, nil, nil
+	}
+
+	versions := make([]*partner.Offer, draft.Version)
+	errs := make([]error, draft.Version)
+	versions[draft.Version-1] = draft
+	err := parallel.ForEach(ctx, draft.Version-1, limit, func(ctx context.Context, i int) error {
+		versions[i], errs[i] = client.GetOfferByVersion(ctx, partner.ShowOfferByVersionParams{
+			PublisherID: draft.PublisherID,
+			OfferID:     draft.ID,
+			Version:     i + 1,
+		})
+		return ctx.Err()
+	})
+	return versions, errs, err
+}
+
+// newTimeline returns an entry for each version, starting at version 1, with the submissions of it. With withDiff,
+// each entry has the changes to the definition from the last version before it which could be fetched.
+func newTimeline(versions []*partner.Offer, errs []error, ops []partner.Operation, withDiff bool) ([]historyEntry, error) {
+	submissions := make(map[int][]historySubmission)
+	for _, op := range ops {
+		if op.OfferVersion == nil {
+			continue
+		}
+
+		submissions[*op.OfferVersion] = append(submissions[*op.OfferVersion], historySubmission{
+			ID:             op.ID,
+			SubmissionType: op.SubmissionType,
+			Slot:           op.Slot,
+			State:          op.SubmissionState,
+			ChangedTime:    op.ChangedTime,
+		})
+	}
+
+	timeline := make([]historyEntry, len(versions))
+	var previous *partner.Offer
+	for i, offer := range versions {
+		version := i + 1
+		entry := historyEntry{Version: version}
+
+		subs := submissions[version]
+		sort.SliceStable(subs, func(a, b int) bool {
+			return subs[a].ChangedTime.Before(subs[b].ChangedTime.Time)
+		})
+		entry.Submissions = subs
+
+		if offer == nil {
+			entry.Error = fmt.Sprintf("unable to get version %d: %v", version, errs[i])
+			timeline[i] = entry
+			continue
+		}
+
+		entry.ChangedTime = offer.ChangedTime
+		entry.Status = offer.Status
+		if withDiff {
+			var from partner.OfferDefinition
+			if previous != nil {
+				from = previous.Definition
+			}
+
+			changes, err := diff.Values(from, offer.Definition)
+			if err != nil {
+				return nil, err
+			}
+			entry.Changes = changes
+		}
+
+		previous = offer
+		timeline[i] = entry
+	}
+	return timeline, nil
+}
@@ -0,0 +1,103 @@
+package offer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/diff"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func newVersionedOffer(version int, displayText string) *partner.Offer {
+	offer := test.NewMarketplaceVMOffer()
+	offer.Version = version
+	offer.Definition.DisplayText = displayText
+	return offer
+}
+
+func newHistoryMocks(draft *partner.Offer, ops []partner.Operation, versions ...*partner.Offer) (*test.RegistryMock, *test.CloudPartnerServiceMock, *test.PrinterMock) {
+	rm, svcMock, prtMock := test.NewOfferRegistryMocks(draft)
+	svcMock.On("ListOperations", mock.Anything, partner.ListOperationsParams{
+		PublisherID: draft.PublisherID,
+		OfferID:     draft.ID,
+	}).Return(ops, nil)
+	for i, v := range versions {
+		params := partner.ShowOfferByVersionParams{PublisherID: draft.PublisherID, OfferID: draft.ID, Version: i + 1}
+		if v == nil {
+			svcMock.On("GetOfferByVersion", mock.Anything, params).Return((*partner.Offer)(nil), errors.New("not found"))
+			continue
+		}
+		svcMock.On("GetOfferByVersion", mock.Anything, params).Return(v, nil)
+	}
+	return rm, svcMock, prtMock
+}
+
+func TestHistoryCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newHistoryCommand)
+	test.VerifyFailsOnArgs(t, newHistoryCommand, "-p", "foo")
+}
+
+func TestHistoryCommand_FailOnCloudPartnerError(t *testing.T) {
+	test.VerifyCloudPartnerServiceCommand(t, newHistoryCommand, "-p", "foo", "-o", "bar")
+}
+
+func TestHistoryCommand_Success(t *testing.T) {
+	v1 := newVersionedOffer(1, "first")
+	v2 := newVersionedOffer(2, "second")
+	draft := newVersionedOffer(4, "draft")
+	published := date.Time{Time: time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)}
+	live := date.Time{Time: time.Date(2019, 10, 2, 0, 0, 0, 0, time.UTC)}
+	ops := []partner.Operation{
+		{Entity: partner.Entity{ID: "live"}, OfferVersion: to.IntPtr(2), SubmissionType: "Live", Slot: "Production", SubmissionState: "Succeeded", ChangedTime: live},
+		{Entity: partner.Entity{ID: "publish"}, OfferVersion: to.IntPtr(2), SubmissionType: "Publish", Slot: "Preview", SubmissionState: "Succeeded", ChangedTime: published},
+		{Entity: partner.Entity{ID: "unversioned"}, SubmissionType: "Cancel"},
+	}
+	rm, _, prtMock := newHistoryMocks(draft, ops, v1, v2, nil)
+
+	cmd, err := test.QuietCommand(newHistoryCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--diff", "--parallelism", "2"})
+	require.NoError(t, cmd.Execute())
+
+	timeline := prtMock.Calls[len(prtMock.Calls)-1].Arguments.Get(0).([]historyEntry)
+	require.Len(t, timeline, 4)
+
+	assert.Equal(t, 1, timeline[0].Version)
+	assert.NotEmpty(t, timeline[0].Changes)
+
+	assert.Equal(t, 2, timeline[1].Version)
+	require.Len(t, timeline[1].Submissions, 2)
+	assert.Equal(t, "publish", timeline[1].Submissions[0].ID)
+	assert.Equal(t, "live", timeline[1].Submissions[1].ID)
+	assert.Equal(t, []diff.Change{{Op: diff.Changed, Path: "$.displayText", From: "first", To: "second"}}, timeline[1].Changes)
+
+	assert.Equal(t, 3, timeline[2].Version)
+	assert.Contains(t, timeline[2].Error, "unable to get version 3")
+
+	assert.Equal(t, 4, timeline[3].Version)
+	assert.Equal(t, []diff.Change{{Op: diff.Changed, Path: "$.displayText", From: "second", To: "draft"}}, timeline[3].Changes)
+}
+
+func TestHistoryCommand_WithoutDiff(t *testing.T) {
+	draft := newVersionedOffer(2, "draft")
+	rm, svcMock, prtMock := newHistoryMocks(draft, nil, newVersionedOffer(1, "first"))
+
+	cmd, err := test.QuietCommand(newHistoryCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID})
+	require.NoError(t, cmd.Execute())
+
+	timeline := prtMock.Calls[len(prtMock.Calls)-1].Arguments.Get(0).([]historyEntry)
+	require.Len(t, timeline, 2)
+	assert.Empty(t, timeline[0].Changes)
+	assert.Empty(t, timeline[1].Changes)
+	svcMock.AssertNumberOfCalls(t, "GetOfferByVersion", 1)
+}
@@ -0,0 +1,156 @@
+package offer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/layout"
+	"github.com/devigned/pub/pkg/scaffold"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	initOfferArgs struct {
+		Type        string
+		Options     scaffold.Options
+		Interactive bool
+		OutFile     string
+		OutDir      string
+		Force       bool
+	}
+
+	// prompt is a question asked in interactive mode and the option it sets
+	prompt struct {
+		question string
+		value    *string
+	}
+)
+
+func newInitCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs initOfferArgs
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "generate a skeleton offer with placeholders for the required fields",
+		Long: `Generate a minimal offer of the given type with one SKU. Required fields which are not specified are set to
+placeholders starting with TODO. With --interactive, the title, summary, contacts and first SKU are prompted for;
+corevm offers have no contacts, so they are not asked for.
+The offer is printed, or written to a single JSON or YAML file with --out-file, or to a directory in the exploded
+layout (offer.yaml and skus/<planId>.yaml) with --out-dir.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			if oArgs.OutFile != "" && oArgs.OutDir != "" {
+				err := errors.New("specify either --out-file or --out-dir, but not both")
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			typeID, err := scaffold.ResolveTypeID(oArgs.Type)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+			oArgs.Options.TypeID = typeID
+
+			if oArgs.Interactive {
+				if err := promptOptions(sl, cmd.InOrStdin(), &oArgs.Options); err != nil {
+					sl.GetPrinter().ErrPrintf("%v\n", err)
+					return err
+				}
+			}
+
+			offer, err := scaffold.NewOffer(oArgs.Options)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			switch {
+			case oArgs.OutFile != "":
+				err = layout.WriteFile(oArgs.OutFile, offer, oArgs.Force)
+			case oArgs.OutDir != "":
+				err = layout.WriteDir(oArgs.OutDir, offer, oArgs.Force)
+			default:
+				return sl.GetPrinter().Print(offer)
+			}
+
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&oArgs.Type, "type", "t", "", "Offer type: "+strings.Join(shortTypeNames(), ", "))
+	if err := cmd.MarkFlagRequired("type"); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().StringVarP(&oArgs.Options.PublisherID, "publisher", "p", "", "(optional) Publisher ID; For example, Contoso.")
+	cmd.Flags().StringVarP(&oArgs.Options.OfferID, "offer", "o", "", "(optional) String that uniquely identifies the offer.")
+	cmd.Flags().StringVarP(&oArgs.Options.SKU.ID, "sku", "s", "", "(optional) String that uniquely identifies the first SKU (SKU ID).")
+	cmd.Flags().BoolVarP(&oArgs.Interactive, "interactive", "i", false, "(optional) Prompt for the title, summary, contacts and first SKU")
+	cmd.Flags().StringVar(&oArgs.OutFile, "out-file", "", "(optional) Write the offer to a JSON file, or YAML file with a .yaml extension")
+	cmd.Flags().StringVar(&oArgs.OutDir, "out-dir", "", "(optional) Write the offer to a directory in the exploded layout")
+	cmd.Flags().BoolVar(&oArgs.Force, "force", false, "(optional) Overwrite existing files")
+	return cmd, nil
+}
+
+// promptOptions asks for each option on stderr and reads the answers from in. Options which were already set by flags
+// are not asked for, and an empty answer leaves the option to be a placeholder. Contacts are only asked for if the
+// offer type has them.
+func promptOptions(sl service.CommandServicer, in io.Reader, opts *scaffold.Options) error {
+	prompts := []prompt{
+		{question: "Publisher ID", value: &opts.PublisherID},
+		{question: "Offer ID", value: &opts.OfferID},
+		{question: "Title", value: &opts.Title},
+		{question: "Summary", value: &opts.Summary},
+		{question: "Description", value: &opts.Description},
+	}
+
+	if scaffold.HasMarketplaceDetail(opts.TypeID) {
+		prompts = append(prompts,
+			prompt{question: "Support contact name", value: &opts.SupportContact.Name},
+			prompt{question: "Support contact email", value: &opts.SupportContact.Email},
+			prompt{question: "Support contact phone", value: &opts.SupportContact.Phone},
+			prompt{question: "Engineering contact name", value: &opts.EngineeringContact.Name},
+			prompt{question: "Engineering contact email", value: &opts.EngineeringContact.Email},
+			prompt{question: "Engineering contact phone", value: &opts.EngineeringContact.Phone},
+		)
+	}
+
+	prompts = append(prompts,
+		prompt{question: "First SKU ID", value: &opts.SKU.ID},
+		prompt{question: "First SKU title", value: &opts.SKU.Title},
+		prompt{question: "First SKU summary", value: &opts.SKU.Summary},
+	)
+
+	reader := bufio.NewReader(in)
+	for _, p := range prompts {
+		if *p.value != "" {
+			continue
+		}
+
+		sl.GetPrinter().ErrPrintf("%s (leave empty for a placeholder): ", p.question)
+		answer, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		*p.value = strings.TrimSpace(answer)
+	}
+	return nil
+}
+
+func shortTypeNames() []string {
+	ids := scaffold.TypeIDs()
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = strings.TrimPrefix(id, "microsoft-azure-")
+	}
+	return names
+}
@@ -0,0 +1,136 @@
+package offer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/layout"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func newInitMocks() (*test.RegistryMock, *test.PrinterMock) {
+	rm, _, prtMock := test.NewRegistryMocks()
+	return rm, prtMock
+}
+
+func printedOffer(t *testing.T, prtMock *test.PrinterMock) *partner.Offer {
+	for _, call := range prtMock.Calls {
+		if call.Method == "Print" {
+			offer, ok := call.Arguments.Get(0).(*partner.Offer)
+			require.True(t, ok)
+			return offer
+		}
+	}
+	require.Fail(t, "offer was not printed")
+	return nil
+}
+
+func TestInitCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newInitCommand)
+}
+
+func TestInitCommand_Success(t *testing.T) {
+	rm, prtMock := newInitMocks()
+	cmd, err := test.QuietCommand(newInitCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-t", "virtualmachines", "-p", "contoso", "-o", "widget", "-s", "widget-basic"})
+	require.NoError(t, cmd.Execute())
+
+	offer := printedOffer(t, prtMock)
+	assert.Equal(t, partner.VirtualMachineOfferType, offer.TypeID)
+	assert.Equal(t, "contoso", offer.PublisherID)
+	assert.Equal(t, "widget", offer.ID)
+	assert.Equal(t, "widget-basic", offer.Definition.Plans[0].ID)
+}
+
+func TestInitCommand_Interactive(t *testing.T) {
+	rm, prtMock := newInitMocks()
+	cmd, err := test.QuietCommand(newInitCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader("contoso\nwidget\nWidget\n\n\nJane\njane@contoso.com\n"))
+	cmd.SetArgs([]string{"-t", "saas", "-i", "-s", "widget-basic"})
+	require.NoError(t, cmd.Execute())
+
+	offer := printedOffer(t, prtMock)
+	assert.Equal(t, "contoso", offer.PublisherID)
+	assert.Equal(t, "widget", offer.ID)
+	assert.Equal(t, "Widget", offer.Definition.DisplayText)
+	assert.Equal(t, "widget-basic", offer.Definition.Plans[0].ID)
+
+	md := offer.Definition.OfferDetail.MarketplaceDetail
+	assert.Equal(t, "Jane", md.SupportContactName)
+	assert.Equal(t, "jane@contoso.com", md.SupportContactEmail)
+	assert.Equal(t, "TODO: engineering contact name", md.EngineeringContactName)
+	prtMock.AssertCalled(t, "ErrPrintf", "%s (leave empty for a placeholder): ", []interface{}{"Title"})
+	prtMock.AssertNotCalled(t, "ErrPrintf", "%s (leave empty for a placeholder): ", []interface{}{"First SKU ID"})
+}
+
+func TestInitCommand_InteractiveCoreVM(t *testing.T) {
+	rm, prtMock := newInitMocks()
+	cmd, err := test.QuietCommand(newInitCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader("contoso\nwidget\nWidget\n\n\nwidget-basic\nWidget Basic\n"))
+	cmd.SetArgs([]string{"-t", "corevm", "-i"})
+	require.NoError(t, cmd.Execute())
+
+	offer := printedOffer(t, prtMock)
+	assert.Equal(t, partner.CoreVMOfferType, offer.TypeID)
+	assert.Equal(t, "widget", offer.ID)
+	require.Len(t, offer.Definition.Plans, 1)
+	assert.Equal(t, "widget-basic", offer.Definition.Plans[0].ID)
+	assert.Equal(t, "Widget Basic", offer.Definition.Plans[0].PlanCoreVMDetail.SKUTitle)
+	prtMock.AssertCalled(t, "ErrPrintf", "%s (leave empty for a placeholder): ", []interface{}{"First SKU ID"})
+	prtMock.AssertNotCalled(t, "ErrPrintf", "%s (leave empty for a placeholder): ", []interface{}{"Support contact name"})
+	prtMock.AssertNotCalled(t, "ErrPrintf", "%s (leave empty for a placeholder): ", []interface{}{"Engineering contact name"})
+}
+
+func TestInitCommand_OutDir(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	rm, prtMock := newInitMocks()
+	cmd, err := test.QuietCommand(newInitCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-t", "corevm", "-s", "widget-basic", "--out-dir", dir})
+	require.NoError(t, cmd.Execute())
+	prtMock.AssertNotCalled(t, "Print", mock.Anything)
+
+	assert.FileExists(t, filepath.Join(dir, layout.OfferFileName))
+	assert.FileExists(t, filepath.Join(dir, layout.SKUDirName, "widget-basic.yaml"))
+
+	cmd, err = test.QuietCommand(newInitCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-t", "corevm", "-s", "widget-basic", "--out-dir", dir})
+	assert.Error(t, cmd.Execute())
+}
+
+func TestInitCommand_OutFile(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	path := filepath.Join(dir, "offer.yaml")
+	rm, _ := newInitMocks()
+	cmd, err := test.QuietCommand(newInitCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-t", "containers", "--out-file", path})
+	require.NoError(t, cmd.Execute())
+
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+}
+
+func TestInitCommand_FailOnUnknownType(t *testing.T) {
+	rm, prtMock := newInitMocks()
+	cmd, err := test.QuietCommand(newInitCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-t", "unknown"})
+	assert.Error(t, cmd.Execute())
+	prtMock.AssertNotCalled(t, "Print", mock.Anything)
+}
@@ -0,0 +1,74 @@
+package offer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/lint"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	lintOfferArgs struct {
+		Source   args.OfferSource
+		Suppress []string
+		Format   string
+	}
+)
+
+func newLintCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs lintOfferArgs
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "check an offer file or live draft against marketplace certification rules",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			if err := lint.ReportFormat(oArgs.Format).Validate(); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			linter, err := lint.New(lint.DefaultRules(), oArgs.Suppress...)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			offer, err := oArgs.Source.Load(ctx, sl)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to load offer: %v\n", err)
+				return err
+			}
+
+			report := linter.Lint(offer)
+			report.Source = oArgs.Source.FilePath
+			return printReport(sl, report, oArgs.Format)
+		}),
+	}
+
+	args.BindOfferSource(cmd, &oArgs.Source)
+	cmd.Flags().StringArrayVar(&oArgs.Suppress, "suppress", []string{}, "Rule ID to suppress (can specify multiple)")
+	cmd.Flags().StringVar(&oArgs.Format, "format", string(lint.JSONReportFormat), "Report format, can be one of: json, sarif")
+	return cmd, nil
+}
+
+// printReport prints the report in the given format and returns an error if the report contains errors
+func printReport(sl service.CommandServicer, report *lint.Report, format string) error {
+	out, err := report.Output(lint.ReportFormat(format))
+	if err != nil {
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return err
+	}
+
+	if err := sl.GetPrinter().Print(out); err != nil {
+		return err
+	}
+
+	if report.HasErrors() {
+		return fmt.Errorf("offer has %d error(s)", report.Errors)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package offer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/lint"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestLintCommand_FailOnMissingSource(t *testing.T) {
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "unable to load offer: %v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newLintCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", "foo"})
+	assert.Error(t, cmd.Execute())
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestLintCommand_FailOnLintErrors(t *testing.T) {
+	fName, del := test.NewTmpOfferFile(t, "offer")
+	defer del()
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.AnythingOfType("*lint.Report")).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newLintCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-f", fName})
+	assert.Error(t, cmd.Execute())
+
+	report := prtMock.Calls[0].Arguments.Get(0).(*lint.Report)
+	assert.Equal(t, fName, report.Source)
+	assert.True(t, report.HasErrors())
+}
+
+func TestLintCommand_SuccessWithSuppressionsAsSARIF(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, partner.ShowOfferParams{
+		PublisherID: offer.PublisherID,
+		OfferID:     offer.ID,
+	}).Return(offer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.AnythingOfType("*lint.SARIFLog")).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newLintCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--format", "sarif", "--suppress", "privacy-url", "--suppress", "support-email"})
+	assert.NoError(t, cmd.Execute())
+
+	log := prtMock.Calls[0].Arguments.Get(0).(*lint.SARIFLog)
+	assert.Empty(t, log.Runs[0].Results)
+}
@@ -0,0 +1,12 @@
+package offer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/devigned/pub/internal/test"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(test.RunWithTmpBackupDir(m))
+}
@@ -0,0 +1,175 @@
+package offer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/diff"
+	"github.com/devigned/pub/pkg/jsonpath"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/patch"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+const (
+	jsonPatchType  = "json"
+	mergePatchType = "merge"
+)
+
+type (
+	patchOfferArgs struct {
+		Publisher     string
+		Offer         string
+		Type          string
+		PatchFilePath string
+		Backup        args.BackupArgs
+	}
+)
+
+func newPatchCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs patchOfferArgs
+	cmd := &cobra.Command{
+		Use:   "patch",
+		Short: "apply a JSON Patch or JSON Merge Patch to the draft of an offer",
+		Long: `Apply an RFC 6902 JSON Patch (--type json) or an RFC 7386 JSON Merge Patch (--type merge) to the draft of an
+offer. Test operations in a JSON Patch are preconditions; if any fails, the offer is not changed. The changes are
+shown before the offer is put with the ETag of the fetched draft, so concurrent changes are not overwritten.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, _ []string) error {
+			bits, err := ioutil.ReadFile(oArgs.PatchFilePath)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			apply, err := patchFunc(oArgs.Type, bits)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			offer, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v", err)
+				return err
+			}
+
+			updated, err := patchOffer(offer, apply)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			changes, err := diff.Values(offer, updated)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if len(changes) == 0 {
+				sl.GetPrinter().ErrPrintf("the patch makes no changes to offer %s\n", offer.ID)
+				return sl.GetPrinter().Print(offer)
+			}
+
+			sl.GetPrinter().ErrPrintf("changes to the Draft slot:\n")
+			args.PrintChanges(sl, changes)
+
+			if err := oArgs.Backup.Save(offer); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			offer, err = client.PutOfferIfMatch(ctx, updated)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to put offer: %v", err)
+				return err
+			}
+
+			return sl.GetPrinter().Print(offer)
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().StringVarP(&oArgs.PatchFilePath, "patch-file", "f", "", "File path to the JSON file containing the patch")
+	if err := cmd.MarkFlagRequired("patch-file"); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().StringVarP(&oArgs.Type, "type", "t", jsonPatchType, "Type of the patch: json for a JSON Patch or merge for a JSON Merge Patch")
+	args.BindBackupArgs(cmd, &oArgs.Backup)
+	return cmd, nil
+}
+
+// patchFunc parses the patch file and returns a function which applies it to a decoded offer
+func patchFunc(patchType string, bits []byte) (func(interface{}) (interface{}, error), error) {
+	switch patchType {
+	case jsonPatchType:
+		p, err := patch.ParseJSONPatch(bits)
+		if err != nil {
+			return nil, err
+		}
+		return p.Apply, nil
+	case mergePatchType:
+		var p interface{}
+		if err := json.Unmarshal(bits, &p); err != nil {
+			return nil, fmt.Errorf("unable to parse JSON Merge Patch: %v", err)
+		}
+		return func(doc interface{}) (interface{}, error) {
+			return patch.MergePatch(doc, p), nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("patch type %q must be %s or %s", patchType, jsonPatchType, mergePatchType)
+	}
+}
+
+// patchOffer applies the patch to a copy of the offer and validates the result is still the same offer
+func patchOffer(offer *partner.Offer, apply func(interface{}) (interface{}, error)) (*partner.Offer, error) {
+	doc, err := jsonpath.ToDocument(offer)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc, err = apply(doc); err != nil {
+		return nil, err
+	}
+
+	bits, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated partner.Offer
+	if err := json.Unmarshal(bits, &updated); err != nil {
+		return nil, fmt.Errorf("the patched offer is not a valid offer: %v", err)
+	}
+
+	if updated.ID != offer.ID || updated.PublisherID != offer.PublisherID {
+		return nil, fmt.Errorf("the patch must not change the offer ID or publisher ID of offer %s", offer.ID)
+	}
+
+	if updated.Etag != offer.Etag {
+		return nil, fmt.Errorf("the patch must not change the ETag of offer %s", offer.ID)
+	}
+	return &updated, nil
+}
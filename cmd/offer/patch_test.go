@@ -0,0 +1,109 @@
+package offer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+)
+
+func TestPatchCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newPatchCommand)
+	test.VerifyFailsOnArgs(t, newPatchCommand, "-p", "foo")
+	test.VerifyFailsOnArgs(t, newPatchCommand, "-p", "foo", "-o", "bar")
+}
+
+func TestPatchCommand_FailOnCloudPartnerServiceError(t *testing.T) {
+	fName, del := test.NewTmpFileWithContent(t, "patch", `[]`)
+	defer del()
+
+	test.VerifyCloudPartnerServiceCommand(t, newPatchCommand, "-p", "foo", "-o", "bar", "-f", fName)
+}
+
+func TestPatchCommand_JSONPatchSuccess(t *testing.T) {
+	fName, del := test.NewTmpFileWithContent(t, "patch", `[
+  {"op": "test", "path": "/definition/plans/0/planId", "value": "planId_one"},
+  {"op": "replace", "path": "/definition/plans/0/microsoft-azure-virtualmachines.skuTitle", "value": "Patched"},
+  {"op": "add", "path": "/definition/plans/0/regions/-", "value": "XX"}
+]`)
+	defer del()
+
+	offer := test.NewMarketplaceVMOffer()
+	updated := test.NewMarketplaceVMOffer()
+	updated.Definition.Plans[0].PlanVirtualMachineDetail.SKUTitle = "Patched"
+	updated.Definition.Plans[0].Regions = append(updated.Definition.Plans[0].Regions, "XX")
+	rm, svcMock, prtMock := newAudienceMocks(offer, updated)
+
+	cmd, err := test.QuietCommand(newPatchCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "-f", fName})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, updated)
+	prtMock.AssertCalled(t, "ErrPrintf", "  %v\n", mock.Anything)
+	prtMock.AssertCalled(t, "Print", updated)
+}
+
+func TestPatchCommand_MergePatchSuccess(t *testing.T) {
+	fName, del := test.NewTmpFileWithContent(t, "patch", `{"definition": {"displayText": "Patched"}}`)
+	defer del()
+
+	offer := test.NewMarketplaceVMOffer()
+	updated := test.NewMarketplaceVMOffer()
+	updated.Definition.DisplayText = "Patched"
+	rm, svcMock, _ := newAudienceMocks(offer, updated)
+
+	cmd, err := test.QuietCommand(newPatchCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "-f", fName, "--type", "merge"})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, updated)
+}
+
+func TestPatchCommand_NoChangesSkipsPut(t *testing.T) {
+	fName, del := test.NewTmpFileWithContent(t, "patch", `{"definition": {"displayText": "displayText"}}`)
+	defer del()
+
+	offer := test.NewMarketplaceVMOffer()
+	rm, svcMock, prtMock := newAudienceMocks(offer, nil)
+
+	cmd, err := test.QuietCommand(newPatchCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "-f", fName, "-t", "merge"})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+	prtMock.AssertCalled(t, "Print", offer)
+}
+
+func TestPatchCommand_Fails(t *testing.T) {
+	cases := map[string]struct {
+		Type  string
+		Patch string
+	}{
+		"UnknownType":      {Type: "strategic", Patch: `[]`},
+		"InvalidPatch":     {Type: "json", Patch: `[{"op": "add"}]`},
+		"FailedTest":       {Type: "json", Patch: `[{"op": "test", "path": "/definition/displayText", "value": "other"}]`},
+		"InvalidOffer":     {Type: "merge", Patch: `{"definition": {"plans": "none"}}`},
+		"ChangedOfferID":   {Type: "json", Patch: `[{"op": "replace", "path": "/id", "value": "other"}]`},
+		"ChangedPublisher": {Type: "merge", Patch: `{"publisherId": "other"}`},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			fName, del := test.NewTmpFileWithContent(t, "patch", c.Patch)
+			defer del()
+
+			offer := test.NewMarketplaceVMOffer()
+			rm, svcMock, _ := newAudienceMocks(offer, nil)
+
+			cmd, err := test.QuietCommand(newPatchCommand(rm))
+			require.NoError(t, err)
+			cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "-f", fName, "-t", c.Type})
+			assert.Error(t, cmd.Execute())
+			svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package offer
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/policy"
+	"github.com/devigned/pub/pkg/service"
+)
+
+type (
+	// policyArgs configures policy enforcement for commands which mutate or publish an offer
+	policyArgs struct {
+		PolicyFile string
+		SkipPolicy bool
+	}
+)
+
+func bindPolicyArgs(cmd *cobra.Command, pArgs *policyArgs) {
+	cmd.Flags().StringVar(&pArgs.PolicyFile, "policy-file", "", fmt.Sprintf("(optional) Policy file to check the offer against (default is $%s)", policy.FileEnvVar))
+	cmd.Flags().BoolVar(&pArgs.SkipPolicy, "skip-policy", false, "(optional) Skip checking the offer against the policy file")
+}
+
+// file returns the policy file to enforce or an empty string if there is none
+func (pArgs policyArgs) file() string {
+	if pArgs.SkipPolicy {
+		return ""
+	}
+	return policy.ResolveFile(pArgs.PolicyFile)
+}
+
+// enforcePolicy checks the offer against the policy file, printing each finding, and returns an error if any of the
+// findings are errors
+func enforcePolicy(sl service.CommandServicer, file string, offer *partner.Offer) error {
+	p, err := policy.Load(file)
+	if err != nil {
+		sl.GetPrinter().ErrPrintf("unable to load policy file %s: %v\n", file, err)
+		return err
+	}
+
+	report, err := p.Check(offer)
+	if err != nil {
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return err
+	}
+
+	for _, f := range report.Findings {
+		sl.GetPrinter().ErrPrintf("%v\n", f)
+	}
+
+	if report.HasErrors() {
+		err := fmt.Errorf("offer violates %d policy rule(s) in %s", report.Errors, file)
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return err
+	}
+	return nil
+}
@@ -2,9 +2,16 @@ package offer
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/devigned/pub/pkg/diff"
+	"github.com/devigned/pub/pkg/lint"
 	"github.com/devigned/pub/pkg/service"
 
 	"github.com/devigned/pub/cmd/args"
@@ -12,11 +19,24 @@ import (
 	"github.com/devigned/pub/pkg/xcobra"
 )
 
+const (
+	// runningStatus is the submission state of an operation which has not completed
+	runningStatus = "running"
+)
+
 type (
 	publishOfferArgs struct {
 		Publisher          string
 		Offer              string
 		NotificationEmails string
+		Checks             publishChecks
+	}
+
+	// publishChecks configures the checks an offer must pass before it is published
+	publishChecks struct {
+		ExpectFilePath string
+		Yes            bool
+		Policy         policyArgs
 	}
 )
 
@@ -25,13 +45,30 @@ func newPublishCommand(sl service.CommandServicer) (*cobra.Command, error) {
 	cmd := &cobra.Command{
 		Use:   "publish",
 		Short: "publish an offer",
-		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+		Long: `publish an offer after checking the draft is ready to publish
+
+Before publishing, the draft offer is checked for required listing fields and image versions, the offer must not
+have a running operation and the changes from the Production slot to the Draft slot are shown for confirmation.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, _ []string) error {
 			client, err := sl.GetCloudPartnerService()
 			if err != nil {
 				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
 				return err
 			}
 
+			draft, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v\n", err)
+				return err
+			}
+
+			if err := checkPublish(ctx, cmd, sl, client, draft, oArgs.Checks); err != nil {
+				return err
+			}
+
 			opLocation, err := client.PublishOffer(ctx, partner.PublishOfferParams{
 				NotificationEmails: oArgs.NotificationEmails,
 				OfferID:            oArgs.Offer,
@@ -56,5 +93,167 @@ func newPublishCommand(sl service.CommandServicer) (*cobra.Command, error) {
 	}
 
 	cmd.Flags().StringVarP(&oArgs.NotificationEmails, "notification-emails", "e", "", "Comma separated list of emails to notify when publication completes.")
+	cmd.Flags().StringVar(&oArgs.Checks.ExpectFilePath, "expect-file", "", "(optional) Offer file the draft must match before publishing, for example the offer file which was reviewed")
+	cmd.Flags().BoolVarP(&oArgs.Checks.Yes, "yes", "y", false, "(optional) Publish without asking for confirmation")
+	bindPolicyArgs(cmd, &oArgs.Checks.Policy)
 	return cmd, nil
 }
+
+// checkPublish runs the checks the draft must pass before it is published: the built-in publish rules, the policy,
+// no running operation and the expected offer file, if any. The changes from the Production slot are then shown for
+// confirmation, unless checks.Yes is set.
+func checkPublish(ctx context.Context, cmd *cobra.Command, sl service.CommandServicer, client service.CloudPartnerServicer, draft *partner.Offer, checks publishChecks) error {
+	if err := checkPublishable(sl, draft); err != nil {
+		return err
+	}
+
+	if file := checks.Policy.file(); file != "" {
+		if err := enforcePolicy(sl, file, draft); err != nil {
+			return err
+		}
+	}
+
+	if err := checkNoRunningOperation(ctx, sl, client, draft); err != nil {
+		return err
+	}
+
+	if checks.ExpectFilePath != "" {
+		if err := checkExpectedOffer(sl, checks.ExpectFilePath, draft); err != nil {
+			return err
+		}
+	}
+
+	if err := printProductionDiff(ctx, sl, client, draft); err != nil {
+		return err
+	}
+
+	if checks.Yes {
+		return nil
+	}
+
+	ok, err := args.Confirm(sl, cmd.InOrStdin(), fmt.Sprintf("Publish offer %s/%s? [y/N]: ", draft.PublisherID, draft.ID))
+	if err != nil {
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return err
+	}
+
+	if !ok {
+		err := errors.New("publish was not confirmed")
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return err
+	}
+	return nil
+}
+
+// checkPublishable runs the built-in publish rules over the draft, printing each finding, and returns an error if any
+// of the findings are errors
+func checkPublishable(sl service.CommandServicer, draft *partner.Offer) error {
+	linter, err := lint.New(lint.PublishRules())
+	if err != nil {
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return err
+	}
+
+	report := linter.Lint(draft)
+	for _, f := range report.Findings {
+		sl.GetPrinter().ErrPrintf("%v\n", f)
+	}
+
+	if report.HasErrors() {
+		err := fmt.Errorf("draft offer is not ready to publish: %d error(s) found", report.Errors)
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return err
+	}
+	return nil
+}
+
+// checkNoRunningOperation returns an error if the offer already has an operation running, since only one operation
+// can run on an offer at a time
+func checkNoRunningOperation(ctx context.Context, sl service.CommandServicer, client service.CloudPartnerServicer, offer *partner.Offer) error {
+	ops, err := client.ListOperations(ctx, partner.ListOperationsParams{
+		PublisherID:    offer.PublisherID,
+		OfferID:        offer.ID,
+		FilteredStatus: runningStatus,
+	})
+	if err != nil {
+		sl.GetPrinter().ErrPrintf("unable to fetch operations: %v\n", err)
+		return err
+	}
+
+	if len(ops) > 0 {
+		ids := make([]string, len(ops))
+		for i, op := range ops {
+			ids[i] = op.ID
+		}
+		err := fmt.Errorf("offer %s/%s already has a running operation (%s); wait for it to complete or cancel it with `pub operations cancel`", offer.PublisherID, offer.ID, strings.Join(ids, ", "))
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return err
+	}
+	return nil
+}
+
+// checkExpectedOffer returns an error if the definition of the draft differs from the definition of the offer in the
+// expected file. Only definitions are compared, since fields like the version and status are set by the service.
+func checkExpectedOffer(sl service.CommandServicer, expectFilePath string, draft *partner.Offer) error {
+	bits, err := ioutil.ReadFile(expectFilePath)
+	if err != nil {
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return err
+	}
+
+	var expected partner.Offer
+	if err := json.Unmarshal(bits, &expected); err != nil {
+		sl.GetPrinter().ErrPrintf("unable unmarshal JSON offer into partner.Offer: %v\n", err)
+		return err
+	}
+
+	changes, err := diff.Values(expected.Definition, draft.Definition)
+	if err != nil {
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return err
+	}
+
+	if len(changes) > 0 {
+		sl.GetPrinter().ErrPrintf("draft offer differs from %s:\n", expectFilePath)
+		args.PrintChanges(sl, changes)
+		err := fmt.Errorf("draft offer does not match the expected offer in %s", expectFilePath)
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return err
+	}
+	return nil
+}
+
+// printProductionDiff prints the changes from the definition in the Production slot to the definition of the draft.
+// If the offer has never been published, the changes are from an empty definition.
+func printProductionDiff(ctx context.Context, sl service.CommandServicer, client service.CloudPartnerServicer, draft *partner.Offer) error {
+	var prodDefinition partner.OfferDefinition
+	prod, err := client.GetOfferBySlot(ctx, partner.ShowOfferBySlotParams{
+		PublisherID: draft.PublisherID,
+		OfferID:     draft.ID,
+		SlotID:      partner.ProductionSlot,
+	})
+	switch {
+	case err == partner.ErrOfferNotFound:
+		sl.GetPrinter().ErrPrintf("the offer is not in the %s slot, showing the full draft\n", partner.ProductionSlot)
+	case err != nil:
+		sl.GetPrinter().ErrPrintf("unable to get the %s slot: %v\n", partner.ProductionSlot, err)
+		return err
+	default:
+		prodDefinition = prod.Definition
+	}
+
+	changes, err := diff.Values(prodDefinition, draft.Definition)
+	if err != nil {
+		sl.GetPrinter().ErrPrintf("%v\n", err)
+		return err
+	}
+
+	if len(changes) == 0 {
+		sl.GetPrinter().ErrPrintf("no changes from the %s slot to the Draft slot\n", partner.ProductionSlot)
+		return nil
+	}
+
+	sl.GetPrinter().ErrPrintf("changes from the %s slot to the Draft slot:\n", partner.ProductionSlot)
+	args.PrintChanges(sl, changes)
+	return nil
+}
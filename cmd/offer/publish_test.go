@@ -2,6 +2,7 @@ package offer
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,6 +13,23 @@ import (
 	"github.com/devigned/pub/pkg/partner"
 )
 
+// newPublishMocks returns mocks for a draft offer with no running operations and a production slot which matches the
+// draft
+func newPublishMocks(draft *partner.Offer) (*test.RegistryMock, *test.CloudPartnerServiceMock, *test.PrinterMock) {
+	rm, svcMock, prtMock := test.NewOfferRegistryMocks(draft)
+	svcMock.On("ListOperations", mock.Anything, partner.ListOperationsParams{
+		PublisherID:    draft.PublisherID,
+		OfferID:        draft.ID,
+		FilteredStatus: "running",
+	}).Return([]partner.Operation{}, nil)
+	svcMock.On("GetOfferBySlot", mock.Anything, partner.ShowOfferBySlotParams{
+		PublisherID: draft.PublisherID,
+		OfferID:     draft.ID,
+		SlotID:      "Production",
+	}).Return(test.NewMarketplaceVMOffer(), nil)
+	return rm, svcMock, prtMock
+}
+
 func TestPublishCommand_FailOnInsufficientArgs(t *testing.T) {
 	test.VerifyFailsOnArgs(t, newPublishCommand)
 	test.VerifyFailsOnArgs(t, newPublishCommand, "-p", "foo")
@@ -22,21 +40,138 @@ func TestPublishCommand_FailOnCloudPartnerServiceError(t *testing.T) {
 }
 
 func TestPublishCommand_FailOnPublishError(t *testing.T) {
+	draft := test.NewMarketplaceVMOffer()
 	boomErr := errors.New("boom")
-	svcMock := new(test.CloudPartnerServiceMock)
+	rm, svcMock, prtMock := newPublishMocks(draft)
 	svcMock.On("PublishOffer", mock.Anything, partner.PublishOfferParams{
-		PublisherID:        "foo",
-		OfferID:            "bar",
+		PublisherID:        draft.PublisherID,
+		OfferID:            draft.ID,
 		NotificationEmails: "joe@microsoft.com,jane@microsoft.com",
 	}).Return("", boomErr)
+
+	cmd, err := test.QuietCommand(newPublishCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "-e", "joe@microsoft.com,jane@microsoft.com", "--yes"})
+	assert.Error(t, cmd.Execute())
+	prtMock.AssertCalled(t, "ErrPrintf", "%v\n", []interface{}{boomErr})
+}
+
+func TestPublishCommand_FailOnDraftWithoutImageVersions(t *testing.T) {
+	draft := test.NewMarketplaceVMOffer()
+	draft.Definition.Plans[0].PlanVirtualMachineDetail.VMImages = nil
+	rm, svcMock, _ := newPublishMocks(draft)
+
+	cmd, err := test.QuietCommand(newPublishCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--yes"})
+	assert.Error(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "ListOperations", mock.Anything, mock.Anything)
+	svcMock.AssertNotCalled(t, "PublishOffer", mock.Anything, mock.Anything)
+}
+
+func TestPublishCommand_FailOnRunningOperation(t *testing.T) {
+	draft := test.NewMarketplaceVMOffer()
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(draft, nil)
+	svcMock.On("ListOperations", mock.Anything, mock.Anything).Return([]partner.Operation{
+		{Entity: partner.Entity{ID: "op1"}, SubmissionState: "running"},
+	}, nil)
 	prtMock := new(test.PrinterMock)
-	prtMock.On("ErrPrintf", "%v\n", []interface{}{boomErr}).Return(nil)
+	prtMock.On("ErrPrintf", mock.Anything, mock.Anything).Return(nil)
 	rm := new(test.RegistryMock)
 	rm.On("GetCloudPartnerService").Return(svcMock, nil)
 	rm.On("GetPrinter").Return(prtMock)
 
 	cmd, err := test.QuietCommand(newPublishCommand(rm))
 	require.NoError(t, err)
-	cmd.SetArgs([]string{"-p", "foo", "-o", "bar", "-e", "joe@microsoft.com,jane@microsoft.com"})
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--yes"})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "op1")
+	svcMock.AssertNotCalled(t, "PublishOffer", mock.Anything, mock.Anything)
+}
+
+func TestPublishCommand_FailOnExpectFileMismatch(t *testing.T) {
+	expected := test.NewMarketplaceVMOffer()
+	expected.Definition.Plans[0].PlanVirtualMachineDetail.SKUTitle = "reviewed title"
+	fName, del := test.NewTmpFileFromOffer(t, "offer", expected)
+	defer del()
+
+	draft := test.NewMarketplaceVMOffer()
+	rm, svcMock, prtMock := newPublishMocks(draft)
+
+	cmd, err := test.QuietCommand(newPublishCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--expect-file", fName, "--yes"})
+	assert.Error(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "PublishOffer", mock.Anything, mock.Anything)
+	prtMock.AssertCalled(t, "ErrPrintf", "  %v\n", mock.Anything)
+}
+
+func TestPublishCommand_FailWhenNotConfirmed(t *testing.T) {
+	draft := test.NewMarketplaceVMOffer()
+	rm, svcMock, _ := newPublishMocks(draft)
+
+	cmd, err := test.QuietCommand(newPublishCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader("n\n"))
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID})
 	assert.Error(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "PublishOffer", mock.Anything, mock.Anything)
+}
+
+func TestPublishCommand_SuccessWithConfirmation(t *testing.T) {
+	draft := test.NewMarketplaceVMOffer()
+	fName, del := test.NewTmpFileFromOffer(t, "offer", draft)
+	defer del()
+
+	rm, svcMock, prtMock := newPublishMocks(draft)
+	svcMock.On("PublishOffer", mock.Anything, partner.PublishOfferParams{
+		PublisherID: draft.PublisherID,
+		OfferID:     draft.ID,
+	}).Return("operation/location", nil)
+	prtMock.On("Print", "operation/location").Return(nil)
+
+	cmd, err := test.QuietCommand(newPublishCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader("yes\n"))
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--expect-file", fName})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "ErrPrintf", "no changes from the %s slot to the Draft slot\n", []interface{}{"Production"})
+	prtMock.AssertCalled(t, "Print", "operation/location")
+}
+
+func TestPublishCommand_ProductionSlot(t *testing.T) {
+	boomErr := errors.New("boom")
+	cases := map[string]struct {
+		err     error
+		publish bool
+	}{
+		"not found": {err: partner.ErrOfferNotFound, publish: true},
+		"error":     {err: boomErr},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			draft := test.NewMarketplaceVMOffer()
+			rm, svcMock, prtMock := test.NewRegistryMocks()
+			svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(draft, nil)
+			svcMock.On("ListOperations", mock.Anything, mock.Anything).Return([]partner.Operation{}, nil)
+			svcMock.On("GetOfferBySlot", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), c.err)
+			svcMock.On("PublishOffer", mock.Anything, mock.Anything).Return("operation/location", nil)
+
+			cmd, err := test.QuietCommand(newPublishCommand(rm))
+			require.NoError(t, err)
+			cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--yes"})
+			if c.publish {
+				require.NoError(t, cmd.Execute())
+				svcMock.AssertCalled(t, "PublishOffer", mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.Error(t, cmd.Execute())
+			prtMock.AssertCalled(t, "ErrPrintf", "unable to get the %s slot: %v\n", []interface{}{partner.ProductionSlot, boomErr})
+			svcMock.AssertNotCalled(t, "PublishOffer", mock.Anything, mock.Anything)
+		})
+	}
 }
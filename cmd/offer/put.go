@@ -3,23 +3,28 @@ package offer
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"strings"
+	"io"
 
-	"github.com/Jeffail/gabs"
 	"github.com/spf13/cobra"
 
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/document"
+	"github.com/devigned/pub/pkg/layout"
 	"github.com/devigned/pub/pkg/service"
 
 	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/schema"
 	"github.com/devigned/pub/pkg/xcobra"
 )
 
 type (
 	putOfferArgs struct {
 		OfferFilePath string
-		Set           []string
+		Set           args.SetArgs
+		Template      args.TemplateArgs
+		Validate      args.ValidateArgs
+		Policy        policyArgs
+		Backup        args.BackupArgs
 	}
 )
 
@@ -29,35 +34,16 @@ func newPutCommand(sl service.CommandServicer) (*cobra.Command, error) {
 		Use:   "put",
 		Short: "create or update an offer",
 		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
-			bits, err := ioutil.ReadFile(oArgs.OfferFilePath)
+			bits, err := readOfferFile(sl, oArgs, cmd.InOrStdin())
 			if err != nil {
 				sl.GetPrinter().ErrPrintf("%v\n", err)
 				return err
 			}
 
-			if len(oArgs.Set) > 0 {
-				parsedJSON, err := gabs.ParseJSON(bits)
-				if err != nil {
-					sl.GetPrinter().ErrPrintf("%v\n", err)
-					return err
-				}
-
-				for _, item := range oArgs.Set {
-					splits := strings.Split(item, "=")
-					if len(splits) != 2 {
-						err := fmt.Errorf("the set item %s was not in key.keypart=value format", item)
-						sl.GetPrinter().ErrPrintf("%v\n", err)
-						return err
-					}
-
-					_, err := parsedJSON.SetP(splits[1], splits[0])
-					if err != nil {
-						sl.GetPrinter().ErrPrintf("could not add item '%s'", item)
-						return err
-					}
-				}
-
-				bits = parsedJSON.Bytes()
+			bits, err = oArgs.Set.ApplyJSON(bits)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to apply set values: %v\n", err)
+				return err
 			}
 
 			var offer partner.Offer
@@ -66,12 +52,37 @@ func newPutCommand(sl service.CommandServicer) (*cobra.Command, error) {
 				return err
 			}
 
+			if file := oArgs.Policy.file(); file != "" {
+				if err := enforcePolicy(sl, file, &offer); err != nil {
+					return err
+				}
+			}
+
 			client, err := sl.GetCloudPartnerService()
 			if err != nil {
 				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
 				return err
 			}
 
+			if !oArgs.Backup.Skip {
+				current, err := client.GetOffer(ctx, partner.ShowOfferParams{
+					PublisherID: offer.PublisherID,
+					OfferID:     offer.ID,
+				})
+				switch {
+				case err == partner.ErrOfferNotFound:
+					// a new offer has nothing to back up
+				case err != nil:
+					sl.GetPrinter().ErrPrintf("unable to get offer: %v\n", err)
+					return err
+				default:
+					if err := oArgs.Backup.Save(current); err != nil {
+						sl.GetPrinter().ErrPrintf("%v\n", err)
+						return err
+					}
+				}
+			}
+
 			updatedOffer, err := client.PutOffer(ctx, &offer)
 			if err != nil {
 				sl.GetPrinter().ErrPrintf("%v\n", err)
@@ -82,10 +93,47 @@ func newPutCommand(sl service.CommandServicer) (*cobra.Command, error) {
 		}),
 	}
 
-	cmd.Flags().StringVarP(&oArgs.OfferFilePath, "offer-file", "o", "", "File path to the JSON file containing the offer")
+	cmd.Flags().StringVarP(&oArgs.OfferFilePath, "offer-file", "o", "", "File path to the JSON or YAML file containing the offer, - for stdin, or a directory in the exploded layout")
 	if err := cmd.MarkFlagRequired("offer-file"); err != nil {
 		return cmd, err
 	}
-	cmd.Flags().StringArrayVar(&oArgs.Set, "set", []string{}, "set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	args.BindSetArgs(cmd, &oArgs.Set)
+	args.BindTemplateArgs(cmd, &oArgs.Template)
+	args.BindValidateArgs(cmd, &oArgs.Validate)
+	bindPolicyArgs(cmd, &oArgs.Policy)
+	args.BindBackupArgs(cmd, &oArgs.Backup)
 	return cmd, nil
 }
+
+// readOfferFile renders, validates and reads the offer from a file, stdin or a directory in the exploded layout as JSON
+func readOfferFile(sl service.CommandServicer, oArgs putOfferArgs, stdin io.Reader) ([]byte, error) {
+	if layout.IsDir(oArgs.OfferFilePath) {
+		values, err := oArgs.Template.Values(oArgs.OfferFilePath, stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		return layout.ReadDir(oArgs.OfferFilePath, func(path string, bits []byte) ([]byte, error) {
+			bits, err := oArgs.Template.Render(path, bits, values)
+			if err != nil {
+				return nil, err
+			}
+
+			s := schema.Offer()
+			if layout.IsSKUFile(path) {
+				s = schema.Plan()
+			}
+
+			if err := oArgs.Validate.Validator(path, s, sl).ValidateFile(path, bits); err != nil {
+				return nil, err
+			}
+			return bits, nil
+		})
+	}
+
+	docs, err := oArgs.Template.ReadValidJSON(oArgs.OfferFilePath, stdin, oArgs.Validate.Validator(oArgs.OfferFilePath, schema.Offer(), sl))
+	if err != nil {
+		return nil, err
+	}
+	return document.Single(oArgs.OfferFilePath, docs)
+}
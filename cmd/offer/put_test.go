@@ -4,13 +4,20 @@ import (
 	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 
 	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/backup"
+	"github.com/devigned/pub/pkg/jsonpath"
+	"github.com/devigned/pub/pkg/layout"
 	"github.com/devigned/pub/pkg/partner"
 )
 
@@ -32,6 +39,7 @@ func TestPutCommand_FailOnPutOfferError(t *testing.T) {
 	boomErr := errors.New("boom")
 	svcMock := new(test.CloudPartnerServiceMock)
 	svcMock.On("PutOffer", mock.Anything, mock.Anything).Return(new(partner.Offer), boomErr)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), partner.ErrOfferNotFound)
 	prtMock := new(test.PrinterMock)
 	prtMock.On("ErrPrintf", "%v\n", []interface{}{boomErr}).Return(nil)
 	rm := new(test.RegistryMock)
@@ -55,6 +63,7 @@ func TestPutCommand_NoMergeOfferSuccess(t *testing.T) {
 
 	svcMock := new(test.CloudPartnerServiceMock)
 	svcMock.On("PutOffer", mock.Anything, &offer).Return(&offer, nil)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), partner.ErrOfferNotFound)
 	prtMock := new(test.PrinterMock)
 	prtMock.On("Print", &offer).Return(nil)
 	rm := new(test.RegistryMock)
@@ -76,6 +85,7 @@ func TestPutCommand_MergeOfferSuccess(t *testing.T) {
 	offer.Definition.DisplayText = "foo"
 	svcMock := new(test.CloudPartnerServiceMock)
 	svcMock.On("PutOffer", mock.Anything, offer).Return(offer, nil)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), partner.ErrOfferNotFound)
 	prtMock := new(test.PrinterMock)
 
 	prtMock.On("Print", offer).Return(nil)
@@ -90,3 +100,279 @@ func TestPutCommand_MergeOfferSuccess(t *testing.T) {
 	offer.Definition.DisplayText = "foo"
 	prtMock.AssertCalled(t, "Print", offer)
 }
+
+func TestPutCommand_FailOnPolicyViolation(t *testing.T) {
+	fName, del := test.NewTmpOfferFile(t, "offer")
+	defer del()
+	pName, delPolicy := test.NewTmpPolicyFile(t, "policy", `
+rules:
+  - id: offer-id
+    selector: $.id
+    assert:
+      regex: ^contoso-
+`)
+	defer delPolicy()
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-o", fName, "--policy-file", pName})
+	assert.Error(t, cmd.Execute())
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestPutCommand_SetTypedValuesSuccess(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	fName, del := test.NewTmpFileFromOffer(t, "offer", offer)
+	defer del()
+
+	offer.Definition.DisplayText = "a=b"
+	offer.Definition.Plans[0].PlanVirtualMachineDetail.HideSKUForSolutionTemplate = to.BoolPtr(false)
+	offer.Definition.Plans[0].PlanVirtualMachineDetail.RecommendedVirtualMachineSizes = []string{"ds2-standard-v2"}
+	offer.Definition.Plans[0].PlanVirtualMachineDetail.OSType = ""
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("PutOffer", mock.Anything, offer).Return(offer, nil)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), partner.ErrOfferNotFound)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", offer).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-o", fName,
+		"--set", "definition.displayText=a=b",
+		"--set", "definition.plans[planId=planId_one].microsoft-azure-virtualmachines.hideSKUForSolutionTemplate=false",
+		"--set-json", `definition.plans[0].microsoft-azure-virtualmachines.recommendedVMSizes=["ds2-standard-v2"]`,
+		"--unset", "definition.plans[planId=planId_one].microsoft-azure-virtualmachines.osType",
+	})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", offer)
+}
+
+func TestPutCommand_FailOnInvalidSet(t *testing.T) {
+	fName, del := test.NewTmpOfferFile(t, "offer")
+	defer del()
+
+	cases := [][]string{
+		{"--set", "definition.displayText"},
+		{"--set-json", "definition.displayText={"},
+		{"--set", "definition.plans[5].planId=foo"},
+		{"--unset", "definition.missing"},
+		{"--set-file", "definition.displayText=/does/not/exist"},
+	}
+
+	for _, c := range cases {
+		prtMock := new(test.PrinterMock)
+		prtMock.On("ErrPrintf", "unable to apply set values: %v\n", mock.Anything).Return(nil)
+		rm := new(test.RegistryMock)
+		rm.On("GetPrinter").Return(prtMock)
+
+		cmd, err := test.QuietCommand(newPutCommand(rm))
+		require.NoError(t, err)
+		cmd.SetArgs(append([]string{"-o", fName}, c...))
+		assert.Error(t, cmd.Execute(), c)
+		rm.AssertNotCalled(t, "GetCloudPartnerService")
+	}
+}
+
+func TestPutCommand_YAMLFromStdinSuccess(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	offer.Definition.DisplayText = "from yaml"
+	doc, err := jsonpath.ToDocument(offer)
+	require.NoError(t, err)
+	bits, err := yaml.Marshal(doc)
+	require.NoError(t, err)
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("PutOffer", mock.Anything, offer).Return(offer, nil)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), partner.ErrOfferNotFound)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", offer).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader("# reviewed offer\n" + string(bits)))
+	cmd.SetArgs([]string{"-o", "-"})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", offer)
+}
+
+func TestPutCommand_TemplateSuccess(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	offer.PublisherID = "contoso-dev"
+	offer.Definition.Plans[0].PlanVirtualMachineDetail.VMImages["2019.10.11"] = partner.VirtualMachineImage{OSVHDURL: "https://contoso.com/dev.vhd"}
+	bits, err := partner.JSONMarshalWithNoHTMLEscaping(offer)
+	require.NoError(t, err)
+	tmpl := strings.Replace(string(bits), `"contoso-dev"`, `"${publisher}"`, 1)
+	tmpl = strings.Replace(tmpl, "https://contoso.com/dev.vhd", "{{ .vhd.url }}", 1)
+	fName, del := test.NewTmpFileWithContent(t, "offer*.json", tmpl)
+	defer del()
+	values, delValues := test.NewTmpFileWithContent(t, "values*.yaml", "publisher: contoso-prod\nvhd:\n  url: https://contoso.com/dev.vhd\n")
+	defer delValues()
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("PutOffer", mock.Anything, offer).Return(offer, nil)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), partner.ErrOfferNotFound)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", offer).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-o", fName, "--values", values, "--var", "publisher=contoso-dev", "--strict-vars"})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", offer)
+}
+
+func TestPutCommand_FailOnSchemaViolation(t *testing.T) {
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader("id: widget\ndefinition:\n  plans:\n    - planId: basic\n      regions: us\n"))
+	cmd.SetArgs([]string{"-o", "-"})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stdin:5:7: $.definition.plans[0].regions: expected array or null, but got string")
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestPutCommand_FailOnSchemaViolationInDirectory(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	offer := test.NewMarketplaceVMOffer()
+	require.NoError(t, layout.WriteDir(dir, offer, false))
+	skuPath := filepath.Join(dir, layout.SKUDirName, offer.Definition.Plans[0].ID+".yaml")
+	require.NoError(t, ioutil.WriteFile(skuPath, []byte("planId: basic\nmicrosoft-azure-virtualmachines.hideSKUForSolutionTemplate: maybe\n"), 0644))
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-o", dir})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), skuPath+":2:1:")
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestPutCommand_BacksUpExistingOffer(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	fName, del := test.NewTmpFileFromOffer(t, "offer", offer)
+	defer del()
+
+	dir, delDir := test.NewTmpDir(t)
+	defer delDir()
+
+	current := test.NewMarketplaceVMOffer()
+	current.Definition.DisplayText = "before the put"
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, partner.ShowOfferParams{
+		PublisherID: offer.PublisherID,
+		OfferID:     offer.ID,
+	}).Return(current, nil)
+	svcMock.On("PutOffer", mock.Anything, mock.Anything).Return(offer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-o", fName, "--backup-dir", dir})
+	require.NoError(t, cmd.Execute())
+
+	store := &backup.Store{Dir: dir}
+	backups, err := store.List(offer.PublisherID, offer.ID)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	saved, err := store.Load(offer.PublisherID, offer.ID, backups[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "before the put", saved.Definition.DisplayText)
+}
+
+func TestPutCommand_FailOnGetOfferErrorBeforeBackup(t *testing.T) {
+	fName, del := test.NewTmpOfferFile(t, "offer")
+	defer del()
+
+	boomErr := errors.New("boom")
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), boomErr)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "unable to get offer: %v\n", []interface{}{boomErr}).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-o", fName})
+	assert.Error(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "PutOffer", mock.Anything, mock.Anything)
+}
+
+func TestPutCommand_NoBackupSkipsGetOffer(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	fName, del := test.NewTmpFileFromOffer(t, "offer", offer)
+	defer del()
+
+	rm, svcMock, _ := test.NewRegistryMocks()
+	svcMock.On("PutOffer", mock.Anything, mock.Anything).Return(offer, nil)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-o", fName, "--no-backup"})
+	require.NoError(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "GetOffer", mock.Anything, mock.Anything)
+	svcMock.AssertCalled(t, "PutOffer", mock.Anything, mock.Anything)
+}
+
+func TestPutCommand_TemplateDirectoryWithValuesFromStdin(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	offer := test.NewMarketplaceVMOffer()
+	require.NoError(t, layout.WriteDir(dir, offer, false))
+	rm, svcMock, _ := test.NewRegistryMocks()
+	svcMock.On("PutOffer", mock.Anything, mock.Anything).Return(offer, nil)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader("unused: value\n"))
+	cmd.SetArgs([]string{"-o", dir, "--values", "-", "--no-backup"})
+	require.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOffer", mock.Anything, mock.Anything)
+}
+
+func TestPutCommand_FailOnOfferAndValuesFromStdin(t *testing.T) {
+	rm, _, _ := test.NewRegistryMocks()
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader("id: test\n"))
+	cmd.SetArgs([]string{"-o", "-", "--values", "-"})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stdin can only be read once")
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
@@ -0,0 +1,206 @@
+package offer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/diff"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+var (
+	// rollbackSlots are the slots the draft of an offer can be restored to
+	rollbackSlots = []string{partner.PreviewSlot, partner.ProductionSlot}
+)
+
+type (
+	rollbackOfferArgs struct {
+		Publisher          string
+		Offer              string
+		ToVersion          int
+		ToSlot             string
+		Yes                bool
+		Publish            bool
+		NotificationEmails string
+		Policy             policyArgs
+		Backup             args.BackupArgs
+	}
+)
+
+func newRollbackCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs rollbackOfferArgs
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "restore the draft of an offer to a previous version or slot",
+		Long: `Restore the definition of the draft of an offer to the definition of a previous version (--to-version) or of
+the offer in a slot (--to-slot), such as Production. Fields set by the Cloud Partner Portal, like the version and
+ETag, are kept from the draft. The changes to the draft are shown for confirmation and the draft is put with the ETag
+of the fetched draft, so concurrent changes are not overwritten. With --publish, the restored draft is published, even
+if the draft already matched. It must first pass the same checks as offers publish, which run before the draft is put.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, _ []string) error {
+			if (oArgs.ToVersion > 0) == (oArgs.ToSlot != "") {
+				err := errors.New("specify either --to-version or --to-slot")
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if oArgs.ToSlot != "" {
+				slot, err := parseRollbackSlot(oArgs.ToSlot)
+				if err != nil {
+					sl.GetPrinter().ErrPrintf("%v\n", err)
+					return err
+				}
+				oArgs.ToSlot = slot
+			}
+
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			draft, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v\n", err)
+				return err
+			}
+
+			snapshot, err := getSnapshot(ctx, client, oArgs)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get %s: %v\n", oArgs.target(), err)
+				return err
+			}
+
+			restored := *draft
+			restored.Definition = snapshot.Definition
+			changes, err := diff.Values(draft.Definition, restored.Definition)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if len(changes) == 0 {
+				sl.GetPrinter().ErrPrintf("the Draft slot already matches %s\n", oArgs.target())
+				if !oArgs.Publish {
+					return sl.GetPrinter().Print(draft)
+				}
+			} else {
+				sl.GetPrinter().ErrPrintf("changes to the Draft slot:\n")
+				args.PrintChanges(sl, changes)
+
+				if !oArgs.Yes {
+					ok, err := args.Confirm(sl, cmd.InOrStdin(), fmt.Sprintf("Restore offer %s/%s to %s? [y/N]: ", oArgs.Publisher, oArgs.Offer, oArgs.target()))
+					if err != nil {
+						sl.GetPrinter().ErrPrintf("%v\n", err)
+						return err
+					}
+
+					if !ok {
+						err := errors.New("rollback was not confirmed")
+						sl.GetPrinter().ErrPrintf("%v\n", err)
+						return err
+					}
+				}
+			}
+
+			if oArgs.Publish {
+				checks := publishChecks{Yes: oArgs.Yes, Policy: oArgs.Policy}
+				if err := checkPublish(ctx, cmd, sl, client, &restored, checks); err != nil {
+					return err
+				}
+			}
+
+			updated := draft
+			if len(changes) > 0 {
+				if err := oArgs.Backup.Save(draft); err != nil {
+					sl.GetPrinter().ErrPrintf("%v\n", err)
+					return err
+				}
+
+				updated, err = client.PutOfferIfMatch(ctx, &restored)
+				if err != nil {
+					sl.GetPrinter().ErrPrintf("unable to put offer: %v\n", err)
+					return err
+				}
+			}
+
+			if !oArgs.Publish {
+				return sl.GetPrinter().Print(updated)
+			}
+
+			opLocation, err := client.PublishOffer(ctx, partner.PublishOfferParams{
+				NotificationEmails: oArgs.NotificationEmails,
+				OfferID:            oArgs.Offer,
+				PublisherID:        oArgs.Publisher,
+			})
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			return sl.GetPrinter().Print(opLocation)
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().IntVar(&oArgs.ToVersion, "to-version", 0, "Version of the offer to restore the draft to")
+	cmd.Flags().StringVar(&oArgs.ToSlot, "to-slot", "", fmt.Sprintf("Slot of the offer to restore the draft to, can be one of: %s", strings.Join(rollbackSlots, ", ")))
+	cmd.Flags().BoolVarP(&oArgs.Yes, "yes", "y", false, "(optional) Restore without asking for confirmation")
+	cmd.Flags().BoolVar(&oArgs.Publish, "publish", false, "(optional) Publish the offer after the draft is restored")
+	cmd.Flags().StringVarP(&oArgs.NotificationEmails, "notification-emails", "e", "", "(optional) Comma separated list of emails to notify when publication completes.")
+	bindPolicyArgs(cmd, &oArgs.Policy)
+	args.BindBackupArgs(cmd, &oArgs.Backup)
+	return cmd, nil
+}
+
+// getSnapshot fetches the version or slot of the offer to restore
+func getSnapshot(ctx context.Context, client service.CloudPartnerServicer, oArgs rollbackOfferArgs) (*partner.Offer, error) {
+	if oArgs.ToVersion > 0 {
+		return client.GetOfferByVersion(ctx, partner.ShowOfferByVersionParams{
+			PublisherID: oArgs.Publisher,
+			OfferID:     oArgs.Offer,
+			Version:     oArgs.ToVersion,
+		})
+	}
+
+	return client.GetOfferBySlot(ctx, partner.ShowOfferBySlotParams{
+		PublisherID: oArgs.Publisher,
+		OfferID:     oArgs.Offer,
+		SlotID:      oArgs.ToSlot,
+	})
+}
+
+// parseRollbackSlot returns the slot the draft can be restored to, matching the name without regard to case
+func parseRollbackSlot(name string) (string, error) {
+	for _, slot := range rollbackSlots {
+		if strings.EqualFold(name, slot) {
+			return slot, nil
+		}
+	}
+	return "", fmt.Errorf("--to-slot %q must be one of: %s", name, strings.Join(rollbackSlots, ", "))
+}
+
+// target describes the version or slot the draft is restored to
+func (oArgs rollbackOfferArgs) target() string {
+	if oArgs.ToVersion > 0 {
+		return fmt.Sprintf("version %d", oArgs.ToVersion)
+	}
+	return fmt.Sprintf("the %s slot", oArgs.ToSlot)
+}
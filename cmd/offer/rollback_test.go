@@ -0,0 +1,194 @@
+package offer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+// newRollbackMocks returns mocks for a draft at version 3 and a snapshot at version 1 and in the Production slot
+func newRollbackMocks(draft, snapshot *partner.Offer) (*test.RegistryMock, *test.CloudPartnerServiceMock, *test.PrinterMock) {
+	rm, svcMock, prtMock := test.NewOfferRegistryMocks(draft)
+	svcMock.On("GetOfferByVersion", mock.Anything, partner.ShowOfferByVersionParams{
+		PublisherID: draft.PublisherID,
+		OfferID:     draft.ID,
+		Version:     1,
+	}).Return(snapshot, nil)
+	svcMock.On("GetOfferBySlot", mock.Anything, partner.ShowOfferBySlotParams{
+		PublisherID: draft.PublisherID,
+		OfferID:     draft.ID,
+		SlotID:      partner.ProductionSlot,
+	}).Return(snapshot, nil)
+	svcMock.On("PutOfferIfMatch", mock.Anything, mock.Anything).Return(draft, nil)
+	return rm, svcMock, prtMock
+}
+
+func newRollbackOffers() (*partner.Offer, *partner.Offer) {
+	draft := newVersionedOffer(3, "new display text")
+	draft.Etag = "draft-etag"
+	draft.Status = "neverPublished"
+	snapshot := newVersionedOffer(1, "old display text")
+	snapshot.Etag = "snapshot-etag"
+	snapshot.Status = "published"
+	return draft, snapshot
+}
+
+func TestRollbackCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newRollbackCommand)
+	test.VerifyFailsOnArgs(t, newRollbackCommand, "-p", "foo")
+}
+
+func TestRollbackCommand_FailOnCloudPartnerError(t *testing.T) {
+	test.VerifyCloudPartnerServiceCommand(t, newRollbackCommand, "-p", "foo", "-o", "bar", "--to-version", "1")
+}
+
+func TestRollbackCommand_FailOnInvalidTarget(t *testing.T) {
+	draft, snapshot := newRollbackOffers()
+	cases := map[string][]string{
+		"neither":    {},
+		"both":       {"--to-version", "1", "--to-slot", partner.ProductionSlot},
+		"draft slot": {"--to-slot", "Draft"},
+		"typo":       {"--to-slot", "Prod"},
+	}
+
+	for name, flags := range cases {
+		t.Run(name, func(t *testing.T) {
+			rm, svcMock, _ := newRollbackMocks(draft, snapshot)
+			cmd, err := test.QuietCommand(newRollbackCommand(rm))
+			require.NoError(t, err)
+			cmd.SetArgs(append([]string{"-p", draft.PublisherID, "-o", draft.ID, "--yes"}, flags...))
+			assert.Error(t, cmd.Execute())
+			svcMock.AssertNotCalled(t, "GetOffer", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRollbackCommand_FailOnSnapshotError(t *testing.T) {
+	draft, _ := newRollbackOffers()
+	boomErr := errors.New("boom")
+	rm, svcMock, prtMock := test.NewRegistryMocks()
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(draft, nil)
+	svcMock.On("GetOfferByVersion", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), boomErr)
+
+	cmd, err := test.QuietCommand(newRollbackCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--to-version", "1", "--yes"})
+	assert.Error(t, cmd.Execute())
+	prtMock.AssertCalled(t, "ErrPrintf", "unable to get %s: %v\n", []interface{}{"version 1", boomErr})
+	svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+}
+
+func TestRollbackCommand_FailWhenNotConfirmed(t *testing.T) {
+	draft, snapshot := newRollbackOffers()
+	rm, svcMock, _ := newRollbackMocks(draft, snapshot)
+
+	cmd, err := test.QuietCommand(newRollbackCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader("n\n"))
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--to-version", "1"})
+	assert.Error(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+}
+
+func TestRollbackCommand_SkipPutWhenUnchanged(t *testing.T) {
+	draft, _ := newRollbackOffers()
+	snapshot := newVersionedOffer(1, draft.Definition.DisplayText)
+	rm, svcMock, prtMock := newRollbackMocks(draft, snapshot)
+
+	cmd, err := test.QuietCommand(newRollbackCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--to-version", "1"})
+	require.NoError(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+	prtMock.AssertCalled(t, "Print", draft)
+}
+
+func TestRollbackCommand_PublishWhenUnchanged(t *testing.T) {
+	draft, _ := newRollbackOffers()
+	snapshot := newVersionedOffer(1, draft.Definition.DisplayText)
+	rm, svcMock, prtMock := newRollbackMocks(draft, snapshot)
+	svcMock.On("ListOperations", mock.Anything, mock.Anything).Return([]partner.Operation{}, nil)
+	svcMock.On("PublishOffer", mock.Anything, mock.Anything).Return("opLocation", nil)
+
+	cmd, err := test.QuietCommand(newRollbackCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--to-version", "1", "--yes", "--publish"})
+	require.NoError(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+	svcMock.AssertCalled(t, "ListOperations", mock.Anything, mock.Anything)
+	prtMock.AssertCalled(t, "Print", "opLocation")
+}
+
+func TestRollbackCommand_Success(t *testing.T) {
+	cases := map[string][]string{
+		"version": {"--to-version", "1"},
+		"slot":    {"--to-slot", partner.ProductionSlot},
+		"case":    {"--to-slot", "production"},
+	}
+
+	for name, flags := range cases {
+		t.Run(name, func(t *testing.T) {
+			draft, snapshot := newRollbackOffers()
+			rm, svcMock, prtMock := newRollbackMocks(draft, snapshot)
+
+			cmd, err := test.QuietCommand(newRollbackCommand(rm))
+			require.NoError(t, err)
+			cmd.SetIn(strings.NewReader("y\n"))
+			cmd.SetArgs(append([]string{"-p", draft.PublisherID, "-o", draft.ID}, flags...))
+			require.NoError(t, cmd.Execute())
+
+			svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, mock.MatchedBy(func(offer *partner.Offer) bool {
+				return offer.Definition.DisplayText == "old display text" &&
+					offer.Version == 3 && offer.Etag == "draft-etag" && offer.Status == "neverPublished"
+			}))
+			svcMock.AssertNotCalled(t, "PublishOffer", mock.Anything, mock.Anything)
+			prtMock.AssertCalled(t, "Print", draft)
+		})
+	}
+}
+
+func TestRollbackCommand_FailToPublishOnRunningOperation(t *testing.T) {
+	draft, snapshot := newRollbackOffers()
+	rm, svcMock, _ := newRollbackMocks(draft, snapshot)
+	svcMock.On("ListOperations", mock.Anything, mock.Anything).Return([]partner.Operation{
+		{Entity: partner.Entity{ID: "op1"}, SubmissionState: runningStatus},
+	}, nil)
+
+	cmd, err := test.QuietCommand(newRollbackCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--to-version", "1", "--yes", "--publish"})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "op1")
+	svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+	svcMock.AssertNotCalled(t, "PublishOffer", mock.Anything, mock.Anything)
+}
+
+func TestRollbackCommand_SuccessWithPublish(t *testing.T) {
+	draft, snapshot := newRollbackOffers()
+	rm, svcMock, prtMock := newRollbackMocks(draft, snapshot)
+	svcMock.On("ListOperations", mock.Anything, partner.ListOperationsParams{
+		PublisherID:    draft.PublisherID,
+		OfferID:        draft.ID,
+		FilteredStatus: runningStatus,
+	}).Return([]partner.Operation{}, nil)
+	svcMock.On("PublishOffer", mock.Anything, partner.PublishOfferParams{
+		PublisherID:        draft.PublisherID,
+		OfferID:            draft.ID,
+		NotificationEmails: "joe@microsoft.com",
+	}).Return("opLocation", nil)
+
+	cmd, err := test.QuietCommand(newRollbackCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--to-version", "1", "--yes", "--publish", "-e", "joe@microsoft.com"})
+	require.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+	prtMock.AssertCalled(t, "Print", "opLocation")
+}
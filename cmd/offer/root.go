@@ -20,7 +20,14 @@ func NewRootCmd(sl service.CommandServicer) (*cobra.Command, error) {
 		newGoLiveCommand,
 		newPublishCommand,
 		newPutCommand,
+		newSetCommand,
+		newPatchCommand,
+		newInitCommand,
+		newHistoryCommand,
+		newRollbackCommand,
 		newStatusCommand,
+		newLintCommand,
+		newAudienceCommand,
 	}
 
 	for _, f := range cmdFuncs {
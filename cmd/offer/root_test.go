@@ -15,7 +15,7 @@ func TestNewRootCmd(t *testing.T) {
 	cmd, err := offer.NewRootCmd(regMock)
 	require.NoError(t, err)
 
-	expected := []string{"list", "put", "show", "live", "status", "publish"}
+	expected := []string{"list", "put", "show", "live", "status", "publish", "lint", "audience", "set", "patch", "init", "history", "rollback"}
 	actual := make([]string, len(cmd.Commands()))
 	for i, c := range cmd.Commands() {
 		actual[i] = c.Name()
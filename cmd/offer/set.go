@@ -0,0 +1,96 @@
+package offer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	setOfferArgs struct {
+		Publisher string
+		Offer     string
+		Set       args.SetArgs
+		Backup    args.BackupArgs
+	}
+)
+
+func newSetCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs setOfferArgs
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "set or remove values in the draft of an offer",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			if oArgs.Set.IsEmpty() {
+				err := errors.New("at least one of --set, --set-string, --set-json, --set-file or --unset must be specified")
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			offer, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v", err)
+				return err
+			}
+
+			bits, err := json.Marshal(offer)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			bits, err = oArgs.Set.ApplyJSON(bits)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to apply set values: %v\n", err)
+				return err
+			}
+
+			var updated partner.Offer
+			if err := json.Unmarshal(bits, &updated); err != nil {
+				sl.GetPrinter().ErrPrintf("unable to unmarshal JSON offer into partner.Offer: %v\n", err)
+				return err
+			}
+
+			if err := oArgs.Backup.Save(offer); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			offer, err = client.PutOfferIfMatch(ctx, &updated)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to put offer: %v", err)
+				return err
+			}
+
+			return sl.GetPrinter().Print(offer)
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	args.BindSetArgs(cmd, &oArgs.Set)
+	args.BindBackupArgs(cmd, &oArgs.Backup)
+	return cmd, nil
+}
@@ -0,0 +1,86 @@
+package offer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/backup"
+)
+
+func TestSetCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newSetCommand)
+	test.VerifyFailsOnArgs(t, newSetCommand, "-p", "foo")
+}
+
+func TestSetCommand_FailOnCloudPartnerServiceError(t *testing.T) {
+	test.VerifyCloudPartnerServiceCommand(t, newSetCommand, "-p", "foo", "-o", "bar", "--set", "definition.displayText=foo")
+}
+
+func TestSetCommand_FailWithoutValues(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	rm, _, _ := newAudienceMocks(offer, nil)
+
+	cmd, err := test.QuietCommand(newSetCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID})
+	assert.Error(t, cmd.Execute())
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestSetCommand_Success(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	updated := test.NewMarketplaceVMOffer()
+	updated.Definition.Plans[0].PlanVirtualMachineDetail.SKUTitle = "Title"
+	updated.Definition.Plans[0].Regions = append(updated.Definition.Plans[0].Regions, "XX")
+	rm, svcMock, prtMock := newAudienceMocks(offer, updated)
+
+	cmd, err := test.QuietCommand(newSetCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID,
+		"--set", "definition.plans[planId=planId_one].microsoft-azure-virtualmachines.skuTitle=Title",
+		"--set", "definition.plans[planId=planId_one].regions[]=XX",
+	})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, updated)
+	prtMock.AssertCalled(t, "Print", updated)
+}
+
+func TestSetCommand_FailOnMissingPath(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	rm, svcMock, _ := newAudienceMocks(offer, nil)
+
+	cmd, err := test.QuietCommand(newSetCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--unset", "definition.plans[planId=missing]"})
+	assert.Error(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+}
+
+func TestSetCommand_BacksUpOffer(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	offer := test.NewMarketplaceVMOffer()
+	offer.Definition.DisplayText = "before the set"
+	updated := test.NewMarketplaceVMOffer()
+	updated.Definition.DisplayText = "after the set"
+	rm, _, _ := newAudienceMocks(offer, updated)
+
+	cmd, err := test.QuietCommand(newSetCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--set", "definition.displayText=after the set", "--backup-dir", dir})
+	require.NoError(t, cmd.Execute())
+
+	store := &backup.Store{Dir: dir}
+	backups, err := store.List(offer.PublisherID, offer.ID)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	saved, err := store.Load(offer.PublisherID, offer.ID, backups[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "before the set", saved.Definition.DisplayText)
+}
@@ -70,7 +70,7 @@ func newShowCommand(sl service.CommandServicer) (*cobra.Command, error) {
 				offer = o
 			}
 
-			return sl.GetPrinter().Print(offer)
+			return sl.GetPrinter().Print(offer.TypedView())
 		}),
 	}
 
@@ -48,7 +48,7 @@ func TestShowCommand_Success(t *testing.T) {
 		OfferID:     offer.ID,
 	}).Return(offer, nil)
 	prtMock := new(test.PrinterMock)
-	prtMock.On("Print", offer).Return(nil)
+	prtMock.On("Print", offer.TypedView()).Return(nil)
 	rm := new(test.RegistryMock)
 	rm.On("GetCloudPartnerService").Return(svcMock, nil)
 	rm.On("GetPrinter").Return(prtMock)
@@ -57,5 +57,5 @@ func TestShowCommand_Success(t *testing.T) {
 	require.NoError(t, err)
 	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID})
 	assert.NoError(t, cmd.Execute())
-	prtMock.AssertCalled(t, "Print", offer)
+	prtMock.AssertCalled(t, "Print", offer.TypedView())
 }
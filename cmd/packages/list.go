@@ -0,0 +1,68 @@
+package packages
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	// listPackagesArgs are the arguments for `packages list` command
+	listPackagesArgs struct {
+		Publisher string
+		Offer     string
+		SKU       string
+	}
+)
+
+func newListCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs listPackagesArgs
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list all package versions for a given plan",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			offer, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to list packages: %v", err)
+				return err
+			}
+
+			var packages partner.ApplicationPackages
+			if plan := offer.GetPlanByID(oArgs.SKU); plan != nil {
+				packages = plan.GetApplicationPackages()
+			}
+
+			return sl.GetPrinter().Print(packages)
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindSKU(cmd, &oArgs.SKU); err != nil {
+		return cmd, err
+	}
+
+	return cmd, nil
+}
@@ -0,0 +1,44 @@
+package packages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestListCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newListCommand)
+	test.VerifyFailsOnArgs(t, newListCommand, "-p", "foo")
+	test.VerifyFailsOnArgs(t, newListCommand, "-p", "foo", "-o", "bar")
+}
+
+func TestListCommand_FailOnCloudPartnerServiceError(t *testing.T) {
+	test.VerifyCloudPartnerServiceCommand(t, newListCommand, "-p", "foo", "-o", "bar", "--sku", "managed")
+}
+
+func TestListCommand_Success(t *testing.T) {
+	offer := test.NewMarketplaceApplicationOffer()
+	sku := offer.Definition.Plans[0]
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, partner.ShowOfferParams{
+		PublisherID: offer.PublisherID,
+		OfferID:     offer.ID,
+	}).Return(offer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", sku.GetApplicationPackages()).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newListCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--sku", sku.ID})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", sku.GetApplicationPackages())
+}
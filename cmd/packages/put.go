@@ -0,0 +1,143 @@
+package packages
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	putPackageArgs struct {
+		Publisher string
+		Offer     string
+		SKU       string
+		Version   args.VersionOrNext
+		Package   partner.ApplicationPackage
+		Backup    args.BackupArgs
+	}
+)
+
+func newPutCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs putPackageArgs
+	cmd := &cobra.Command{
+		Use:   "put",
+		Short: "put a package version for a given Azure Application plan",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			if err := oArgs.Version.Validate(); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if err := validatePackageURI(oArgs.Package.PackageURI); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			offer, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v", err)
+				return err
+			}
+
+			if offer.TypeID != partner.ApplicationOfferType {
+				err := fmt.Errorf("offer %s is a %s offer, but packages are only supported for %s offers", offer.ID, offer.TypeID, partner.ApplicationOfferType)
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			plan := offer.GetPlanByID(oArgs.SKU)
+			if plan == nil {
+				err := fmt.Errorf("no plan %s was found in offer %s", oArgs.SKU, offer.ID)
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			version, err := oArgs.Version.Resolve(plan.GetApplicationPackages().Versions().Next)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if err := oArgs.Backup.Save(offer); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if plan.PlanApplicationDetail.Packages == nil {
+				plan.PlanApplicationDetail.Packages = make(map[string]partner.ApplicationPackage)
+			}
+			plan.PlanApplicationDetail.Packages[version] = oArgs.Package
+
+			offer, err = client.PutOffer(ctx, offer)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to put offer: %v", err)
+				return err
+			}
+
+			var packages partner.ApplicationPackages
+			if plan := offer.GetPlanByID(oArgs.SKU); plan != nil {
+				packages = plan.GetApplicationPackages()
+			}
+			return sl.GetPrinter().Print(packages)
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindSKU(cmd, &oArgs.SKU); err != nil {
+		return cmd, err
+	}
+
+	args.BindVersionOrNext(cmd, &oArgs.Version)
+	cmd.Flags().StringVar(&oArgs.Package.PackageURI, "package-uri", "", "HTTPS URL of the ARM template package (.zip) for the version")
+	if err := cmd.MarkFlagRequired("package-uri"); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().StringVar(&oArgs.Package.Description, "desc", "", "(optional) Description of the package version")
+	args.BindBackupArgs(cmd, &oArgs.Backup)
+	return cmd, nil
+}
+
+// validatePackageURI verifies the package URI is an https URL to a .zip file, which is how ARM template packages are
+// uploaded
+func validatePackageURI(packageURI string) error {
+	u, err := url.Parse(packageURI)
+	if err != nil {
+		return fmt.Errorf("unable to parse package URI: %v", err)
+	}
+
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("package URI %q must be an absolute https URL", packageURI)
+	}
+
+	if !strings.HasSuffix(strings.ToLower(u.Path), ".zip") {
+		return fmt.Errorf("package URI %q must refer to a .zip ARM template package", packageURI)
+	}
+	return nil
+}
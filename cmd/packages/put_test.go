@@ -0,0 +1,85 @@
+package packages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+const packageURI = "https://account.blob.core.windows.net/packages/app-1.2.0.zip"
+
+func TestPutCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newPutCommand)
+	test.VerifyFailsOnArgs(t, newPutCommand, "-p", "foo", "-o", "bar", "--sku", "managed", "--next", "minor")
+}
+
+func TestPutCommand_FailOnInvalidArgs(t *testing.T) {
+	cases := map[string][]string{
+		"VersionAndNext": {"--version", "1.0.0", "--next", "minor", "--package-uri", packageURI},
+		"BadVersion":     {"--version", "1.0", "--package-uri", packageURI},
+		"HTTPPackage":    {"--version", "1.0.0", "--package-uri", "http://account.blob.core.windows.net/packages/app.zip"},
+		"NotZipPackage":  {"--version", "1.0.0", "--package-uri", "https://account.blob.core.windows.net/packages/app.json"},
+	}
+
+	for name, args := range cases {
+		a := args
+		t.Run(name, func(t *testing.T) {
+			prtMock := new(test.PrinterMock)
+			prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+			rm := new(test.RegistryMock)
+			rm.On("GetPrinter").Return(prtMock)
+
+			cmd, err := test.QuietCommand(newPutCommand(rm))
+			require.NoError(t, err)
+			cmd.SetArgs(append([]string{"-p", "foo", "-o", "bar", "--sku", "managed"}, a...))
+			assert.Error(t, cmd.Execute())
+			rm.AssertNotCalled(t, "GetCloudPartnerService")
+		})
+	}
+}
+
+func TestPutCommand_FailOnNonApplicationOffer(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(offer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one", "--version", "1.0.0", "--package-uri", packageURI})
+	assert.Error(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "PutOffer", mock.Anything, mock.Anything)
+}
+
+func TestPutCommand_SuccessWithNext(t *testing.T) {
+	offer := test.NewMarketplaceApplicationOffer()
+	updatedOffer := test.NewMarketplaceApplicationOffer()
+	updatedOffer.Definition.Plans[0].PlanApplicationDetail.Packages["1.2.0"] = partner.ApplicationPackage{
+		PackageURI:  packageURI,
+		Description: "new package",
+	}
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(offer, nil)
+	svcMock.On("PutOffer", mock.Anything, updatedOffer).Return(updatedOffer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", updatedOffer.GetPlanByID("managed").GetApplicationPackages()).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--sku", "managed", "--next", "minor", "--package-uri", packageURI, "--desc", "new package"})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", updatedOffer.GetPlanByID("managed").GetApplicationPackages())
+}
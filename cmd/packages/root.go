@@ -0,0 +1,32 @@
+package packages
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+)
+
+// NewRootCmd returns the root packages cmd
+func NewRootCmd(sl service.CommandServicer) (*cobra.Command, error) {
+	rootCmd := &cobra.Command{
+		Use:              "packages",
+		Short:            "a group of actions for working with Azure Application package versions",
+		TraverseChildren: true,
+	}
+
+	cmdFuncs := []func(locator service.CommandServicer) (*cobra.Command, error){
+		newListCommand,
+		newShowCommand,
+		newPutCommand,
+	}
+
+	for _, f := range cmdFuncs {
+		cmd, err := f(sl)
+		if err != nil {
+			return rootCmd, err
+		}
+		rootCmd.AddCommand(cmd)
+	}
+
+	return rootCmd, nil
+}
@@ -0,0 +1,24 @@
+package packages_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/cmd/packages"
+	"github.com/devigned/pub/internal/test"
+)
+
+func TestNewRootCmd(t *testing.T) {
+	regMock := new(test.RegistryMock)
+	cmd, err := packages.NewRootCmd(regMock)
+	require.NoError(t, err)
+
+	expected := []string{"list", "put", "show"}
+	actual := make([]string, len(cmd.Commands()))
+	for i, c := range cmd.Commands() {
+		actual[i] = c.Name()
+	}
+	assert.ElementsMatch(t, expected, actual)
+}
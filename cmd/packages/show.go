@@ -0,0 +1,74 @@
+package packages
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	showPackageArgs struct {
+		Publisher string
+		Offer     string
+		SKU       string
+		Version   string
+	}
+)
+
+func newShowCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs showPackageArgs
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "show a package version for a given plan",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			offer, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v", err)
+				return err
+			}
+
+			var packages partner.ApplicationPackages
+			if plan := offer.GetPlanByID(oArgs.SKU); plan != nil {
+				packages = plan.GetApplicationPackages()
+			}
+
+			if pkg, ok := packages[oArgs.Version]; ok {
+				return sl.GetPrinter().Print(pkg)
+			}
+
+			return sl.GetPrinter().Print("no package version found")
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindSKU(cmd, &oArgs.SKU); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().StringVar(&oArgs.Version, "version", "", "String that uniquely identifies the package version.")
+	err := cmd.MarkFlagRequired("version")
+	return cmd, err
+}
@@ -0,0 +1,39 @@
+package packages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestShowCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newShowCommand)
+	test.VerifyFailsOnArgs(t, newShowCommand, "-p", "foo", "-o", "bar", "--sku", "managed")
+}
+
+func TestShowCommand_Success(t *testing.T) {
+	offer := test.NewMarketplaceApplicationOffer()
+	sku := offer.Definition.Plans[0]
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, partner.ShowOfferParams{
+		PublisherID: offer.PublisherID,
+		OfferID:     offer.ID,
+	}).Return(offer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", sku.GetApplicationPackages()["1.1.0"]).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newShowCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--sku", sku.ID, "--version", "1.1.0"})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", sku.GetApplicationPackages()["1.1.0"])
+}
@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/lint"
+	"github.com/devigned/pub/pkg/policy"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	checkPolicyArgs struct {
+		Source     args.OfferSource
+		PolicyFile string
+		Suppress   []string
+		Format     string
+	}
+)
+
+func newCheckCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs checkPolicyArgs
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "check an offer file or live draft against a policy file",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			format := lint.ReportFormat(oArgs.Format)
+			if err := format.Validate(); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			file := policy.ResolveFile(oArgs.PolicyFile)
+			if file == "" {
+				err := fmt.Errorf("no policy file was specified with --policy-file or $%s", policy.FileEnvVar)
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			p, err := policy.Load(file)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to load policy file %s: %v\n", file, err)
+				return err
+			}
+
+			offer, err := oArgs.Source.Load(ctx, sl)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to load offer: %v\n", err)
+				return err
+			}
+
+			report, err := p.Check(offer, oArgs.Suppress...)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+			report.Source = oArgs.Source.FilePath
+
+			out, err := report.Output(format)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if err := sl.GetPrinter().Print(out); err != nil {
+				return err
+			}
+
+			if report.HasErrors() {
+				return errors.New("offer violates the policy")
+			}
+			return nil
+		}),
+	}
+
+	args.BindOfferSource(cmd, &oArgs.Source)
+	cmd.Flags().StringVar(&oArgs.PolicyFile, "policy-file", "", fmt.Sprintf("Policy file to check the offer against (default is $%s)", policy.FileEnvVar))
+	cmd.Flags().StringArrayVar(&oArgs.Suppress, "suppress", []string{}, "Rule ID to suppress (can specify multiple)")
+	cmd.Flags().StringVar(&oArgs.Format, "format", string(lint.JSONReportFormat), "Report format, can be one of: json, sarif")
+	return cmd, nil
+}
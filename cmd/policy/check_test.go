@@ -0,0 +1,95 @@
+package policy
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/lint"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/policy"
+)
+
+const skuTitlePolicy = `
+rules:
+  - id: sku-title
+    selector: $.definition.plans[*]['microsoft-azure-virtualmachines.skuTitle']
+    assert:
+      regex: %s
+`
+
+func TestCheckCommand_FailOnMissingPolicyFile(t *testing.T) {
+	fName, del := test.NewTmpOfferFile(t, "offer")
+	defer del()
+
+	prev, ok := os.LookupEnv(policy.FileEnvVar)
+	require.NoError(t, os.Unsetenv(policy.FileEnvVar))
+	if ok {
+		defer func() {
+			_ = os.Setenv(policy.FileEnvVar, prev)
+		}()
+	}
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newCheckCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-f", fName})
+	assert.Error(t, cmd.Execute())
+	prtMock.AssertNotCalled(t, "Print", mock.Anything)
+}
+
+func TestCheckCommand_FailOnViolations(t *testing.T) {
+	fName, del := test.NewTmpOfferFile(t, "offer")
+	defer del()
+	pName, delPolicy := test.NewTmpPolicyFile(t, "policy", strings.Replace(skuTitlePolicy, "%s", "^Contoso", 1))
+	defer delPolicy()
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.AnythingOfType("*lint.Report")).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newCheckCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-f", fName, "--policy-file", pName})
+	assert.Error(t, cmd.Execute())
+
+	report := prtMock.Calls[0].Arguments.Get(0).(*lint.Report)
+	assert.Equal(t, fName, report.Source)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "sku-title", report.Findings[0].RuleID)
+}
+
+func TestCheckCommand_SuccessWithLiveOffer(t *testing.T) {
+	pName, delPolicy := test.NewTmpPolicyFile(t, "policy", strings.Replace(skuTitlePolicy, "%s", "^sku", 1))
+	defer delPolicy()
+
+	offer := test.NewMarketplaceVMOffer()
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, partner.ShowOfferParams{
+		PublisherID: offer.PublisherID,
+		OfferID:     offer.ID,
+	}).Return(offer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.AnythingOfType("*lint.SARIFLog")).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newCheckCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--policy-file", pName, "--format", "sarif"})
+	assert.NoError(t, cmd.Execute())
+
+	log := prtMock.Calls[0].Arguments.Get(0).(*lint.SARIFLog)
+	assert.Empty(t, log.Runs[0].Results)
+}
@@ -0,0 +1,30 @@
+package policy
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+)
+
+// NewRootCmd returns the root policy cmd
+func NewRootCmd(sl service.CommandServicer) (*cobra.Command, error) {
+	rootCmd := &cobra.Command{
+		Use:              "policy",
+		Short:            "a group of actions for working with publishing policies",
+		TraverseChildren: true,
+	}
+
+	cmdFuncs := []func(locator service.CommandServicer) (*cobra.Command, error){
+		newCheckCommand,
+	}
+
+	for _, f := range cmdFuncs {
+		cmd, err := f(sl)
+		if err != nil {
+			return rootCmd, err
+		}
+		rootCmd.AddCommand(cmd)
+	}
+
+	return rootCmd, nil
+}
@@ -0,0 +1,122 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/diff"
+	"github.com/devigned/pub/pkg/service"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	diffPricingArgs struct {
+		Publisher string
+		Offer     string
+		SKU       string
+		ExitCode  bool
+	}
+)
+
+func newDiffCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs diffPricingArgs
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "show the pricing changes from the Production slot to the Draft slot",
+		Long: `show the pricing changes from the Production slot to the Draft slot
+
+Each change is printed with the JSONPath of the pricing value, starting with the plan ID. If the offer has never been
+published, all of the draft pricing is shown as added.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			draft, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v", err)
+				return err
+			}
+
+			if oArgs.SKU != "" {
+				if _, err := getVMPlan(draft, oArgs.SKU); err != nil {
+					sl.GetPrinter().ErrPrintf("%v\n", err)
+					return err
+				}
+			} else if draft.TypeID != partner.VirtualMachineOfferType {
+				err := fmt.Errorf("offer %s is a %s offer, but pricing is only supported for %s offers", draft.ID, draft.TypeID, partner.VirtualMachineOfferType)
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			prod, err := client.GetOfferBySlot(ctx, partner.ShowOfferBySlotParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+				SlotID:      partner.ProductionSlot,
+			})
+			switch {
+			case err == partner.ErrOfferNotFound:
+				sl.GetPrinter().ErrPrintf("the offer is not in the %s slot, showing all draft pricing\n", partner.ProductionSlot)
+				prod = new(partner.Offer)
+			case err != nil:
+				sl.GetPrinter().ErrPrintf("unable to get the %s slot: %v\n", partner.ProductionSlot, err)
+				return err
+			}
+
+			changes, err := diff.Values(planPricing(prod, oArgs.SKU), planPricing(draft, oArgs.SKU))
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if changes == nil {
+				changes = []diff.Change{}
+			}
+
+			if err := sl.GetPrinter().Print(changes); err != nil {
+				return err
+			}
+
+			if oArgs.ExitCode && len(changes) > 0 {
+				return fmt.Errorf("pricing of offer %s/%s differs from the %s slot", oArgs.Publisher, oArgs.Offer, partner.ProductionSlot)
+			}
+			return nil
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().StringVarP(&oArgs.SKU, "sku", "s", "", "(optional) String that uniquely identifies the SKU (SKU ID); defaults to all SKUs")
+	cmd.Flags().BoolVar(&oArgs.ExitCode, "exit-code", false, "(optional) Exit with a non-zero code if the pricing has changed")
+	return cmd, nil
+}
+
+// planPricing returns the pricing of each plan of the offer by plan ID, or only of the plan with the given ID if it
+// is not empty
+func planPricing(offer *partner.Offer, planID string) map[string]*partner.VirtualMachinePricing {
+	pricing := make(map[string]*partner.VirtualMachinePricing)
+	for i := range offer.Definition.Plans {
+		plan := &offer.Definition.Plans[i]
+		if planID == "" || plan.ID == planID {
+			pricing[plan.ID] = plan.GetVMPricing()
+		}
+	}
+	return pricing
+}
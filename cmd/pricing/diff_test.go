@@ -0,0 +1,91 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/diff"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestDiffCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newDiffCommand)
+	test.VerifyFailsOnArgs(t, newDiffCommand, "-p", "foo")
+}
+
+func TestDiffCommand_Success(t *testing.T) {
+	draft := test.NewMarketplaceVMOffer()
+	draft.Definition.Plans[0].PlanVirtualMachineDetail.VirtualMachinePricingV2.FreeTrialDurationInMonths = to.IntPtr(1)
+	prod := test.NewMarketplaceVMOffer()
+
+	expected := []diff.Change{
+		{
+			Op:   diff.Changed,
+			Path: "$.planId_one.freeTrialDurationInMonths",
+			From: float64(0),
+			To:   float64(1),
+		},
+	}
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(draft, nil)
+	svcMock.On("GetOfferBySlot", mock.Anything, partner.ShowOfferBySlotParams{
+		PublisherID: draft.PublisherID,
+		OfferID:     draft.ID,
+		SlotID:      partner.ProductionSlot,
+	}).Return(prod, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", expected).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newDiffCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--exit-code"})
+	assert.Error(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", expected)
+}
+
+func TestDiffCommand_SuccessWithoutProduction(t *testing.T) {
+	draft := test.NewMarketplaceVMOffer()
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(draft, nil)
+	svcMock.On("GetOfferBySlot", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), partner.ErrOfferNotFound)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", mock.Anything, mock.Anything).Return(nil)
+	prtMock.On("Print", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newDiffCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID, "--sku", "planId_one"})
+	assert.NoError(t, cmd.Execute())
+
+	changes := prtMock.Calls[len(prtMock.Calls)-1].Arguments.Get(0).([]diff.Change)
+	require.Len(t, changes, 1)
+	assert.Equal(t, diff.Added, changes[0].Op)
+	assert.Equal(t, "$.planId_one", changes[0].Path)
+}
+
+func TestDiffCommand_FailOnProductionError(t *testing.T) {
+	draft := test.NewMarketplaceVMOffer()
+	boomErr := errors.New("boom")
+	rm, svcMock, prtMock := test.NewOfferRegistryMocks(draft)
+	svcMock.On("GetOfferBySlot", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), boomErr)
+
+	cmd, err := test.QuietCommand(newDiffCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", draft.PublisherID, "-o", draft.ID})
+	assert.Equal(t, boomErr, cmd.Execute())
+	prtMock.AssertNotCalled(t, "Print", mock.Anything)
+}
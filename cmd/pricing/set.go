@@ -0,0 +1,240 @@
+package pricing
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/xcobra"
+
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+const (
+	// usd is the currency of the core price, from which the prices for other currencies are generated
+	usd = "USD"
+)
+
+var (
+	marketRegex   = regexp.MustCompile(`^[A-Z]{2}$`)
+	currencyRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+type (
+	setPricingArgs struct {
+		Publisher        string
+		Offer            string
+		SKU              string
+		BYOL             bool
+		FreeTrialMonths  int
+		CorePrice        float32
+		MarketPricesPath string
+		Backup           args.BackupArgs
+	}
+)
+
+func newSetCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs setPricingArgs
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "set the pricing for a given virtual machine plan",
+		Long: `set the pricing for a given virtual machine plan
+
+Only the pricing options which are specified are changed. Switching a plan to bring your own license removes the
+core price and market prices. The market prices CSV file has a row of market,currency,price for each market which
+overrides the price generated from the core price, for example "DE,EUR,0.09". The file replaces all market prices
+of the plan, which are sent in the marketPrices object of the pricing, keyed by market code. The offer is put with
+the Etag it was fetched with, so the put fails if the offer changed meanwhile.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			update, err := newPricingUpdate(cmd, oArgs)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			offer, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v", err)
+				return err
+			}
+
+			plan, err := getVMPlan(offer, oArgs.SKU)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if err := oArgs.Backup.Save(offer); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			plan.UpdateVMPricing(update)
+			if err := plan.GetVMPricing().Validate(); err != nil {
+				err = fmt.Errorf("invalid pricing for plan %s: %v", plan.ID, err)
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			offer, err = client.PutOfferIfMatch(ctx, offer)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to put offer: %v", err)
+				return err
+			}
+
+			var pricing *partner.VirtualMachinePricing
+			if plan := offer.GetPlanByID(oArgs.SKU); plan != nil {
+				pricing = plan.GetVMPricing()
+			}
+			return sl.GetPrinter().Print(pricing)
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindSKU(cmd, &oArgs.SKU); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().BoolVar(&oArgs.BYOL, "byol", false, "(optional) Customers bring their own license rather than paying for the software")
+	cmd.Flags().IntVar(&oArgs.FreeTrialMonths, "free-trial-months", 0, fmt.Sprintf("(optional) Duration of the free trial in months; one of %v", partner.FreeTrialDurations))
+	cmd.Flags().Float32Var(&oArgs.CorePrice, "core-price", 0, "(optional) Price per core per hour in USD, from which the prices for other markets are generated")
+	cmd.Flags().StringVar(&oArgs.MarketPricesPath, "market-prices", "", "(optional) CSV file of market,currency,price rows which replace the market price overrides")
+	args.BindBackupArgs(cmd, &oArgs.Backup)
+	return cmd, nil
+}
+
+// newPricingUpdate returns a function which applies the pricing flags which were set on the command to a pricing
+func newPricingUpdate(cmd *cobra.Command, oArgs setPricingArgs) (func(pricing *partner.VirtualMachinePricing), error) {
+	flags := cmd.Flags()
+	if !flags.Changed("byol") && !flags.Changed("free-trial-months") && !flags.Changed("core-price") && !flags.Changed("market-prices") {
+		return nil, errors.New("no pricing changes were specified; set at least one of --byol, --free-trial-months, --core-price or --market-prices")
+	}
+
+	var marketPrices map[string]partner.CoreMultiplier
+	if flags.Changed("market-prices") {
+		prices, err := readMarketPricesFile(oArgs.MarketPricesPath)
+		if err != nil {
+			return nil, err
+		}
+		marketPrices = prices
+	}
+
+	return func(pricing *partner.VirtualMachinePricing) {
+		if flags.Changed("byol") {
+			pricing.IsBringYourOwnLicense = to.BoolPtr(oArgs.BYOL)
+			if oArgs.BYOL {
+				pricing.CoreMultiplier = nil
+				pricing.MarketPrices = nil
+			}
+		}
+
+		if flags.Changed("free-trial-months") {
+			pricing.FreeTrialDurationInMonths = to.IntPtr(oArgs.FreeTrialMonths)
+		}
+
+		if flags.Changed("core-price") {
+			pricing.CoreMultiplier = &partner.CoreMultiplier{
+				Currency: usd,
+				Single:   to.Float32Ptr(oArgs.CorePrice),
+			}
+		}
+
+		if flags.Changed("market-prices") {
+			pricing.MarketPrices = marketPrices
+		}
+	}, nil
+}
+
+func readMarketPricesFile(path string) (map[string]partner.CoreMultiplier, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	prices, err := readMarketPrices(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read market prices from %s: %v", path, err)
+	}
+	return prices, nil
+}
+
+// readMarketPrices reads market,currency,price rows into market price overrides. An optional header row starting
+// with "market" is skipped.
+func readMarketPrices(r io.Reader) (map[string]partner.CoreMultiplier, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	prices := make(map[string]partner.CoreMultiplier)
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if row == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "market") {
+			continue
+		}
+
+		market, currency := strings.ToUpper(strings.TrimSpace(record[0])), strings.ToUpper(strings.TrimSpace(record[1]))
+		if !marketRegex.MatchString(market) {
+			return nil, fmt.Errorf("row %d: market %q must be a two letter market code", row, record[0])
+		}
+
+		if !currencyRegex.MatchString(currency) {
+			return nil, fmt.Errorf("row %d: currency %q must be a three letter currency code", row, record[1])
+		}
+
+		price, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 32)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: price %q is not a number", row, record[2])
+		}
+
+		if _, ok := prices[market]; ok {
+			return nil, fmt.Errorf("row %d: market %s has more than one price", row, market)
+		}
+
+		prices[market] = partner.CoreMultiplier{
+			Currency: currency,
+			Single:   to.Float32Ptr(float32(price)),
+		}
+	}
+	return prices, nil
+}
@@ -0,0 +1,148 @@
+package pricing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/backup"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestSetCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newSetCommand)
+	test.VerifyFailsOnArgs(t, newSetCommand, "-p", "foo", "-o", "bar", "--core-price", "0.1")
+}
+
+func TestSetCommand_FailWithoutChanges(t *testing.T) {
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newSetCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", "foo", "-o", "bar", "--sku", "planId_one"})
+	assert.Error(t, cmd.Execute())
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestSetCommand_FailOnInvalidPricing(t *testing.T) {
+	cases := map[string][]string{
+		"PriceWithBYOL":   {"--core-price", "0.1"},
+		"FreeTrialLength": {"--byol=false", "--free-trial-months", "2"},
+		"NegativePrice":   {"--byol=false", "--core-price", "-1"},
+	}
+
+	for name, args := range cases {
+		a := args
+		t.Run(name, func(t *testing.T) {
+			offer := test.NewMarketplaceVMOffer()
+			svcMock := new(test.CloudPartnerServiceMock)
+			svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(offer, nil)
+			prtMock := new(test.PrinterMock)
+			prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+			rm := new(test.RegistryMock)
+			rm.On("GetCloudPartnerService").Return(svcMock, nil)
+			rm.On("GetPrinter").Return(prtMock)
+
+			cmd, err := test.QuietCommand(newSetCommand(rm))
+			require.NoError(t, err)
+			cmd.SetArgs(append([]string{"-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one"}, a...))
+			assert.Error(t, cmd.Execute())
+			svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSetCommand_Success(t *testing.T) {
+	csvFile, cleanup := test.NewTmpFileWithContent(t, "prices", "market,currency,price\nDE,EUR,0.09\ngb, gbp, 0.08\n")
+	defer cleanup()
+
+	offer := test.NewMarketplaceVMOffer()
+	updatedOffer := test.NewMarketplaceVMOffer()
+	expected := &partner.VirtualMachinePricing{
+		IsBringYourOwnLicense:     to.BoolPtr(false),
+		FreeTrialDurationInMonths: to.IntPtr(1),
+		CoreMultiplier: &partner.CoreMultiplier{
+			Currency: "USD",
+			Single:   to.Float32Ptr(0.1),
+		},
+		MarketPrices: map[string]partner.CoreMultiplier{
+			"DE": {Currency: "EUR", Single: to.Float32Ptr(0.09)},
+			"GB": {Currency: "GBP", Single: to.Float32Ptr(0.08)},
+		},
+	}
+	expectedV2 := *expected
+	updatedOffer.Definition.Plans[0].PlanVirtualMachineDetail.VirtualMachinePricing = expected
+	updatedOffer.Definition.Plans[0].PlanVirtualMachineDetail.VirtualMachinePricingV2 = &expectedV2
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(offer, nil)
+	svcMock.On("PutOfferIfMatch", mock.Anything, updatedOffer).Return(updatedOffer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", &expectedV2).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newSetCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one", "--byol=false", "--free-trial-months", "1", "--core-price", "0.1", "--market-prices", csvFile})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, updatedOffer)
+	prtMock.AssertCalled(t, "Print", &expectedV2)
+}
+
+func TestReadMarketPrices_Errors(t *testing.T) {
+	cases := map[string]string{
+		"BadMarket":     "USA,USD,1",
+		"BadCurrency":   "US,dollars,1",
+		"BadPrice":      "US,USD,one",
+		"MissingColumn": "US,1",
+		"Duplicate":     "US,USD,1\nus,USD,2",
+	}
+
+	for name, csv := range cases {
+		c := csv
+		t.Run(name, func(t *testing.T) {
+			_, err := readMarketPrices(strings.NewReader(c))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSetCommand_BacksUpOfferUnlessSkipped(t *testing.T) {
+	cases := map[string]struct {
+		args    []string
+		backups int
+	}{
+		"backup":    {backups: 1},
+		"no backup": {args: []string{"--no-backup"}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, del := test.NewTmpDir(t)
+			defer del()
+
+			offer := test.NewMarketplaceVMOffer()
+			rm, svcMock, _ := test.NewOfferRegistryMocks(offer)
+			svcMock.On("PutOfferIfMatch", mock.Anything, mock.Anything).Return(offer, nil)
+
+			cmd, err := test.QuietCommand(newSetCommand(rm))
+			require.NoError(t, err)
+			cmd.SetArgs(append([]string{"-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one", "--byol", "--backup-dir", dir}, c.args...))
+			require.NoError(t, cmd.Execute())
+
+			backups, err := (&backup.Store{Dir: dir}).List(offer.PublisherID, offer.ID)
+			require.NoError(t, err)
+			assert.Len(t, backups, c.backups)
+		})
+	}
+}
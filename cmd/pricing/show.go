@@ -0,0 +1,79 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	showPricingArgs struct {
+		Publisher string
+		Offer     string
+		SKU       string
+	}
+)
+
+func newShowCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs showPricingArgs
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "show the pricing for a given virtual machine plan",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			offer, err := client.GetOffer(ctx, partner.ShowOfferParams{
+				PublisherID: oArgs.Publisher,
+				OfferID:     oArgs.Offer,
+			})
+
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to get offer: %v", err)
+				return err
+			}
+
+			plan, err := getVMPlan(offer, oArgs.SKU)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			return sl.GetPrinter().Print(plan.GetVMPricing())
+		}),
+	}
+
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	err := args.BindSKU(cmd, &oArgs.SKU)
+	return cmd, err
+}
+
+// getVMPlan returns the plan of a virtual machine offer, which is the only offer type with plan pricing
+func getVMPlan(offer *partner.Offer, planID string) (*partner.Plan, error) {
+	if offer.TypeID != partner.VirtualMachineOfferType {
+		return nil, fmt.Errorf("offer %s is a %s offer, but pricing is only supported for %s offers", offer.ID, offer.TypeID, partner.VirtualMachineOfferType)
+	}
+
+	plan := offer.GetPlanByID(planID)
+	if plan == nil {
+		return nil, fmt.Errorf("no plan %s was found in offer %s", planID, offer.ID)
+	}
+	return plan, nil
+}
@@ -0,0 +1,58 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestShowCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newShowCommand)
+	test.VerifyFailsOnArgs(t, newShowCommand, "-p", "foo", "-o", "bar")
+}
+
+func TestShowCommand_FailOnCloudPartnerServiceError(t *testing.T) {
+	test.VerifyCloudPartnerServiceCommand(t, newShowCommand, "-p", "foo", "-o", "bar", "--sku", "planId_one")
+}
+
+func TestShowCommand_FailOnNonVMOffer(t *testing.T) {
+	offer := test.NewMarketplaceCoreVMOffer()
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(offer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newShowCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one"})
+	assert.Error(t, cmd.Execute())
+	prtMock.AssertNotCalled(t, "Print", mock.Anything)
+}
+
+func TestShowCommand_Success(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, partner.ShowOfferParams{
+		PublisherID: offer.PublisherID,
+		OfferID:     offer.ID,
+	}).Return(offer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", offer.Definition.Plans[0].PlanVirtualMachineDetail.VirtualMachinePricingV2).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newShowCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one"})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", offer.Definition.Plans[0].PlanVirtualMachineDetail.VirtualMachinePricingV2)
+}
@@ -0,0 +1,77 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/export"
+	"github.com/devigned/pub/pkg/parallel"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	exportArgs struct {
+		Dir         string
+		Parallelism int
+		Full        bool
+	}
+)
+
+func newExportCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs exportArgs
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "export every offer of every publisher to a directory",
+		Long: `Export every offer of every publisher to a directory for disaster recovery and audits. The Draft, Preview and
+Production slots, status and operations of each offer are written to <dir>/<publisher>/<offer>/, with a manifest of
+the export in <dir>/manifest.json. Offers are fetched concurrently. Offers with the same version and changed time as
+in the manifest of the last export, and the same status and operations, are skipped unless --full is specified. The
+file of a slot an offer is no longer in is removed. A summary of the export is printed, and the command fails if
+anything could not be exported.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			exporter := &export.Exporter{
+				Client:      client,
+				Dir:         oArgs.Dir,
+				Parallelism: oArgs.Parallelism,
+				Full:        oArgs.Full,
+			}
+
+			summary, err := exporter.Export(ctx)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to export: %v\n", err)
+				return err
+			}
+
+			for _, msg := range summary.Errors {
+				sl.GetPrinter().ErrPrintf("%s\n", msg)
+			}
+
+			if err := sl.GetPrinter().Print(summary); err != nil {
+				return err
+			}
+
+			if len(summary.Errors) > 0 {
+				return fmt.Errorf("export finished with %d error(s)", len(summary.Errors))
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&oArgs.Dir, "dir", "", "Directory to export to")
+	if err := cmd.MarkFlagRequired("dir"); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().IntVar(&oArgs.Parallelism, "parallelism", parallel.DefaultLimit, "(optional) Number of offers to fetch at once")
+	cmd.Flags().BoolVar(&oArgs.Full, "full", false, "(optional) Export every offer, even those which have not changed since the last export")
+	return cmd, nil
+}
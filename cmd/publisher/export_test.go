@@ -0,0 +1,63 @@
+package publisher
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/export"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestExportCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newExportCommand)
+}
+
+func TestExportCommand_FailOnCloudPartnerServiceError(t *testing.T) {
+	test.VerifyCloudPartnerServiceCommand(t, newExportCommand, "--dir", "foo")
+}
+
+func TestExportCommand_Success(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("ListPublishers", mock.Anything).Return([]partner.Publisher{{Entity: partner.Entity{ID: "publisher"}}}, nil)
+	svcMock.On("ListOffers", mock.Anything, mock.Anything).Return([]partner.Offer{}, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newExportCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"--dir", dir})
+	require.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", &export.Summary{Dir: dir, Publishers: 1})
+}
+
+func TestExportCommand_FailWithSummaryOnErrors(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("ListPublishers", mock.Anything).Return([]partner.Publisher{{Entity: partner.Entity{ID: "publisher"}}}, nil)
+	svcMock.On("ListOffers", mock.Anything, mock.Anything).Return([]partner.Offer{}, errors.New("boom"))
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.Anything).Return(nil)
+	prtMock.On("ErrPrintf", "%s\n", []interface{}{"publisher: unable to list offers: boom"}).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newExportCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"--dir", dir})
+	assert.Error(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", mock.Anything)
+}
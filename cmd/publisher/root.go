@@ -19,6 +19,11 @@ func NewRootCmd(sl service.CommandServicer) (*cobra.Command, error) {
 		return rootCmd, err
 	}
 
-	rootCmd.AddCommand(list)
+	exportCmd, err := newExportCommand(sl)
+	if err != nil {
+		return rootCmd, err
+	}
+
+	rootCmd.AddCommand(list, exportCmd)
 	return rootCmd, err
 }
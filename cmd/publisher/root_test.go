@@ -15,7 +15,7 @@ func TestNewRootCmd(t *testing.T) {
 	cmd, err := publisher.NewRootCmd(regMock)
 	require.NoError(t, err)
 
-	expected := []string{"list"}
+	expected := []string{"list", "export"}
 	actual := make([]string, len(cmd.Commands()))
 	for i, c := range cmd.Commands() {
 		actual[i] = c.Name()
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	renderArgs struct {
+		FilePath string
+		Template args.TemplateArgs
+	}
+)
+
+func newRenderCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs renderArgs
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "render an offer or SKU template to preview what would be put",
+		Long: `Render an offer or SKU file as a template with the same --values, --var and --strict-vars flags as offers put
+and skus put, and print the result as JSON. A YAML file with several documents is printed as an array.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			oArgs.Template.Template = true
+			docs, err := oArgs.Template.ReadJSON(oArgs.FilePath, cmd.InOrStdin())
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if len(docs) == 0 {
+				err := errors.New("the file rendered no documents")
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			rendered := make([]interface{}, len(docs))
+			for i, bits := range docs {
+				if err := json.Unmarshal(bits, &rendered[i]); err != nil {
+					sl.GetPrinter().ErrPrintf("the rendered file is not valid JSON: %v\n", err)
+					return err
+				}
+			}
+
+			if len(rendered) == 1 {
+				return sl.GetPrinter().Print(rendered[0])
+			}
+			return sl.GetPrinter().Print(rendered)
+		}),
+	}
+
+	cmd.Flags().StringVarP(&oArgs.FilePath, "file", "f", "", "File path to the JSON or YAML template, or - for stdin")
+	if err := cmd.MarkFlagRequired("file"); err != nil {
+		return cmd, err
+	}
+
+	args.BindTemplateArgs(cmd, &oArgs.Template)
+	if err := cmd.Flags().MarkHidden("template"); err != nil {
+		return cmd, err
+	}
+	return cmd, nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+)
+
+func TestRenderCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newRenderCommand)
+}
+
+func TestRenderCommand_Success(t *testing.T) {
+	values, del := test.NewTmpFileWithContent(t, "values*.yaml", "publisher:\n  id: contoso-dev\n")
+	defer del()
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newRenderCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader("planId: ${sku}\n---\npublisherId: '{{ .publisher.id }}'\n"))
+	cmd.SetArgs([]string{"-f", "-", "--values", values, "--var", "sku=dev"})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", []interface{}{
+		map[string]interface{}{"planId": "dev"},
+		map[string]interface{}{"publisherId": "contoso-dev"},
+	})
+}
+
+func TestRenderCommand_FailOnUndefinedStrictVars(t *testing.T) {
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newRenderCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader(`{"id": "${PUB_TEST_UNDEFINED_VALUE}"}`))
+	cmd.SetArgs([]string{"-f", "-", "--strict-vars"})
+	assert.Error(t, cmd.Execute())
+	prtMock.AssertNotCalled(t, "Print", mock.Anything)
+}
@@ -11,11 +11,19 @@ import (
 	"github.com/devigned/pub/pkg/format"
 	"github.com/devigned/pub/pkg/service"
 
+	"github.com/devigned/pub/cmd/backup"
+	"github.com/devigned/pub/cmd/cache"
+	"github.com/devigned/pub/cmd/listing"
 	"github.com/devigned/pub/cmd/offer"
 	"github.com/devigned/pub/cmd/operation"
+	"github.com/devigned/pub/cmd/packages"
+	"github.com/devigned/pub/cmd/policy"
+	"github.com/devigned/pub/cmd/pricing"
 	"github.com/devigned/pub/cmd/publisher"
+	"github.com/devigned/pub/cmd/schema"
 	"github.com/devigned/pub/cmd/sku"
 	"github.com/devigned/pub/cmd/version"
+	offercache "github.com/devigned/pub/pkg/cache"
 	"github.com/devigned/pub/pkg/partner"
 )
 
@@ -46,12 +54,30 @@ func newRootCommand() (*cobra.Command, error) {
 
 	var apiVersion string
 	var cfgFile string
+	var noCache bool
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.pub.yaml)")
 	rootCmd.PersistentFlags().StringVarP(&apiVersion, "api-version", "v", "2017-10-31", "the API version override")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "fetch offers without the local cache enabled by PUB_CACHE or PUB_CACHE_DIR")
 
 	sl := &service.Registry{
 		CloudPartnerServicerFactory: func() (service.CloudPartnerServicer, error) {
-			return partner.New(apiVersion)
+			enabled, err := offercache.Enabled()
+			if err != nil {
+				return nil, err
+			}
+
+			if !enabled {
+				return partner.New(apiVersion)
+			}
+
+			store, err := offercache.NewStore("")
+			if err != nil {
+				return nil, err
+			}
+
+			// with --no-cache, offers are still invalidated when they are changed, so the cache is not left stale
+			store.Bypass = noCache
+			return partner.New(apiVersion, partner.WithOfferCache(store))
 		},
 		PrinterFactory: func() format.Printer {
 			return &format.StdPrinter{
@@ -66,6 +92,15 @@ func newRootCommand() (*cobra.Command, error) {
 		sku.NewRootCmd,
 		version.NewRootCmd,
 		operation.NewRootCmd,
+		policy.NewRootCmd,
+		packages.NewRootCmd,
+		pricing.NewRootCmd,
+		listing.NewRootCmd,
+		schema.NewRootCmd,
+		backup.NewRootCmd,
+		cache.NewRootCmd,
+		newRenderCommand,
+		newSearchCommand,
 		func(locator service.CommandServicer) (*cobra.Command, error) {
 			return newVersionCommand(), nil
 		},
@@ -0,0 +1,33 @@
+package schema
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/schema"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+func newPrintCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "print <type>",
+		Short: "print the JSON Schema of a partner type",
+		Long: `Print the JSON Schema of a partner type for editor autocompletion and validation. The type is one of:
+` + strings.Join(schema.Names(), ", ") + `. Offer files are validated against the offer schema and SKU files against the
+plan schema by offers put and skus put.`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: schema.Names(),
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			s, err := schema.ForName(args[0])
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+			return sl.GetPrinter().Print(s)
+		}),
+	}
+	return cmd, nil
+}
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/schema"
+)
+
+func TestPrintCommand_Success(t *testing.T) {
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPrintCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"Offer"})
+	require.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", schema.Offer())
+}
+
+func TestPrintCommand_FailOnUnknownType(t *testing.T) {
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPrintCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"unknown"})
+	assert.Error(t, cmd.Execute())
+	prtMock.AssertNotCalled(t, "Print", mock.Anything)
+}
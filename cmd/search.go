@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/export"
+	"github.com/devigned/pub/pkg/parallel"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/search"
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	searchArgs struct {
+		PublisherIDs []string
+		Query        string
+		Regex        string
+		Version      string
+		Parallelism  int
+		CacheDir     string
+	}
+)
+
+func newSearchCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs searchArgs
+	cmd := &cobra.Command{
+		Use:   "search",
+		Short: "search the offers of one or more publishers with a JSONPath query",
+		Long: `Search the drafts of the offers of one or more publishers, or of every publisher if none are given, with a
+JSONPath query such as $.definition.plans[*]['microsoft-azure-virtualmachines.vmImages'].* and print each value
+selected with the offer, plan and image version it was found in. The values may be filtered with --regex, and by the
+image version they are in with --version.
+
+Offers are fetched from the Cloud Partner Portal, or read from an export made with publishers export when --cache-dir
+is specified. Run publishers export --dir <cache-dir> to refresh the cache.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			query, err := search.NewQuery(oArgs.Query, oArgs.Regex, oArgs.Version)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			var offers []*partner.Offer
+			if oArgs.CacheDir != "" {
+				offers, err = export.ReadDrafts(oArgs.CacheDir, oArgs.PublisherIDs...)
+				if err != nil {
+					sl.GetPrinter().ErrPrintf("unable to read offers from %s: %v\n", oArgs.CacheDir, err)
+					return err
+				}
+				search.Sort(offers)
+			} else {
+				client, err := sl.GetCloudPartnerService()
+				if err != nil {
+					sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+					return err
+				}
+
+				offers, err = search.Load(ctx, client, oArgs.PublisherIDs, oArgs.Parallelism)
+				if err != nil {
+					sl.GetPrinter().ErrPrintf("%v\n", err)
+					return err
+				}
+			}
+
+			results, err := query.Run(offers)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+			return sl.GetPrinter().Print(results)
+		}),
+	}
+
+	cmd.Flags().StringSliceVarP(&oArgs.PublisherIDs, "publisher", "p", nil, "(optional) Publisher ID; may be repeated (default is every publisher)")
+	cmd.Flags().StringVarP(&oArgs.Query, "query", "q", "", "JSONPath query selecting values from each offer")
+	if err := cmd.MarkFlagRequired("query"); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().StringVar(&oArgs.Regex, "regex", "", "(optional) Regular expression the selected values must match")
+	cmd.Flags().StringVar(&oArgs.Version, "version", "", "(optional) Regular expression the image version of the selected values must match")
+	cmd.Flags().IntVar(&oArgs.Parallelism, "parallelism", parallel.DefaultLimit, "(optional) Number of offers to fetch at once")
+	cmd.Flags().StringVar(&oArgs.CacheDir, "cache-dir", "", "(optional) Directory of an export made with publishers export to read offers from")
+	return cmd, nil
+}
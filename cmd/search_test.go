@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/export"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/search"
+)
+
+func newSearchMocks() (*test.RegistryMock, *test.CloudPartnerServiceMock, *test.PrinterMock) {
+	offer := test.NewMarketplaceVMOffer()
+	rm, svcMock, prtMock := test.NewRegistryMocks()
+	svcMock.On("ListPublishers", mock.Anything).Return([]partner.Publisher{{Entity: partner.Entity{ID: offer.PublisherID}}}, nil)
+	svcMock.On("ListOffers", mock.Anything, partner.ListOffersParams{PublisherID: offer.PublisherID}).Return([]partner.Offer{*offer}, nil)
+	svcMock.On("GetOffer", mock.Anything, partner.ShowOfferParams{PublisherID: offer.PublisherID, OfferID: offer.ID}).Return(offer, nil)
+	svcMock.On("GetOfferBySlot", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), partner.ErrOfferNotFound)
+	svcMock.On("GetOfferStatus", mock.Anything, mock.Anything).Return(&partner.OfferStatus{Status: "succeeded"}, nil)
+	svcMock.On("ListOperations", mock.Anything, mock.Anything).Return([]partner.Operation{}, nil)
+	return rm, svcMock, prtMock
+}
+
+var searchVersionResult = []search.Result{
+	{
+		PublisherID: "publisherId",
+		OfferID:     "test",
+		PlanID:      "planId_one",
+		Version:     "2019.10.11",
+		Path:        "$.definition.plans[0]['microsoft-azure-virtualmachines.vmImages']['2019.10.11'].osVhdUrl",
+		Value:       "osVhdUrl_two",
+	},
+}
+
+func TestSearchCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newSearchCommand)
+	test.VerifyFailsOnArgs(t, newSearchCommand, "-p", "foo")
+}
+
+func TestSearchCommand_FailOnCloudPartnerError(t *testing.T) {
+	test.VerifyCloudPartnerServiceCommand(t, newSearchCommand, "-q", "$.id")
+}
+
+func TestSearchCommand_FailOnInvalidQuery(t *testing.T) {
+	rm, svcMock, prtMock := newSearchMocks()
+	cmd, err := test.QuietCommand(newSearchCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-q", "$.definition[", "-p", "publisherId"})
+	assert.Error(t, cmd.Execute())
+	svcMock.AssertNotCalled(t, "ListOffers", mock.Anything, mock.Anything)
+	prtMock.AssertNotCalled(t, "Print", mock.Anything)
+}
+
+func TestSearchCommand_Success(t *testing.T) {
+	rm, svcMock, prtMock := newSearchMocks()
+	cmd, err := test.QuietCommand(newSearchCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", "publisherId", "-q", "$.definition.plans[*]['microsoft-azure-virtualmachines.vmImages'].*.osVhdUrl", "--version", `^2019\.`})
+	require.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", searchVersionResult)
+	svcMock.AssertNotCalled(t, "ListPublishers", mock.Anything)
+}
+
+func TestSearchCommand_SuccessWithCacheDir(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	rm, svcMock, prtMock := newSearchMocks()
+	_, err := (&export.Exporter{Client: svcMock, Dir: dir}).Export(context.Background())
+	require.NoError(t, err)
+
+	svcMock = new(test.CloudPartnerServiceMock)
+	rm = new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newSearchCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"--cache-dir", dir, "-q", "$.definition.plans[*]['microsoft-azure-virtualmachines.vmImages'].*.osVhdUrl", "--version", `^2019\.`})
+	require.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", searchVersionResult)
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
@@ -0,0 +1,274 @@
+package sku
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+
+	"github.com/devigned/pub/cmd/args"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	availabilityArgs struct {
+		Publisher     string
+		Offer         string
+		SKU           string
+		Regions       []string
+		AddRegions    []string
+		RemoveRegions []string
+		AllRegions    bool
+		Clouds        []string
+		Backup        args.BackupArgs
+	}
+
+	// planAvailability is where a plan is available
+	planAvailability struct {
+		Regions []string                          `json:"regions"`
+		Clouds  []partner.CloudAvailabilityOption `json:"cloudAvailability,omitempty"`
+	}
+)
+
+func newAvailabilityCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "availability",
+		Short: "a group of actions for working with the regions and clouds in which a SKU is available",
+	}
+
+	cmdFuncs := []func(locator service.CommandServicer) (*cobra.Command, error){
+		newAvailabilityShowCommand,
+		newAvailabilitySetCommand,
+	}
+
+	for _, f := range cmdFuncs {
+		c, err := f(sl)
+		if err != nil {
+			return cmd, err
+		}
+		cmd.AddCommand(c)
+	}
+
+	return cmd, nil
+}
+
+func newAvailabilityShowCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs availabilityArgs
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "show the regions and clouds in which a SKU is available",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			_, offerType, plan, err := getAvailabilityPlan(ctx, client, oArgs)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			return sl.GetPrinter().Print(getPlanAvailability(offerType, plan))
+		}),
+	}
+
+	return bindAvailabilityArgs(cmd, &oArgs)
+}
+
+func newAvailabilitySetCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs availabilityArgs
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "set the regions and clouds in which a SKU is available",
+		Long: `set the regions and clouds in which a SKU is available
+
+Regions are market codes, like US or DE. --regions replaces the regions of the SKU, while --add-regions and
+--remove-regions change them. Clouds are one of PublicAzure, Fairfax, Mooncake or Blackforest. A warning is printed
+if a cloud is enabled before the SKU has the description and useful links for the cloud.`,
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			if err := validateAvailabilityArgs(cmd, oArgs); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			client, err := sl.GetCloudPartnerService()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
+				return err
+			}
+
+			offer, offerType, plan, err := getAvailabilityPlan(ctx, client, oArgs)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			if err := oArgs.Backup.Save(offer); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			regions, err := updatedRegions(cmd, oArgs, plan.Regions)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+			plan.Regions = regions
+
+			if cmd.Flags().Changed("clouds") {
+				clouds, err := partner.ParseCloudAvailability(oArgs.Clouds)
+				if err != nil {
+					sl.GetPrinter().ErrPrintf("%v\n", err)
+					return err
+				}
+
+				if err := offerType.SetCloudAvailability(plan, clouds); err != nil {
+					sl.GetPrinter().ErrPrintf("%v\n", err)
+					return err
+				}
+
+				for _, cloud := range clouds {
+					if missing := offerType.MissingCloudDetails(plan, cloud); len(missing) > 0 {
+						sl.GetPrinter().ErrPrintf("warning: plan %s is available in %s, but %s is not set\n", plan.ID, cloud, strings.Join(missing, ", "))
+					}
+				}
+			}
+
+			offer, err = client.PutOfferIfMatch(ctx, offer)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("unable to put offer: %v", err)
+				return err
+			}
+
+			if plan = offer.GetPlanByID(oArgs.SKU); plan == nil {
+				return sl.GetPrinter().Print("no SKU found")
+			}
+			return sl.GetPrinter().Print(getPlanAvailability(offerType, plan))
+		}),
+	}
+
+	if _, err := bindAvailabilityArgs(cmd, &oArgs); err != nil {
+		return cmd, err
+	}
+
+	cmd.Flags().StringSliceVar(&oArgs.Regions, "regions", nil, "(optional) Market codes of the regions which replace the regions of the SKU")
+	cmd.Flags().StringSliceVar(&oArgs.AddRegions, "add-regions", nil, "(optional) Market codes of regions to add to the SKU")
+	cmd.Flags().StringSliceVar(&oArgs.RemoveRegions, "remove-regions", nil, "(optional) Market codes of regions to remove from the SKU")
+	cmd.Flags().BoolVar(&oArgs.AllRegions, "all-regions", false, "(optional) Make the SKU available in all regions")
+	cmd.Flags().StringSliceVar(&oArgs.Clouds, "clouds", nil, fmt.Sprintf("(optional) Clouds in which the SKU is available; any of %v", partner.CloudAvailabilityOptions))
+	args.BindBackupArgs(cmd, &oArgs.Backup)
+	return cmd, nil
+}
+
+func bindAvailabilityArgs(cmd *cobra.Command, oArgs *availabilityArgs) (*cobra.Command, error) {
+	if err := args.BindPublisher(cmd, &oArgs.Publisher); err != nil {
+		return cmd, err
+	}
+
+	if err := args.BindOffer(cmd, &oArgs.Offer); err != nil {
+		return cmd, err
+	}
+
+	err := args.BindSKU(cmd, &oArgs.SKU)
+	return cmd, err
+}
+
+func validateAvailabilityArgs(cmd *cobra.Command, oArgs availabilityArgs) error {
+	flags := cmd.Flags()
+	replace := flags.Changed("regions") || oArgs.AllRegions
+	change := flags.Changed("add-regions") || flags.Changed("remove-regions")
+	if !replace && !change && !flags.Changed("clouds") {
+		return errors.New("no availability changes were specified; set at least one of --regions, --add-regions, --remove-regions, --all-regions or --clouds")
+	}
+
+	if flags.Changed("regions") && oArgs.AllRegions {
+		return errors.New("only one of --regions or --all-regions can be specified")
+	}
+
+	if replace && change {
+		return errors.New("--add-regions and --remove-regions can not be used with --regions or --all-regions")
+	}
+	return nil
+}
+
+// updatedRegions returns the regions of the plan after applying the region flags
+func updatedRegions(cmd *cobra.Command, oArgs availabilityArgs, current []string) ([]string, error) {
+	flags := cmd.Flags()
+	switch {
+	case oArgs.AllRegions:
+		return append([]string{}, partner.Markets...), nil
+	case flags.Changed("regions"):
+		return partner.ParseMarkets(oArgs.Regions)
+	case flags.Changed("add-regions") || flags.Changed("remove-regions"):
+		added, err := partner.ParseMarkets(oArgs.AddRegions)
+		if err != nil {
+			return nil, err
+		}
+
+		removed, err := partner.ParseMarkets(oArgs.RemoveRegions)
+		if err != nil {
+			return nil, err
+		}
+
+		remove := make(map[string]bool, len(removed))
+		for _, region := range removed {
+			remove[region] = true
+		}
+
+		// the current regions are kept as they are, even if they are not known markets
+		seen := make(map[string]bool, len(current)+len(added))
+		regions := make([]string, 0, len(current)+len(added))
+		for _, region := range append(current, added...) {
+			if !remove[strings.ToUpper(region)] && !seen[strings.ToUpper(region)] {
+				seen[strings.ToUpper(region)] = true
+				regions = append(regions, region)
+			}
+		}
+		sort.Strings(regions)
+		return regions, nil
+	default:
+		return current, nil
+	}
+}
+
+func getAvailabilityPlan(ctx context.Context, client service.CloudPartnerServicer, oArgs availabilityArgs) (*partner.Offer, partner.OfferType, *partner.Plan, error) {
+	offer, err := client.GetOffer(ctx, partner.ShowOfferParams{
+		PublisherID: oArgs.Publisher,
+		OfferID:     oArgs.Offer,
+	})
+
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to get offer: %v", err)
+	}
+
+	offerType, err := offer.OfferType()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	plan := offer.GetPlanByID(oArgs.SKU)
+	if plan == nil {
+		return nil, nil, nil, fmt.Errorf("no plan %s was found in offer %s", oArgs.SKU, offer.ID)
+	}
+	return offer, offerType, plan, nil
+}
+
+func getPlanAvailability(offerType partner.OfferType, plan *partner.Plan) planAvailability {
+	availability := planAvailability{Regions: plan.Regions}
+	if availability.Regions == nil {
+		availability.Regions = []string{}
+	}
+
+	if clouds, err := offerType.CloudAvailability(plan); err == nil {
+		availability.Clouds = clouds
+	}
+	return availability
+}
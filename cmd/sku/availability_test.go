@@ -0,0 +1,102 @@
+package sku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func newAvailabilityMocks(offer, updated *partner.Offer) (*test.RegistryMock, *test.CloudPartnerServiceMock, *test.PrinterMock) {
+	rm, svcMock, prtMock := test.NewOfferRegistryMocks(offer)
+	if updated != nil {
+		svcMock.On("PutOfferIfMatch", mock.Anything, updated).Return(updated, nil)
+	}
+	return rm, svcMock, prtMock
+}
+
+func TestAvailabilityCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newAvailabilityCommand, "show", "-p", "foo", "-o", "bar")
+	test.VerifyFailsOnArgs(t, newAvailabilityCommand, "set", "-p", "foo", "-o", "bar", "--regions", "US")
+}
+
+func TestAvailabilityShowCommand_Success(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	rm, _, prtMock := newAvailabilityMocks(offer, nil)
+
+	cmd, err := test.QuietCommand(newAvailabilityCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"show", "-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one"})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", planAvailability{
+		Regions: offer.Definition.Plans[0].Regions,
+		Clouds:  []partner.CloudAvailabilityOption{partner.PublicOption},
+	})
+}
+
+func TestAvailabilitySetCommand_FailOnInvalidArgs(t *testing.T) {
+	cases := map[string][]string{
+		"NoChanges":        {},
+		"UnknownRegion":    {"--regions", "US,XX"},
+		"UnknownCloud":     {"--clouds", "Moon"},
+		"RegionsAndAll":    {"--regions", "US", "--all-regions"},
+		"RegionsAndAdd":    {"--regions", "US", "--add-regions", "DE"},
+		"UnsupportedCloud": {"--clouds", "PublicAzure"},
+	}
+
+	for name, args := range cases {
+		a := args
+		t.Run(name, func(t *testing.T) {
+			offer := test.NewMarketplaceVMOffer()
+			if name == "UnsupportedCloud" {
+				offer = test.NewMarketplaceContainerOffer()
+			}
+			rm, svcMock, _ := newAvailabilityMocks(offer, nil)
+
+			cmd, err := test.QuietCommand(newAvailabilityCommand(rm))
+			require.NoError(t, err)
+			cmd.SetArgs(append([]string{"set", "-p", offer.PublisherID, "-o", offer.ID, "--sku", offer.Definition.Plans[0].ID}, a...))
+			assert.Error(t, cmd.Execute())
+			svcMock.AssertNotCalled(t, "PutOfferIfMatch", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAvailabilitySetCommand_SuccessChangesRegions(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	offer.Definition.Plans[0].Regions = []string{"DE", "US"}
+	updated := test.NewMarketplaceVMOffer()
+	updated.Definition.Plans[0].Regions = []string{"CA", "US"}
+	updated.Definition.Plans[0].PlanVirtualMachineDetail.CloudAvailability = []string{"PublicAzure", "Fairfax"}
+	rm, svcMock, prtMock := newAvailabilityMocks(offer, updated)
+
+	cmd, err := test.QuietCommand(newAvailabilityCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"set", "-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one", "--add-regions", "ca,US", "--remove-regions", "DE", "--clouds", "publicazure,Fairfax"})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, updated)
+	prtMock.AssertCalled(t, "Print", planAvailability{
+		Regions: []string{"CA", "US"},
+		Clouds:  []partner.CloudAvailabilityOption{partner.PublicOption, partner.GovCloud},
+	})
+}
+
+func TestAvailabilitySetCommand_WarnsOnMissingCloudDetails(t *testing.T) {
+	offer := test.NewMarketplaceCoreVMOffer()
+	offer.Definition.Plans[0].PlanCoreVMDetail.SKUDescriptionFairfax = "description"
+	updated := test.NewMarketplaceCoreVMOffer()
+	updated.Definition.Plans[0].PlanCoreVMDetail.SKUDescriptionFairfax = "description"
+	updated.Definition.Plans[0].PlanCoreVMDetail.CloudAvailability = []partner.CloudAvailabilityOption{partner.GovCloud}
+	rm, svcMock, prtMock := newAvailabilityMocks(offer, updated)
+
+	cmd, err := test.QuietCommand(newAvailabilityCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"set", "-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one", "--clouds", "Fairfax"})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOfferIfMatch", mock.Anything, updated)
+	prtMock.AssertCalled(t, "ErrPrintf", "warning: plan %s is available in %s, but %s is not set\n", []interface{}{"planId_one", partner.GovCloud, "microsoft-azure-corevm.usefulLinksFairfax"})
+}
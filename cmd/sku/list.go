@@ -41,7 +41,7 @@ func newListCommand(sl service.CommandServicer) (*cobra.Command, error) {
 				return err
 			}
 
-			return sl.GetPrinter().Print(offer.Definition.Plans)
+			return sl.GetPrinter().Print(offer.TypedPlans())
 		}),
 	}
 
@@ -3,13 +3,15 @@ package sku
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 
 	"github.com/spf13/cobra"
 
 	"github.com/devigned/pub/cmd/args"
 	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/schema"
 	"github.com/devigned/pub/pkg/service"
 	"github.com/devigned/pub/pkg/xcobra"
 )
@@ -20,6 +22,10 @@ type (
 		Offer       string
 		SkuFilePath string
 		Force       bool
+		Set         args.SetArgs
+		Template    args.TemplateArgs
+		Validate    args.ValidateArgs
+		Backup      args.BackupArgs
 	}
 )
 
@@ -27,20 +33,14 @@ func newPutCommand(sl service.CommandServicer) (*cobra.Command, error) {
 	var oArgs putPlanArgs
 	cmd := &cobra.Command{
 		Use:   "put",
-		Short: "create a SKU",
+		Short: "create one or more SKUs",
 		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
-			bits, err := ioutil.ReadFile(oArgs.SkuFilePath)
+			plans, err := readPlans(sl, oArgs, cmd.InOrStdin())
 			if err != nil {
 				sl.GetPrinter().ErrPrintf("%v\n", err)
 				return err
 			}
 
-			var plan partner.Plan
-			if err := json.Unmarshal(bits, &plan); err != nil {
-				sl.GetPrinter().ErrPrintf("unable to unmarshal JSON from sku file into an object: %v", err)
-				return err
-			}
-
 			client, err := sl.GetCloudPartnerService()
 			if err != nil {
 				sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
@@ -57,12 +57,21 @@ func newPutCommand(sl service.CommandServicer) (*cobra.Command, error) {
 				return err
 			}
 
-			if !oArgs.Force && offer.GetPlanByID(plan.ID) != nil {
-				warning := fmt.Sprintf("Plan '%v' already exists for offer '%v'", plan.ID, oArgs.Offer)
-				return sl.GetPrinter().Print(warning)
+			for _, plan := range plans {
+				if !oArgs.Force && offer.GetPlanByID(plan.ID) != nil {
+					warning := fmt.Sprintf("Plan '%v' already exists for offer '%v'", plan.ID, oArgs.Offer)
+					return sl.GetPrinter().Print(warning)
+				}
+			}
+
+			if err := oArgs.Backup.Save(offer); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
 			}
 
-			offer.SetPlanByID(plan)
+			for _, plan := range plans {
+				offer.SetPlanByID(plan)
+			}
 
 			updatedOffer, err := client.PutOffer(ctx, offer)
 			if err != nil {
@@ -82,12 +91,48 @@ func newPutCommand(sl service.CommandServicer) (*cobra.Command, error) {
 		return cmd, err
 	}
 
-	cmd.Flags().StringVarP(&oArgs.SkuFilePath, "sku-file", "f", "", "File path to the JSON file containing the SKU")
+	cmd.Flags().StringVarP(&oArgs.SkuFilePath, "sku-file", "f", "", "File path to the JSON or YAML file containing the SKU, or - for stdin. A YAML file may contain several SKUs as separate documents.")
 	if err := cmd.MarkFlagRequired("sku-file"); err != nil {
 		return cmd, err
 	}
 
 	cmd.Flags().BoolVarP(&oArgs.Force, "force", "", false, "Overwrite existing SKU if a SKU with the same ID already exists")
+	args.BindSetArgs(cmd, &oArgs.Set)
+	args.BindTemplateArgs(cmd, &oArgs.Template)
+	args.BindValidateArgs(cmd, &oArgs.Validate)
+	args.BindBackupArgs(cmd, &oArgs.Backup)
 
 	return cmd, nil
 }
+
+// readPlans renders and validates the sku file, reads one plan from JSON or one plan per document from YAML, and applies the set
+// values to each of them
+func readPlans(sl service.CommandServicer, oArgs putPlanArgs, stdin io.Reader) ([]partner.Plan, error) {
+	docs, err := oArgs.Template.ReadValidJSON(oArgs.SkuFilePath, stdin, oArgs.Validate.Validator(oArgs.SkuFilePath, schema.Plan(), sl))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, errors.New("no SKUs were found in the sku file")
+	}
+
+	plans := make([]partner.Plan, len(docs))
+	seen := make(map[string]bool, len(docs))
+	for i, bits := range docs {
+		bits, err = oArgs.Set.ApplyJSON(bits)
+		if err != nil {
+			return nil, fmt.Errorf("unable to apply set values: %v", err)
+		}
+
+		if err := json.Unmarshal(bits, &plans[i]); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal JSON from sku file into an object: %v", err)
+		}
+
+		if seen[plans[i].ID] {
+			return nil, fmt.Errorf("SKU %s is specified more than once", plans[i].ID)
+		}
+		seen[plans[i].ID] = true
+	}
+	return plans, nil
+}
@@ -2,13 +2,16 @@ package sku
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/backup"
 	"github.com/devigned/pub/pkg/partner"
 )
 
@@ -210,3 +213,208 @@ func TestPutCommand_SuccessPutAdditionalPlan(t *testing.T) {
 	assert.NoError(t, cmd.Execute())
 	prtMock.AssertCalled(t, "Print", expectedOffer)
 }
+
+func TestPutCommand_SuccessWithSetValues(t *testing.T) {
+	plan, skuFileName, del := test.NewTmpSKUFile(t, "sku", "second_sku", "skuSummary")
+	defer del()
+
+	plan.PlanVirtualMachineDetail.SKUTitle = "Second SKU"
+	plan.PlanVirtualMachineDetail.SupportsAcceleratedNetworking = to.BoolPtr(true)
+	plan.Regions = append(plan.Regions, "XX")
+	expectedOffer := test.NewMarketplaceVMOffer()
+	expectedOffer.Definition.Plans = append(expectedOffer.Definition.Plans, plan)
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(test.NewMarketplaceVMOffer(), nil)
+	svcMock.On("PutOffer", mock.Anything, expectedOffer).Return(expectedOffer, nil)
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", expectedOffer).Return(nil)
+
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", "foo", "-o", "bar", "-f", skuFileName,
+		"--set", "microsoft-azure-virtualmachines.skuTitle=Second SKU",
+		"--set", "microsoft-azure-virtualmachines.supportsAcceleratedNetworking=true",
+		"--set-string", "regions[]=XX",
+	})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", expectedOffer)
+}
+
+func TestPutCommand_SuccessPutMultipleYAMLPlans(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	offer.Definition.Plans = nil
+
+	expectedOffer := test.NewMarketplaceVMOffer()
+	expectedOffer.Definition.Plans = []partner.Plan{
+		{ID: "yaml_one", Regions: []string{"US"}},
+		{ID: "yaml_two", PlanVirtualMachineDetail: partner.PlanVirtualMachineDetail{SKUTitle: "Two"}},
+	}
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(offer, nil)
+	svcMock.On("PutOffer", mock.Anything, expectedOffer).Return(expectedOffer, nil)
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", expectedOffer).Return(nil)
+
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetIn(strings.NewReader(`
+# generated SKUs
+planId: yaml_one
+regions: [US]
+---
+planId: yaml_two
+microsoft-azure-virtualmachines.skuTitle: Two
+`))
+	cmd.SetArgs([]string{"-p", "foo", "-o", "bar", "-f", "-"})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", expectedOffer)
+}
+
+func TestPutCommand_FailOnDuplicateYAMLPlans(t *testing.T) {
+	skuFileName, del := test.NewTmpFileWithContent(t, "skus*.yaml", "planId: one\n---\nplanId: one\n")
+	defer del()
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", "foo", "-o", "bar", "-f", skuFileName})
+	assert.Error(t, cmd.Execute())
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestPutCommand_FailOnSchemaViolation(t *testing.T) {
+	skuFileName, del := test.NewTmpFileWithContent(t, "skus*.yaml", "planId: one\nmicrosoft-azure-corevm.deploymentModels:\n  - Classic\n")
+	defer del()
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", "foo", "-o", "bar", "-f", skuFileName})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), skuFileName+":3:3:")
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestPutCommand_StrictFailsOnUnknownKeys(t *testing.T) {
+	skuFileName, del := test.NewTmpFileWithContent(t, "skus*.yaml", "planId: one\nmicrosoft-azure-corevm.skuTitel: One\n")
+	defer del()
+
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", "foo", "-o", "bar", "-f", skuFileName})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did you mean microsoft-azure-corevm.skuTitle?")
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestPutCommand_NotStrictIgnoresUnknownKeys(t *testing.T) {
+	skuFileName, del := test.NewTmpFileWithContent(t, "skus*.yaml", "planId: one\nmicrosoft-azure-corevm.skuTitel: One\n")
+	defer del()
+
+	offer := test.NewMarketplaceVMOffer()
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, partner.ShowOfferParams{PublisherID: "foo", OfferID: "bar"}).Return(offer, nil)
+	svcMock.On("PutOffer", mock.Anything, offer).Return(offer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", offer).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", "foo", "-o", "bar", "-f", skuFileName, "--strict=false"})
+	require.NoError(t, cmd.Execute())
+	assert.NotNil(t, offer.GetPlanByID("one"))
+}
+
+func TestPutCommand_StdinIsNotStrictByDefault(t *testing.T) {
+	cases := map[string]struct {
+		args    []string
+		wantErr bool
+	}{
+		"default": {},
+		"strict":  {args: []string{"--strict"}, wantErr: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			offer := test.NewMarketplaceVMOffer()
+			rm, svcMock, _ := test.NewOfferRegistryMocks(offer)
+			svcMock.On("PutOffer", mock.Anything, offer).Return(offer, nil)
+
+			cmd, err := test.QuietCommand(newPutCommand(rm))
+			require.NoError(t, err)
+			cmd.SetIn(strings.NewReader("planId: one\nmicrosoft-azure-corevm.skuTitel: One\n"))
+			cmd.SetArgs(append([]string{"-p", offer.PublisherID, "-o", offer.ID, "-f", "-", "--no-backup"}, c.args...))
+			err = cmd.Execute()
+			if c.wantErr {
+				require.Error(t, err)
+				svcMock.AssertNotCalled(t, "PutOffer", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			svcMock.AssertCalled(t, "PutOffer", mock.Anything, offer)
+		})
+	}
+}
+
+func TestPutCommand_BacksUpOfferBeforePut(t *testing.T) {
+	_, skuFileName, del := test.NewTmpSKUFile(t, "sku", "first_sku", "skuSummary")
+	defer del()
+
+	dir, delDir := test.NewTmpDir(t)
+	defer delDir()
+
+	offer := test.NewMarketplaceVMOffer()
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(offer, nil)
+	svcMock.On("PutOffer", mock.Anything, mock.Anything).Return(offer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "-f", skuFileName, "--backup-dir", dir})
+	require.NoError(t, cmd.Execute())
+
+	store := &backup.Store{Dir: dir}
+	backups, err := store.List(offer.PublisherID, offer.ID)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	saved, err := store.Load(offer.PublisherID, offer.ID, backups[0].ID)
+	require.NoError(t, err)
+	require.Len(t, saved.Definition.Plans, 1)
+	assert.Equal(t, "planId_one", saved.Definition.Plans[0].ID)
+}
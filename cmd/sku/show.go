@@ -45,7 +45,7 @@ func newShowCommand(sl service.CommandServicer) (*cobra.Command, error) {
 			plan := offer.GetPlanByID(oArgs.SKU)
 
 			if plan != nil {
-				return sl.GetPrinter().Print(plan)
+				return sl.GetPrinter().Print(offer.TypedPlan(*plan))
 			}
 
 			return sl.GetPrinter().Print("no SKU found")
@@ -1,6 +1,7 @@
 package sku
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -54,7 +55,7 @@ func TestShowCommand_Success(t *testing.T) {
 	}).Return(offer, nil)
 
 	prtMock := new(test.PrinterMock)
-	prtMock.On("Print", &offer.Definition.Plans[0]).Return(nil)
+	prtMock.On("Print", offer.TypedPlan(offer.Definition.Plans[0])).Return(nil)
 
 	rm := new(test.RegistryMock)
 	rm.On("GetCloudPartnerService").Return(svcMock, nil)
@@ -65,3 +66,30 @@ func TestShowCommand_Success(t *testing.T) {
 	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "-s", offer.Definition.Plans[0].ID})
 	assert.NoError(t, cmd.Execute())
 }
+
+func TestShowCommand_SuccessWithContainerOffer(t *testing.T) {
+	offer := test.NewMarketplaceContainerOffer()
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(offer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newShowCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "-s", "container"})
+	assert.NoError(t, cmd.Execute())
+
+	bits, err := json.Marshal(prtMock.Calls[0].Arguments.Get(0))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"planId": "container",
+		"microsoft-azure-containers.skuTitle": "skuTitle",
+		"microsoft-azure-containers.skuSummary": "skuSummary",
+		"microsoft-azure-containers.imageRepository": "contoso/app",
+		"microsoft-azure-containers.imageTags": ["1.0.0", "latest"]
+	}`, string(bits))
+}
@@ -43,11 +43,17 @@ func newListCommand(sl service.CommandServicer) (*cobra.Command, error) {
 				return err
 			}
 
-			var versions map[string]partner.VirtualMachineImage
-			for _, plan := range offer.Definition.Plans {
-				if plan.ID == oArgs.SKU {
-					versions = plan.GetVMImages()
-					break
+			offerType, err := offer.OfferType()
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			var versions partner.VirtualMachineImages
+			if plan := offer.GetPlanByID(oArgs.SKU); plan != nil {
+				if versions, err = offerType.ImageVersions(plan); err != nil {
+					sl.GetPrinter().ErrPrintf("%v\n", err)
+					return err
 				}
 			}
 
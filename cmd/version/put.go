@@ -2,6 +2,7 @@ package version
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/spf13/cobra"
 
@@ -19,15 +20,29 @@ type (
 		Publisher string
 		Offer     string
 		SKU       string
-		Version   string
+		Version   args.VersionOrNext
 		Image     partner.VirtualMachineImage
+		ShowInGui bool
+		vhdValidationArgs
+		SkipVHDValidation bool
+		Backup            args.BackupArgs
 	}
 )
 
 func newPutCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs putImageVersionsArgs
 	cmd := &cobra.Command{
 		Use:   "put",
 		Short: "put a version for a given plan",
+		Long: `put a vm image version for a given plan
+
+The image version is put into the plan details for the offer type, so the same command works for virtual machine
+and core VM offers. Offer types without VM images, like Azure Applications, are rejected.`,
+		Run: getAndPutMutatedPlan(sl, &oArgs),
+	}
+
+	if _, err := bindPutArgs(cmd, &oArgs); err != nil {
+		return cmd, err
 	}
 
 	imageCmd, err := newPutImageCmd(sl)
@@ -48,14 +63,10 @@ func newPutCommand(sl service.CommandServicer) (*cobra.Command, error) {
 func newPutImageCmd(sl service.CommandServicer) (*cobra.Command, error) {
 	var oArgs putImageVersionsArgs
 	cmd := &cobra.Command{
-		Use:   "image",
-		Short: "put a vm image version for a given plan",
-		Run: getAndPutMutatedPlan(sl, &oArgs, func(plan *partner.Plan, version string, vm partner.VirtualMachineImage) {
-			if plan.PlanVirtualMachineDetail.VMImages == nil {
-				plan.PlanVirtualMachineDetail.VMImages = make(map[string]partner.VirtualMachineImage)
-			}
-			plan.PlanVirtualMachineDetail.VMImages[version] = vm
-		}),
+		Use:        "image",
+		Short:      "put a vm image version for a given plan",
+		Deprecated: "the offer type is detected, use `versions put` instead",
+		Run:        getAndPutMutatedPlan(sl, &oArgs),
 	}
 
 	cmd, err := bindPutArgs(cmd, &oArgs)
@@ -65,23 +76,12 @@ func newPutImageCmd(sl service.CommandServicer) (*cobra.Command, error) {
 func newPutCoreImageCmd(sl service.CommandServicer) (*cobra.Command, error) {
 	var oArgs putImageVersionsArgs
 	cmd := &cobra.Command{
-		Use:   "corevm",
-		Short: "put a vm image version for a given plan",
-		Run: getAndPutMutatedPlan(sl, &oArgs, func(plan *partner.Plan, version string, vm partner.VirtualMachineImage) {
-			if plan.PlanCoreVMDetail.VMImages == nil {
-				plan.PlanCoreVMDetail.VMImages = make(map[string]partner.VirtualMachineImage)
-			}
-
-			plan.PlanCoreVMDetail.VMImages[version] = vm
-		}),
+		Use:        "corevm",
+		Short:      "put a vm image version for a given plan",
+		Deprecated: "the offer type is detected, use `versions put` instead",
+		Run:        getAndPutMutatedPlan(sl, &oArgs),
 	}
 
-	cmd.Flags().StringVar(&oArgs.Image.MediaName, "media-name", "", "(optional) Name of the vm image (only used for CoreVM Type)")
-	cmd.Flags().StringVar(&oArgs.Image.Label, "label", "", "(optional) Label of the vm image (only used for CoreVM Type)")
-	cmd.Flags().StringVar(&oArgs.Image.Description, "desc", "", "(optional) Description of the vm image (only used for CoreVM Type)")
-	oArgs.Image.ShowInGui = to.BoolPtr(false)
-	cmd.Flags().BoolVar(oArgs.Image.ShowInGui, "show", false, "(optional) Show in GUI (only used for CoreVM Type)")
-	cmd.Flags().StringVar(&oArgs.Image.PublishedDate, "published-date", "", "(optional) Date the image was published (only used for CoreVM Type")
 	cmd, err := bindPutArgs(cmd, &oArgs)
 	return cmd, err
 }
@@ -99,18 +99,39 @@ func bindPutArgs(cmd *cobra.Command, oArgs *putImageVersionsArgs) (*cobra.Comman
 		return cmd, err
 	}
 
-	cmd.Flags().StringVar(&oArgs.Version, "version", "", "String that uniquely identifies the version.")
-	if err := cmd.MarkFlagRequired("version"); err != nil {
+	args.BindVersionOrNext(cmd, &oArgs.Version)
+
+	cmd.Flags().StringVar(&oArgs.Image.OSVHDURL, "vhd-uri", "", "Signed Azure classic storage blob containing a captured VHD")
+	if err := cmd.MarkFlagRequired("vhd-uri"); err != nil {
 		return cmd, err
 	}
 
-	cmd.Flags().StringVar(&oArgs.Image.OSVHDURL, "vhd-uri", "", "Signed Azure classic storage blob containing a captured VHD")
-	err := cmd.MarkFlagRequired("vhd-uri")
-	return cmd, err
+	cmd.Flags().StringVar(&oArgs.Image.MediaName, "media-name", "", "(optional) Name of the vm image (only used for CoreVM Type)")
+	cmd.Flags().StringVar(&oArgs.Image.Label, "label", "", "(optional) Label of the vm image (only used for CoreVM Type)")
+	cmd.Flags().StringVar(&oArgs.Image.Description, "desc", "", "(optional) Description of the vm image (only used for CoreVM Type)")
+	cmd.Flags().BoolVar(&oArgs.ShowInGui, "show", false, "(optional) Show in GUI (only used for CoreVM Type)")
+	cmd.Flags().StringVar(&oArgs.Image.PublishedDate, "published-date", "", "(optional) Date the image was published (only used for CoreVM Type)")
+
+	bindVHDValidationArgs(cmd, &oArgs.vhdValidationArgs)
+	cmd.Flags().BoolVar(&oArgs.SkipVHDValidation, "skip-vhd-validation", false, "(optional) Skip validation of the VHD SAS URI.")
+	args.BindBackupArgs(cmd, &oArgs.Backup)
+	return cmd, nil
 }
 
-func getAndPutMutatedPlan(sl service.CommandServicer, oArgs *putImageVersionsArgs, mutator func(plan *partner.Plan, version string, vm partner.VirtualMachineImage)) func(cmd *cobra.Command, args []string) {
+func getAndPutMutatedPlan(sl service.CommandServicer, oArgs *putImageVersionsArgs) func(cmd *cobra.Command, args []string) {
 	return xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+		if err := oArgs.Version.Validate(); err != nil {
+			sl.GetPrinter().ErrPrintf("%v\n", err)
+			return err
+		}
+
+		if !oArgs.SkipVHDValidation {
+			if _, err := validateVHD(ctx, oArgs.Image.OSVHDURL, oArgs.vhdValidationArgs); err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+		}
+
 		client, err := sl.GetCloudPartnerService()
 		if err != nil {
 			sl.GetPrinter().ErrPrintf("unable to create Cloud Partner Portal client: %v", err)
@@ -127,14 +148,52 @@ func getAndPutMutatedPlan(sl service.CommandServicer, oArgs *putImageVersionsArg
 			return err
 		}
 
+		offerType, err := offer.OfferType()
+		if err != nil {
+			sl.GetPrinter().ErrPrintf("%v\n", err)
+			return err
+		}
+
+		if !offerType.SupportsImages() {
+			err := &partner.ImagesNotSupportedError{TypeID: offerType.ID()}
+			sl.GetPrinter().ErrPrintf("%v\n", err)
+			return err
+		}
+
 		plan := offer.GetPlanByID(oArgs.SKU)
 
 		if plan == nil {
-			sl.GetPrinter().ErrPrintf("no plan was found")
+			err := fmt.Errorf("no plan %s was found in offer %s", oArgs.SKU, offer.ID)
+			sl.GetPrinter().ErrPrintf("%v\n", err)
+			return err
+		}
+
+		images, err := offerType.ImageVersions(plan)
+		if err != nil {
+			sl.GetPrinter().ErrPrintf("%v\n", err)
+			return err
+		}
+
+		version, err := oArgs.Version.Resolve(images.Versions().Next)
+		if err != nil {
+			sl.GetPrinter().ErrPrintf("%v\n", err)
 			return err
 		}
 
-		mutator(plan, oArgs.Version, oArgs.Image)
+		image := oArgs.Image
+		if cmd.Flags().Changed("show") || offerType.ID() == partner.CoreVMOfferType {
+			image.ShowInGui = to.BoolPtr(oArgs.ShowInGui)
+		}
+
+		if err := oArgs.Backup.Save(offer); err != nil {
+			sl.GetPrinter().ErrPrintf("%v\n", err)
+			return err
+		}
+
+		if err := offerType.SetImageVersion(plan, version, image); err != nil {
+			sl.GetPrinter().ErrPrintf("%v\n", err)
+			return err
+		}
 
 		offer, err = client.PutOffer(ctx, offer)
 		if err != nil {
@@ -142,6 +201,15 @@ func getAndPutMutatedPlan(sl service.CommandServicer, oArgs *putImageVersionsArg
 			return err
 		}
 
-		return sl.GetPrinter().Print(offer.GetPlanByID(oArgs.SKU).GetVMImages())
+		if plan = offer.GetPlanByID(oArgs.SKU); plan == nil {
+			return sl.GetPrinter().Print(partner.VirtualMachineImages(nil))
+		}
+
+		images, err = offerType.ImageVersions(plan)
+		if err != nil {
+			sl.GetPrinter().ErrPrintf("%v\n", err)
+			return err
+		}
+		return sl.GetPrinter().Print(images)
 	})
 }
@@ -10,25 +10,28 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/backup"
 	"github.com/devigned/pub/pkg/partner"
 )
 
+var vhdURI = test.NewVHDSASURL()
+
 func TestPutCommand_FailOnInsufficientArgs(t *testing.T) {
-	test.VerifyFailsOnArgs(t, newPutCommand, "corevm", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1234")
-	test.VerifyFailsOnArgs(t, newPutCommand, "image", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1234")
+	test.VerifyFailsOnArgs(t, newPutCommand, "corevm", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1.0.0")
+	test.VerifyFailsOnArgs(t, newPutCommand, "image", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1.0.0")
 }
 
 func TestPutCommand_FailOnCloudPartnerServiceError(t *testing.T) {
 	test.VerifyCloudPartnerServiceCommand(
 		t,
 		newPutCommand,
-		"image", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1234", "--vhd-uri", "uri",
+		"image", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1.0.0", "--vhd-uri", vhdURI,
 	)
 
 	test.VerifyCloudPartnerServiceCommand(
 		t,
 		newPutCommand,
-		"corevm", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1234", "--vhd-uri", "uri",
+		"corevm", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1.0.0", "--vhd-uri", vhdURI,
 	)
 }
 
@@ -47,7 +50,7 @@ func TestPutCommand_FailOnGetOfferError(t *testing.T) {
 
 	cmd, err := test.QuietCommand(newPutCommand(rm))
 	require.NoError(t, err)
-	cmd.SetArgs([]string{"image", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1234", "--vhd-uri", "uri"})
+	cmd.SetArgs([]string{"image", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1.0.0", "--vhd-uri", vhdURI})
 	assert.Error(t, cmd.Execute())
 	prtMock.AssertCalled(t, "ErrPrintf", "unable to get offer: %v", []interface{}{boomErr})
 }
@@ -69,7 +72,7 @@ func TestPutCommand_FailOnPutOfferError(t *testing.T) {
 
 	cmd, err := test.QuietCommand(newPutCommand(rm))
 	require.NoError(t, err)
-	cmd.SetArgs([]string{"image", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1234", "--vhd-uri", "uri"})
+	cmd.SetArgs([]string{"image", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1.0.0", "--vhd-uri", vhdURI})
 	assert.Error(t, cmd.Execute())
 	prtMock.AssertCalled(t, "ErrPrintf", "unable to put offer: %v", []interface{}{boomErr})
 }
@@ -90,7 +93,7 @@ func TestPutCommand_Success(t *testing.T) {
 
 	cmd, err := test.QuietCommand(newPutCommand(rm))
 	require.NoError(t, err)
-	cmd.SetArgs([]string{"image", "-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one", "--version", "1234", "--vhd-uri", "uri"})
+	cmd.SetArgs([]string{"image", "-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one", "--version", "1.0.0", "--vhd-uri", vhdURI})
 	assert.NoError(t, cmd.Execute())
 	prtMock.AssertCalled(t, "Print", offer.GetPlanByID("planId_one").GetVMImages())
 }
@@ -104,7 +107,7 @@ func TestPutCommand_SuccessCoreVm(t *testing.T) {
 		PublishedDate: "1/1/2020",
 		Label:         "label",
 		Description:   "description",
-		OSVHDURL:      "newImageUrl",
+		OSVHDURL:      vhdURI,
 	}
 	updatedOffer := test.NewMarketplaceCoreVMOffer()
 	updatedOffer.Definition.Plans[0].PlanCoreVMDetail.VMImages["2020.01.01"] = newVMImage
@@ -122,7 +125,149 @@ func TestPutCommand_SuccessCoreVm(t *testing.T) {
 
 	cmd, err := test.QuietCommand(newPutCommand(rm))
 	require.NoError(t, err)
-	cmd.SetArgs([]string{"corevm", "-p", offer.PublisherID, "-o", offer.ID, "-s", "planId_one", "--version", "2020.01.01", "--vhd-uri", "newImageUrl", "--media-name", "newImageName", "--label", "label", "--desc", "description", "--published-date", "1/1/2020"})
+	cmd.SetArgs([]string{"corevm", "-p", offer.PublisherID, "-o", offer.ID, "-s", "planId_one", "--version", "2020.01.01", "--vhd-uri", vhdURI, "--media-name", "newImageName", "--label", "label", "--desc", "description", "--published-date", "1/1/2020"})
 	assert.NoError(t, cmd.Execute())
 	prtMock.AssertCalled(t, "Print", updatedOffer.GetPlanByID("planId_one").GetVMImages())
 }
+
+func TestPutCommand_FailOnInvalidVersion(t *testing.T) {
+	rm := new(test.RegistryMock)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"image", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1234", "--vhd-uri", vhdURI})
+	assert.Error(t, cmd.Execute())
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestPutCommand_FailOnVersionAndNext(t *testing.T) {
+	rm := new(test.RegistryMock)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"image", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1.0.0", "--next", "patch", "--vhd-uri", vhdURI})
+	assert.Error(t, cmd.Execute())
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestPutCommand_SuccessWithNext(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	updatedOffer := test.NewMarketplaceVMOffer()
+	updatedOffer.Definition.Plans[0].PlanVirtualMachineDetail.VMImages["2019.11.0"] = partner.VirtualMachineImage{
+		OSVHDURL: vhdURI,
+	}
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(offer, nil)
+	svcMock.On("PutOffer", mock.Anything, updatedOffer).Return(updatedOffer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", updatedOffer.GetPlanByID("planId_one").GetVMImages()).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"image", "-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one", "--next", "minor", "--vhd-uri", vhdURI})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertCalled(t, "Print", updatedOffer.GetPlanByID("planId_one").GetVMImages())
+}
+
+func TestPutCommand_FailOnInvalidVHDURI(t *testing.T) {
+	rm := new(test.RegistryMock)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"image", "-p", "foo", "-o", "bar", "--sku", "planId_one", "--version", "1.0.0", "--vhd-uri", "uri"})
+	assert.Error(t, cmd.Execute())
+	rm.AssertNotCalled(t, "GetCloudPartnerService")
+}
+
+func TestPutCommand_SuccessDetectsOfferType(t *testing.T) {
+	offer := test.NewMarketplaceCoreVMOffer()
+	updatedOffer := test.NewMarketplaceCoreVMOffer()
+	updatedOffer.Definition.Plans[0].PlanCoreVMDetail.VMImages["2020.1.0"] = partner.VirtualMachineImage{
+		MediaName: "newImageName",
+		ShowInGui: to.BoolPtr(true),
+		OSVHDURL:  vhdURI,
+	}
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(offer, nil)
+	svcMock.On("PutOffer", mock.Anything, updatedOffer).Return(updatedOffer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", partner.VirtualMachineImages(updatedOffer.Definition.Plans[0].PlanCoreVMDetail.VMImages)).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "-s", "planId_one", "--version", "2020.1.0", "--vhd-uri", vhdURI, "--media-name", "newImageName", "--show"})
+	assert.NoError(t, cmd.Execute())
+	svcMock.AssertCalled(t, "PutOffer", mock.Anything, updatedOffer)
+	prtMock.AssertCalled(t, "Print", partner.VirtualMachineImages(updatedOffer.Definition.Plans[0].PlanCoreVMDetail.VMImages))
+}
+
+func TestPutCommand_FailOnOfferTypeWithoutImages(t *testing.T) {
+	offer := test.NewMarketplaceApplicationOffer()
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(offer, nil)
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newPutCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"-p", offer.PublisherID, "-o", offer.ID, "-s", "managed", "--version", "1.0.0", "--vhd-uri", vhdURI})
+	err = cmd.Execute()
+	assert.EqualError(t, err, "offer type microsoft-azure-applications does not support image versions")
+	svcMock.AssertNotCalled(t, "PutOffer", mock.Anything, mock.Anything)
+}
+
+func TestPutCommand_BacksUpOfferUnlessSkipped(t *testing.T) {
+	cases := map[string]struct {
+		args    []string
+		backups int
+	}{
+		"backup":    {backups: 1},
+		"no backup": {args: []string{"--no-backup"}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, del := test.NewTmpDir(t)
+			defer del()
+
+			offer := test.NewMarketplaceVMOffer()
+			svcMock := new(test.CloudPartnerServiceMock)
+			svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(offer, nil)
+			svcMock.On("PutOffer", mock.Anything, offer).Return(offer, nil)
+			prtMock := new(test.PrinterMock)
+			prtMock.On("Print", mock.Anything).Return(nil)
+			rm := new(test.RegistryMock)
+			rm.On("GetCloudPartnerService").Return(svcMock, nil)
+			rm.On("GetPrinter").Return(prtMock)
+
+			cmd, err := test.QuietCommand(newPutCommand(rm))
+			require.NoError(t, err)
+			cmd.SetArgs(append([]string{"-p", offer.PublisherID, "-o", offer.ID, "--sku", "planId_one", "--version", "2.0.0", "--vhd-uri", vhdURI, "--backup-dir", dir}, c.args...))
+			require.NoError(t, cmd.Execute())
+
+			backups, err := (&backup.Store{Dir: dir}).List(offer.PublisherID, offer.ID)
+			require.NoError(t, err)
+			assert.Len(t, backups, c.backups)
+		})
+	}
+}
@@ -18,6 +18,7 @@ func NewRootCmd(sl service.CommandServicer) (*cobra.Command, error) {
 		newListCommand,
 		newShowCommand,
 		newPutCommand,
+		newValidateCommand,
 	}
 
 	for _, f := range cmdFuncs {
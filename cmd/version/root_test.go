@@ -15,7 +15,7 @@ func TestNewRootCmd(t *testing.T) {
 	cmd, err := version.NewRootCmd(regMock)
 	require.NoError(t, err)
 
-	expected := []string{"list", "put", "show"}
+	expected := []string{"list", "put", "show", "validate"}
 	actual := make([]string, len(cmd.Commands()))
 	for i, c := range cmd.Commands() {
 		actual[i] = c.Name()
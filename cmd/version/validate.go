@@ -0,0 +1,80 @@
+package version
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devigned/pub/pkg/service"
+	"github.com/devigned/pub/pkg/vhd"
+	"github.com/devigned/pub/pkg/xcobra"
+)
+
+type (
+	vhdValidationArgs struct {
+		MinExpiry time.Duration
+		CheckBlob bool
+	}
+
+	validateVHDArgs struct {
+		VHDURI string
+		vhdValidationArgs
+	}
+
+	// vhdReport is the result of validating a VHD SAS URL
+	vhdReport struct {
+		SAS  *vhd.SASInfo  `json:"sas,omitempty"`
+		Blob *vhd.BlobInfo `json:"blob,omitempty"`
+	}
+)
+
+func newValidateCommand(sl service.CommandServicer) (*cobra.Command, error) {
+	var oArgs validateVHDArgs
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "validate a VHD SAS URI before putting it into a version",
+		Run: xcobra.RunWithCtx(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			report, err := validateVHD(ctx, oArgs.VHDURI, oArgs.vhdValidationArgs)
+			if err != nil {
+				sl.GetPrinter().ErrPrintf("%v\n", err)
+				return err
+			}
+
+			return sl.GetPrinter().Print(report)
+		}),
+	}
+
+	cmd.Flags().StringVar(&oArgs.VHDURI, "vhd-uri", "", "Signed Azure classic storage blob containing a captured VHD")
+	if err := cmd.MarkFlagRequired("vhd-uri"); err != nil {
+		return cmd, err
+	}
+
+	bindVHDValidationArgs(cmd, &oArgs.vhdValidationArgs)
+	return cmd, nil
+}
+
+func bindVHDValidationArgs(cmd *cobra.Command, oArgs *vhdValidationArgs) {
+	cmd.Flags().DurationVar(&oArgs.MinExpiry, "min-sas-expiry", vhd.DefaultMinExpiry, "Minimum remaining lifetime of the VHD SAS token.")
+	cmd.Flags().BoolVar(&oArgs.CheckBlob, "check-blob", false, "(optional) Issue a HEAD request to verify the VHD blob exists and its size is 1 MB aligned.")
+}
+
+func validateVHD(ctx context.Context, uri string, oArgs vhdValidationArgs) (*vhdReport, error) {
+	sas, err := vhd.ValidateSASURL(uri, vhd.Options{MinExpiry: oArgs.MinExpiry})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &vhdReport{SAS: sas}
+	if !oArgs.CheckBlob {
+		return report, nil
+	}
+
+	blob, err := vhd.CheckBlob(ctx, &http.Client{Timeout: 30 * time.Second}, uri)
+	if err != nil {
+		return nil, err
+	}
+	report.Blob = blob
+	return report, nil
+}
@@ -0,0 +1,40 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+)
+
+func TestValidateCommand_FailOnInsufficientArgs(t *testing.T) {
+	test.VerifyFailsOnArgs(t, newValidateCommand)
+}
+
+func TestValidateCommand_FailOnExpiringSAS(t *testing.T) {
+	prtMock := new(test.PrinterMock)
+	prtMock.On("ErrPrintf", "%v\n", mock.Anything).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newValidateCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"--vhd-uri", vhdURI, "--min-sas-expiry", "2000h"})
+	assert.Error(t, cmd.Execute())
+}
+
+func TestValidateCommand_Success(t *testing.T) {
+	prtMock := new(test.PrinterMock)
+	prtMock.On("Print", mock.AnythingOfType("*version.vhdReport")).Return(nil)
+	rm := new(test.RegistryMock)
+	rm.On("GetPrinter").Return(prtMock)
+
+	cmd, err := test.QuietCommand(newValidateCommand(rm))
+	require.NoError(t, err)
+	cmd.SetArgs([]string{"--vhd-uri", vhdURI})
+	assert.NoError(t, cmd.Execute())
+	prtMock.AssertNumberOfCalls(t, "Print", 1)
+}
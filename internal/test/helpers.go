@@ -6,11 +6,13 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/devigned/pub/pkg/backup"
 	"github.com/devigned/pub/pkg/partner"
 	"github.com/devigned/pub/pkg/service"
 )
@@ -96,3 +98,52 @@ func NewTmpFile(t *testing.T, prefix string) (string, func()) {
 		_ = os.Remove(f.Name())
 	}
 }
+
+// NewVHDSASURL returns a VHD container SAS URL which passes validation for the next 60 days
+func NewVHDSASURL() string {
+	expiry := time.Now().UTC().Add(60 * 24 * time.Hour).Format("2006-01-02T15:04:05Z")
+	return fmt.Sprintf("https://account.blob.core.windows.net/vhds/image.vhd?sv=2019-02-02&sr=c&sp=rl&se=%s&sig=c2lnbmF0dXJl", expiry)
+}
+
+// NewTmpPolicyFile writes the policy to a temporary file
+func NewTmpPolicyFile(t *testing.T, prefix, policy string) (string, func()) {
+	return NewTmpFileWithContent(t, prefix, policy)
+}
+
+// NewTmpFileWithContent writes the content to a temporary file
+func NewTmpFileWithContent(t *testing.T, prefix, content string) (string, func()) {
+	f, err := ioutil.TempFile("", prefix)
+	require.NoError(t, err)
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	return f.Name(), func() {
+		_ = os.Remove(f.Name())
+	}
+}
+
+// NewTmpDir creates a temporary directory which is removed with everything in it by the returned func
+func NewTmpDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "pub")
+	require.NoError(t, err)
+	return dir, func() {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// RunWithTmpBackupDir runs the tests of a package which put offers with their backups saved to a temporary directory,
+// rather than to the home directory
+func RunWithTmpBackupDir(m *testing.M) int {
+	dir, err := ioutil.TempDir("", "pub-backups")
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	if err := os.Setenv(backup.DirEnvVar, dir); err != nil {
+		panic(err)
+	}
+	return m.Run()
+}
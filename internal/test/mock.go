@@ -82,6 +82,11 @@ func (cpsm *CloudPartnerServiceMock) PutOffer(ctx context.Context, offer *partne
 	return args.Get(0).(*partner.Offer), args.Error(1)
 }
 
+func (cpsm *CloudPartnerServiceMock) PutOfferIfMatch(ctx context.Context, offer *partner.Offer) (*partner.Offer, error) {
+	args := cpsm.Called(ctx, offer)
+	return args.Get(0).(*partner.Offer), args.Error(1)
+}
+
 func (cpsm *CloudPartnerServiceMock) PublishOffer(ctx context.Context, params partner.PublishOfferParams) (string, error) {
 	args := cpsm.Called(ctx, params)
 	return args.Get(0).(string), args.Error(1)
@@ -112,6 +117,29 @@ func (cpsm *CloudPartnerServiceMock) ListPublishers(ctx context.Context) ([]part
 	return args.Get(0).([]partner.Publisher), args.Error(1)
 }
 
+// NewRegistryMocks returns a registry for a Cloud Partner Portal service mock with no expectations, and for a printer
+// mock which accepts any output
+func NewRegistryMocks() (*RegistryMock, *CloudPartnerServiceMock, *PrinterMock) {
+	svcMock := new(CloudPartnerServiceMock)
+	prtMock := new(PrinterMock)
+	prtMock.On("ErrPrintf", mock.Anything, mock.Anything).Return(nil)
+	prtMock.On("Print", mock.Anything).Return(nil)
+	rm := new(RegistryMock)
+	rm.On("GetCloudPartnerService").Return(svcMock, nil)
+	rm.On("GetPrinter").Return(prtMock)
+	return rm, svcMock, prtMock
+}
+
+// NewOfferRegistryMocks returns the mocks of NewRegistryMocks, with a service which returns the offer from GetOffer
+func NewOfferRegistryMocks(offer *partner.Offer) (*RegistryMock, *CloudPartnerServiceMock, *PrinterMock) {
+	rm, svcMock, prtMock := NewRegistryMocks()
+	svcMock.On("GetOffer", mock.Anything, partner.ShowOfferParams{
+		PublisherID: offer.PublisherID,
+		OfferID:     offer.ID,
+	}).Return(offer, nil)
+	return rm, svcMock, prtMock
+}
+
 // NewMarketplaceVMOffer returns a valid offer for testing for virtualmachine scenarios
 func NewMarketplaceVMOffer() *partner.Offer {
 	changed, _ := date.ParseTime(time.RFC3339Nano, "2019-10-30T22:03:51.2917913Z")
@@ -272,3 +300,144 @@ func NewMarketplaceCoreVMOffer() *partner.Offer {
 		Etag:        "W/\"datetime'2019-10-30T22%3A03%3A51.6562051Z'\"",
 	}
 }
+
+// NewMarketplaceApplicationOffer returns a valid offer for testing for Azure Application scenarios
+func NewMarketplaceApplicationOffer() *partner.Offer {
+	changed, _ := date.ParseTime(time.RFC3339Nano, "2019-10-30T22:03:51.2917913Z")
+
+	return &partner.Offer{
+		Entity: partner.Entity{
+			ID:      "test",
+			Version: 4,
+		},
+		TypeID:      "microsoft-azure-applications",
+		PublisherID: "publisherId",
+		Status:      "succeeded",
+		Definition: partner.OfferDefinition{
+			DisplayText: "displayText",
+			OfferDetail: &partner.OfferDetail{
+				MarketplaceDetail: partner.MarketplaceDetail{
+					Title:                "title",
+					Summary:              "summary",
+					LongSummary:          "longSummary",
+					Description:          "description",
+					AllowedSubscriptions: []string{"4145cbfe-cd94-439d-aa3c-1ec6c7e53074"},
+					SmallLogo:            "smallLogo",
+					MediumLogo:           "mediumLogo",
+					PrivacyURL:           "https://contoso.com/privacy",
+					SupportContactEmail:  "support@contoso.com",
+				},
+			},
+			Plans: []partner.Plan{
+				{
+					ID: "managed",
+					PlanApplicationDetail: partner.PlanApplicationDetail{
+						SKUTitle:          "skuTitle",
+						SKUSummary:        "skuSummary",
+						SKUDescription:    "skuDescription",
+						SolutionType:      partner.ManagedApplicationType,
+						CloudAvailability: []string{"PublicAzure"},
+						Packages: map[string]partner.ApplicationPackage{
+							"1.0.0": {
+								PackageURI: "https://account.blob.core.windows.net/packages/app-1.0.0.zip",
+							},
+							"1.1.0": {
+								PackageURI:  "https://account.blob.core.windows.net/packages/app-1.1.0.zip",
+								Description: "description",
+							},
+						},
+						Authorizations: []partner.ApplicationAuthorization{
+							{
+								PrincipalID:      "9f4f6b3e-7c6f-4a8d-9d5c-0b7b0c9a2f10",
+								RoleDefinitionID: "8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+							},
+						},
+						AllowedCustomerActions: []string{"Microsoft.Compute/virtualMachines/restart/action"},
+					},
+				},
+			},
+		},
+		ChangedTime: date.Time{Time: changed},
+		Etag:        "W/\"datetime'2019-10-30T22%3A03%3A51.6562051Z'\"",
+	}
+}
+
+// NewMarketplaceContainerOffer returns a valid offer for testing for container scenarios
+func NewMarketplaceContainerOffer() *partner.Offer {
+	return &partner.Offer{
+		Entity: partner.Entity{
+			ID:      "test",
+			Version: 2,
+		},
+		TypeID:      "microsoft-azure-containers",
+		PublisherID: "publisherId",
+		Status:      "succeeded",
+		Definition: partner.OfferDefinition{
+			DisplayText: "displayText",
+			OfferDetail: &partner.OfferDetail{
+				MarketplaceDetail: partner.MarketplaceDetail{
+					Title:      "title",
+					Summary:    "summary",
+					SmallLogo:  "smallLogo",
+					MediumLogo: "mediumLogo",
+					PrivacyURL: "https://contoso.com/privacy",
+				},
+			},
+			Plans: []partner.Plan{
+				{
+					ID: "container",
+					PlanContainerDetail: partner.PlanContainerDetail{
+						SKUTitle:        "skuTitle",
+						SKUSummary:      "skuSummary",
+						ImageRepository: "contoso/app",
+						ImageTags:       []string{"1.0.0", "latest"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// NewMarketplaceSaaSOffer returns a valid offer for testing for SaaS scenarios
+func NewMarketplaceSaaSOffer() *partner.Offer {
+	return &partner.Offer{
+		Entity: partner.Entity{
+			ID:      "test",
+			Version: 3,
+		},
+		TypeID:      "microsoft-azure-saas",
+		PublisherID: "publisherId",
+		Status:      "succeeded",
+		Definition: partner.OfferDefinition{
+			DisplayText: "displayText",
+			OfferDetail: &partner.OfferDetail{
+				MarketplaceDetail: partner.MarketplaceDetail{
+					Title:      "title",
+					Summary:    "summary",
+					SmallLogo:  "smallLogo",
+					MediumLogo: "mediumLogo",
+					PrivacyURL: "https://contoso.com/privacy",
+				},
+				SaaSOfferDetail: partner.SaaSOfferDetail{
+					LandingPageURL:    "https://contoso.com/landing",
+					ConnectionWebhook: "https://contoso.com/webhook",
+				},
+			},
+			Plans: []partner.Plan{
+				{
+					ID: "basic",
+					PlanSaaSDetail: partner.PlanSaaSDetail{
+						SKUTitle:   "skuTitle",
+						SKUSummary: "skuSummary",
+						Pricing: &partner.SaaSPricing{
+							Model:       "FlatRate",
+							BillingTerm: "Monthly",
+							Currency:    "USD",
+							Price:       to.Float64Ptr(10),
+						},
+					},
+				},
+			},
+		},
+	}
+}
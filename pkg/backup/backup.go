@@ -0,0 +1,282 @@
+// Package backup saves local snapshots of offers before they are overwritten, so an accidental put can be undone
+// without the version history of the Cloud Partner Portal.
+//
+// Backups are JSON files of the offer, stored by publisher and offer in the backup directory:
+//
+//	<dir>/<publisher>/<offer>/20060102T150405.000000000Z-v3.json
+//
+// The ID of a backup is its file name without the extension, which is the UTC time it was taken and the version of
+// the offer. Each time a backup is saved, the oldest backups of the offer beyond the retention settings are removed.
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devigned/pub/pkg/partner"
+)
+
+const (
+	// DirEnvVar is the environment variable used to configure the backup directory
+	DirEnvVar = "PUB_BACKUP_DIR"
+	// KeepEnvVar is the environment variable used to configure the number of backups kept for each offer
+	KeepEnvVar = "PUB_BACKUP_KEEP"
+	// MaxAgeEnvVar is the environment variable used to configure how long backups are kept, such as 720h
+	MaxAgeEnvVar = "PUB_BACKUP_MAX_AGE"
+
+	// DefaultKeep is the number of backups kept for each offer when KeepEnvVar is not set
+	DefaultKeep = 20
+
+	timeLayout = "20060102T150405.000000000Z"
+	ext        = ".json"
+)
+
+type (
+	// Store saves and loads the backups of offers in a directory
+	Store struct {
+		Dir string
+		// Keep is the number of backups kept for each offer, or 0 to keep any number
+		Keep int
+		// MaxAge is how long backups are kept, or 0 to keep them forever. The newest backup of an offer is always kept.
+		MaxAge time.Duration
+		// Now returns the current time, and defaults to time.Now
+		Now func() time.Time
+	}
+
+	// Backup is a snapshot of an offer saved before it was overwritten
+	Backup struct {
+		ID          string    `json:"id"`
+		PublisherID string    `json:"publisherId"`
+		OfferID     string    `json:"offerId"`
+		Version     int       `json:"version"`
+		Time        time.Time `json:"time"`
+		Path        string    `json:"path"`
+	}
+)
+
+// NewStore returns a store in the directory, or in the directory of PUB_BACKUP_DIR if dir is empty, or in
+// $HOME/.pub/backups if neither is set. The retention settings are read from PUB_BACKUP_KEEP and PUB_BACKUP_MAX_AGE.
+func NewStore(dir string) (*Store, error) {
+	if dir == "" {
+		dir = os.Getenv(DirEnvVar)
+	}
+
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to find the home directory for backups; set %s: %v", DirEnvVar, err)
+		}
+		dir = filepath.Join(home, ".pub", "backups")
+	}
+
+	s := &Store{Dir: dir, Keep: DefaultKeep}
+	if keep := os.Getenv(KeepEnvVar); keep != "" {
+		n, err := strconv.Atoi(keep)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%s must be a number of backups, but is %q", KeepEnvVar, keep)
+		}
+		s.Keep = n
+	}
+
+	if maxAge := os.Getenv(MaxAgeEnvVar); maxAge != "" {
+		d, err := time.ParseDuration(maxAge)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("%s must be a duration, such as 720h, but is %q", MaxAgeEnvVar, maxAge)
+		}
+		s.MaxAge = d
+	}
+	return s, nil
+}
+
+// Save writes a backup of the offer and removes the backups of the offer beyond the retention settings
+func (s *Store) Save(offer *partner.Offer) (*Backup, error) {
+	if err := partner.CheckPathID(offer.PublisherID); err != nil {
+		return nil, err
+	}
+
+	if err := partner.CheckPathID(offer.ID); err != nil {
+		return nil, err
+	}
+
+	bits, err := json.MarshalIndent(offer, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(s.Dir, offer.PublisherID, offer.ID)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	t := s.now().UTC()
+	b := Backup{
+		ID:          fmt.Sprintf("%s-v%d", t.Format(timeLayout), offer.Version),
+		PublisherID: offer.PublisherID,
+		OfferID:     offer.ID,
+		Version:     offer.Version,
+		Time:        t,
+	}
+	b.Path = filepath.Join(dir, b.ID+ext)
+
+	if err := ioutil.WriteFile(b.Path, bits, 0600); err != nil {
+		return nil, err
+	}
+
+	if err := s.prune(offer.PublisherID, offer.ID); err != nil {
+		return &b, fmt.Errorf("backup was saved to %s, but older backups could not be removed: %v", b.Path, err)
+	}
+	return &b, nil
+}
+
+// List returns the backups of the offer, or of every offer of the publisher if offerID is empty, newest first
+func (s *Store) List(publisherID, offerID string) ([]Backup, error) {
+	if err := partner.CheckPathID(publisherID); err != nil {
+		return nil, err
+	}
+
+	offerIDs := []string{offerID}
+	if offerID == "" {
+		infos, err := ioutil.ReadDir(filepath.Join(s.Dir, publisherID))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		offerIDs = nil
+		for _, info := range infos {
+			if info.IsDir() {
+				offerIDs = append(offerIDs, info.Name())
+			}
+		}
+	} else if err := partner.CheckPathID(offerID); err != nil {
+		return nil, err
+	}
+
+	backups := []Backup{}
+	for _, id := range offerIDs {
+		offerBackups, err := s.listOffer(publisherID, id)
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, offerBackups...)
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// Load reads the offer saved in the backup with the ID
+func (s *Store) Load(publisherID, offerID, id string) (*partner.Offer, error) {
+	for _, name := range []string{publisherID, offerID, id} {
+		if err := partner.CheckPathID(name); err != nil {
+			return nil, err
+		}
+	}
+
+	path := filepath.Join(s.Dir, publisherID, offerID, id+ext)
+	bits, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("there is no backup %s of offer %s/%s in %s", id, publisherID, offerID, s.Dir)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var offer partner.Offer
+	if err := json.Unmarshal(bits, &offer); err != nil {
+		return nil, fmt.Errorf("unable to read backup %s: %v", path, err)
+	}
+	return &offer, nil
+}
+
+// listOffer returns the backups of the offer, skipping files which are not named like a backup
+func (s *Store) listOffer(publisherID, offerID string) ([]Backup, error) {
+	dir := filepath.Join(s.Dir, publisherID, offerID)
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, info := range infos {
+		if info.IsDir() || filepath.Ext(info.Name()) != ext {
+			continue
+		}
+
+		b, ok := parseID(strings.TrimSuffix(info.Name(), ext))
+		if !ok {
+			continue
+		}
+
+		b.PublisherID = publisherID
+		b.OfferID = offerID
+		b.Path = filepath.Join(dir, info.Name())
+		backups = append(backups, b)
+	}
+	return backups, nil
+}
+
+// prune removes the backups of the offer beyond Keep or older than MaxAge, but never the newest
+func (s *Store) prune(publisherID, offerID string) error {
+	backups, err := s.List(publisherID, offerID)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	for i, b := range backups {
+		if i == 0 {
+			continue
+		}
+
+		tooMany := s.Keep > 0 && i >= s.Keep
+		tooOld := s.MaxAge > 0 && now.Sub(b.Time) > s.MaxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+
+		if err := os.Remove(b.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+// parseID reads the time and version from the ID of a backup
+func parseID(id string) (Backup, bool) {
+	i := strings.LastIndex(id, "-v")
+	if i < 0 {
+		return Backup{}, false
+	}
+
+	t, err := time.Parse(timeLayout, id[:i])
+	if err != nil {
+		return Backup{}, false
+	}
+
+	version, err := strconv.Atoi(id[i+2:])
+	if err != nil {
+		return Backup{}, false
+	}
+	return Backup{ID: id, Version: version, Time: t}, true
+}
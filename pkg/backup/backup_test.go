@@ -0,0 +1,176 @@
+package backup_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/backup"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+// newClockStore returns a store in a temporary directory with a clock which moves forward a minute each time it is read
+func newClockStore(t *testing.T) (*backup.Store, func()) {
+	dir, del := test.NewTmpDir(t)
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &backup.Store{
+		Dir:  dir,
+		Keep: backup.DefaultKeep,
+		Now: func() time.Time {
+			now = now.Add(time.Minute)
+			return now
+		},
+	}, del
+}
+
+func newOffer(id string, version int) *partner.Offer {
+	offer := test.NewMarketplaceVMOffer()
+	offer.ID = id
+	offer.Version = version
+	return offer
+}
+
+func TestStore_SaveAndLoad(t *testing.T) {
+	s, del := newClockStore(t)
+	defer del()
+
+	offer := newOffer("offer", 3)
+	b, err := s.Save(offer)
+	require.NoError(t, err)
+	assert.Equal(t, "20200102T030505.000000000Z-v3", b.ID)
+	assert.Equal(t, 3, b.Version)
+	assert.Equal(t, filepath.Join(s.Dir, offer.PublisherID, "offer", b.ID+".json"), b.Path)
+
+	loaded, err := s.Load(offer.PublisherID, "offer", b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, offer.Definition, loaded.Definition)
+	assert.Equal(t, 3, loaded.Version)
+
+	_, err = s.Load(offer.PublisherID, "offer", "missing")
+	assert.Error(t, err)
+}
+
+func TestStore_List(t *testing.T) {
+	s, del := newClockStore(t)
+	defer del()
+
+	first, err := s.Save(newOffer("a", 1))
+	require.NoError(t, err)
+	second, err := s.Save(newOffer("b", 1))
+	require.NoError(t, err)
+	third, err := s.Save(newOffer("a", 2))
+	require.NoError(t, err)
+
+	// files which are not named like a backup are skipped
+	require.NoError(t, ioutil.WriteFile(filepath.Join(s.Dir, first.PublisherID, "a", "notes.json"), []byte("{}"), 0600))
+
+	backups, err := s.List(first.PublisherID, "a")
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, third.ID, backups[0].ID)
+	assert.Equal(t, first.ID, backups[1].ID)
+	assert.Equal(t, "a", backups[0].OfferID)
+	assert.Equal(t, first.PublisherID, backups[0].PublisherID)
+
+	backups, err = s.List(first.PublisherID, "")
+	require.NoError(t, err)
+	ids := make([]string, len(backups))
+	for i, b := range backups {
+		ids[i] = b.ID
+	}
+	assert.Equal(t, []string{third.ID, second.ID, first.ID}, ids)
+
+	backups, err = s.List("nobody", "")
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+}
+
+func TestStore_SaveRemovesBackupsBeyondRetention(t *testing.T) {
+	cases := map[string]struct {
+		keep   int
+		maxAge time.Duration
+		kept   []int
+	}{
+		"keep": {
+			keep: 2,
+			kept: []int{4, 3},
+		},
+		"max age": {
+			maxAge: 90 * time.Second,
+			kept:   []int{4},
+		},
+		"unlimited": {
+			kept: []int{4, 3, 2, 1},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, del := newClockStore(t)
+			defer del()
+			s.Keep = c.keep
+			s.MaxAge = c.maxAge
+
+			for version := 1; version <= 4; version++ {
+				_, err := s.Save(newOffer("offer", version))
+				require.NoError(t, err)
+			}
+
+			backups, err := s.List(test.NewMarketplaceVMOffer().PublisherID, "offer")
+			require.NoError(t, err)
+			versions := make([]int, len(backups))
+			for i, b := range backups {
+				versions[i] = b.Version
+			}
+			assert.Equal(t, c.kept, versions)
+		})
+	}
+}
+
+func TestStore_FailOnPathSeparators(t *testing.T) {
+	s, del := newClockStore(t)
+	defer del()
+
+	_, err := s.Save(newOffer("../offer", 1))
+	assert.Error(t, err)
+
+	_, err = s.List("..", "")
+	assert.Error(t, err)
+
+	_, err = s.Load("publisher", "offer", "../../secret")
+	assert.Error(t, err)
+}
+
+func TestNewStore(t *testing.T) {
+	for _, name := range []string{backup.DirEnvVar, backup.KeepEnvVar, backup.MaxAgeEnvVar} {
+		defer os.Setenv(name, os.Getenv(name))
+	}
+
+	require.NoError(t, os.Setenv(backup.DirEnvVar, "from-env"))
+	require.NoError(t, os.Setenv(backup.KeepEnvVar, "5"))
+	require.NoError(t, os.Setenv(backup.MaxAgeEnvVar, "720h"))
+	s, err := backup.NewStore("")
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", s.Dir)
+	assert.Equal(t, 5, s.Keep)
+	assert.Equal(t, 720*time.Hour, s.MaxAge)
+
+	s, err = backup.NewStore("from-flag")
+	require.NoError(t, err)
+	assert.Equal(t, "from-flag", s.Dir)
+
+	require.NoError(t, os.Setenv(backup.KeepEnvVar, "many"))
+	_, err = backup.NewStore("")
+	assert.Error(t, err)
+
+	require.NoError(t, os.Setenv(backup.KeepEnvVar, ""))
+	require.NoError(t, os.Setenv(backup.MaxAgeEnvVar, "a month"))
+	_, err = backup.NewStore("")
+	assert.Error(t, err)
+}
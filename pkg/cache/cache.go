@@ -0,0 +1,155 @@
+// Package cache stores the bodies and Etags of fetched offers on disk, so the Cloud Partner Portal client can
+// revalidate them with If-None-Match rather than fetch whole offers again.
+//
+// Entries are JSON files stored by publisher, offer and slot in the cache directory:
+//
+//	<dir>/<publisher>/<offer>/Draft.json
+//
+// The cache is opt-in. It is enabled by setting PUB_CACHE to true or by setting PUB_CACHE_DIR.
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/devigned/pub/pkg/partner"
+)
+
+const (
+	// EnabledEnvVar is the environment variable used to enable the cache
+	EnabledEnvVar = "PUB_CACHE"
+	// DirEnvVar is the environment variable used to configure the cache directory, which also enables the cache
+	DirEnvVar = "PUB_CACHE_DIR"
+
+	ext = ".json"
+)
+
+type (
+	// Store keeps the bodies and Etags of offers in a directory. It implements partner.OfferCache.
+	Store struct {
+		Dir string
+		// Bypass skips reading and writing entries, but still invalidates them, so a command run without the cache
+		// does not leave stale entries behind
+		Bypass bool
+	}
+
+	entry struct {
+		Etag string          `json:"etag"`
+		Body json.RawMessage `json:"body"`
+	}
+)
+
+// Enabled returns true if the cache is enabled with PUB_CACHE or PUB_CACHE_DIR
+func Enabled() (bool, error) {
+	if os.Getenv(DirEnvVar) != "" {
+		return true, nil
+	}
+
+	enabled := os.Getenv(EnabledEnvVar)
+	if enabled == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(enabled)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false, but is %q", EnabledEnvVar, enabled)
+	}
+	return b, nil
+}
+
+// NewStore returns a store in the directory, or in the directory of PUB_CACHE_DIR if dir is empty, or in
+// $HOME/.pub/cache if neither is set
+func NewStore(dir string) (*Store, error) {
+	if dir == "" {
+		dir = os.Getenv(DirEnvVar)
+	}
+
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to find the home directory for the cache; set %s: %v", DirEnvVar, err)
+		}
+		dir = filepath.Join(home, ".pub", "cache")
+	}
+	return &Store{Dir: dir}, nil
+}
+
+// Get returns the Etag and body cached for the offer in the slot. Entries which are missing or cannot be read are
+// reported as not cached.
+func (s *Store) Get(publisherID, offerID, slot string) (string, []byte, bool) {
+	if s.Bypass {
+		return "", nil, false
+	}
+
+	path, err := s.path(publisherID, offerID, slot)
+	if err != nil {
+		return "", nil, false
+	}
+
+	bits, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", nil, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(bits, &e); err != nil || e.Etag == "" {
+		return "", nil, false
+	}
+	return e.Etag, e.Body, true
+}
+
+// Put caches the Etag and body of the offer in the slot
+func (s *Store) Put(publisherID, offerID, slot, etag string, body []byte) error {
+	if s.Bypass {
+		return nil
+	}
+
+	path, err := s.path(publisherID, offerID, slot)
+	if err != nil {
+		return err
+	}
+
+	bits, err := json.Marshal(entry{Etag: etag, Body: body})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bits, 0600)
+}
+
+// Invalidate removes the entries of every slot of the offer
+func (s *Store) Invalidate(publisherID, offerID string) error {
+	if err := partner.CheckPathID(publisherID); err != nil {
+		return err
+	}
+
+	if err := partner.CheckPathID(offerID); err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(s.Dir, publisherID, offerID))
+}
+
+// Clear removes every entry from the cache
+func (s *Store) Clear() error {
+	if s.Dir == "" {
+		return errors.New("the cache has no directory")
+	}
+	return os.RemoveAll(s.Dir)
+}
+
+func (s *Store) path(publisherID, offerID, slot string) (string, error) {
+	for _, name := range []string{publisherID, offerID, slot} {
+		if err := partner.CheckPathID(name); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(s.Dir, publisherID, offerID, slot+ext), nil
+}
@@ -0,0 +1,122 @@
+package cache_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/cache"
+)
+
+func TestStore_PutAndGet(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	s := &cache.Store{Dir: dir}
+	_, _, ok := s.Get("publisher", "offer", "Draft")
+	assert.False(t, ok)
+
+	require.NoError(t, s.Put("publisher", "offer", "Draft", "etag", []byte(`{"id":"offer"}`)))
+	require.NoError(t, s.Put("publisher", "offer", "Production", "other", []byte(`{"id":"offer"}`)))
+	etag, body, ok := s.Get("publisher", "offer", "Draft")
+	require.True(t, ok)
+	assert.Equal(t, "etag", etag)
+	assert.JSONEq(t, `{"id":"offer"}`, string(body))
+
+	require.NoError(t, s.Invalidate("publisher", "offer"))
+	_, _, ok = s.Get("publisher", "offer", "Draft")
+	assert.False(t, ok)
+	_, _, ok = s.Get("publisher", "offer", "Production")
+	assert.False(t, ok)
+}
+
+func TestStore_Bypass(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	s := &cache.Store{Dir: dir}
+	require.NoError(t, s.Put("publisher", "offer", "Draft", "etag", []byte(`{}`)))
+
+	s.Bypass = true
+	_, _, ok := s.Get("publisher", "offer", "Draft")
+	assert.False(t, ok, "a bypassed cache is not read")
+	require.NoError(t, s.Put("publisher", "other", "Draft", "etag", []byte(`{}`)))
+	require.NoError(t, s.Invalidate("publisher", "offer"))
+
+	s.Bypass = false
+	_, _, ok = s.Get("publisher", "offer", "Draft")
+	assert.False(t, ok, "a bypassed cache is still invalidated")
+	_, _, ok = s.Get("publisher", "other", "Draft")
+	assert.False(t, ok, "a bypassed cache is not written")
+}
+
+func TestStore_Clear(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	s := &cache.Store{Dir: dir}
+	require.NoError(t, s.Put("publisher", "offer", "Draft", "etag", []byte(`{}`)))
+	require.NoError(t, s.Clear())
+	_, err := os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Error(t, (&cache.Store{}).Clear())
+}
+
+func TestStore_FailOnPathSeparators(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	s := &cache.Store{Dir: dir}
+	assert.Error(t, s.Put("..", "offer", "Draft", "etag", []byte(`{}`)))
+	assert.Error(t, s.Invalidate("publisher", "../offer"))
+}
+
+func TestEnabled(t *testing.T) {
+	for _, name := range []string{cache.EnabledEnvVar, cache.DirEnvVar} {
+		defer os.Setenv(name, os.Getenv(name))
+	}
+
+	cases := map[string]struct {
+		enabled  string
+		dir      string
+		expected bool
+		err      bool
+	}{
+		"unset":    {},
+		"enabled":  {enabled: "true", expected: true},
+		"disabled": {enabled: "false"},
+		"dir":      {dir: "cache", expected: true},
+		"invalid":  {enabled: "sometimes", err: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, os.Setenv(cache.EnabledEnvVar, c.enabled))
+			require.NoError(t, os.Setenv(cache.DirEnvVar, c.dir))
+			enabled, err := cache.Enabled()
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, enabled)
+		})
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	defer os.Setenv(cache.DirEnvVar, os.Getenv(cache.DirEnvVar))
+
+	require.NoError(t, os.Setenv(cache.DirEnvVar, "from-env"))
+	s, err := cache.NewStore("")
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", s.Dir)
+
+	s, err = cache.NewStore("from-flag")
+	require.NoError(t, err)
+	assert.Equal(t, "from-flag", s.Dir)
+}
@@ -0,0 +1,138 @@
+// Package diff compares JSON documents and reports each change by the JSONPath of the value which changed.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/devigned/pub/pkg/jsonpath"
+)
+
+const (
+	// Added indicates a value which only exists in the new document
+	Added Op = "add"
+
+	// Removed indicates a value which only exists in the old document
+	Removed Op = "remove"
+
+	// Changed indicates a value which differs between the documents
+	Changed Op = "change"
+)
+
+type (
+	// Op is the kind of change made to a value
+	Op string
+
+	// Change is a single difference between two documents
+	Change struct {
+		Op   Op          `json:"op"`
+		Path string      `json:"path"`
+		From interface{} `json:"from,omitempty"`
+		To   interface{} `json:"to,omitempty"`
+	}
+)
+
+// Values converts from and to into JSON documents, like a partner.Offer, and returns the changes between them
+func Values(from, to interface{}) ([]Change, error) {
+	fromDoc, err := jsonpath.ToDocument(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toDoc, err := jsonpath.ToDocument(to)
+	if err != nil {
+		return nil, err
+	}
+
+	return Documents(fromDoc, toDoc), nil
+}
+
+// Documents returns the changes between two decoded JSON documents. Objects are compared key by key in sorted order
+// and arrays are compared index by index.
+func Documents(from, to interface{}) []Change {
+	return compare("$", from, to, nil)
+}
+
+// String returns a single line, human readable description of the change
+func (c Change) String() string {
+	switch c.Op {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", c.Path, display(c.To))
+	case Removed:
+		return fmt.Sprintf("- %s: %s", c.Path, display(c.From))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Path, display(c.From), display(c.To))
+	}
+}
+
+func compare(path string, from, to interface{}, changes []Change) []Change {
+	switch f := from.(type) {
+	case map[string]interface{}:
+		t, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		keys := make(map[string]bool, len(f)+len(t))
+		for k := range f {
+			keys[k] = true
+		}
+		for k := range t {
+			keys[k] = true
+		}
+
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			childPath := path + jsonpath.Child(k)
+			fv, inFrom := f[k]
+			tv, inTo := t[k]
+			switch {
+			case !inFrom:
+				changes = append(changes, Change{Op: Added, Path: childPath, To: tv})
+			case !inTo:
+				changes = append(changes, Change{Op: Removed, Path: childPath, From: fv})
+			default:
+				changes = compare(childPath, fv, tv, changes)
+			}
+		}
+		return changes
+	case []interface{}:
+		t, ok := to.([]interface{})
+		if !ok {
+			break
+		}
+
+		for i := 0; i < len(f) || i < len(t); i++ {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(f):
+				changes = append(changes, Change{Op: Added, Path: childPath, To: t[i]})
+			case i >= len(t):
+				changes = append(changes, Change{Op: Removed, Path: childPath, From: f[i]})
+			default:
+				changes = compare(childPath, f[i], t[i], changes)
+			}
+		}
+		return changes
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		changes = append(changes, Change{Op: Changed, Path: path, From: from, To: to})
+	}
+	return changes
+}
+
+func display(value interface{}) string {
+	bits, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(bits)
+}
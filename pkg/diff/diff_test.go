@@ -0,0 +1,51 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/diff"
+)
+
+func TestDocuments(t *testing.T) {
+	from := map[string]interface{}{
+		"same":    "value",
+		"removed": 1.0,
+		"changed": "old",
+		"a.b":     []interface{}{"x", "y"},
+		"nested":  map[string]interface{}{"list": []interface{}{1.0}},
+	}
+	to := map[string]interface{}{
+		"same":    "value",
+		"added":   true,
+		"changed": "new",
+		"a.b":     []interface{}{"x"},
+		"nested":  map[string]interface{}{"list": []interface{}{1.0, 2.0}},
+	}
+
+	assert.Equal(t, []diff.Change{
+		{Op: diff.Removed, Path: "$['a.b'][1]", From: "y"},
+		{Op: diff.Added, Path: "$.added", To: true},
+		{Op: diff.Changed, Path: "$.changed", From: "old", To: "new"},
+		{Op: diff.Added, Path: "$.nested.list[1]", To: 2.0},
+		{Op: diff.Removed, Path: "$.removed", From: 1.0},
+	}, diff.Documents(from, to))
+}
+
+func TestValues(t *testing.T) {
+	from := test.NewMarketplaceVMOffer()
+	to := test.NewMarketplaceVMOffer()
+
+	changes, err := diff.Values(from, to)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	to.Definition.Plans[0].PlanVirtualMachineDetail.SKUTitle = "new title"
+	changes, err = diff.Values(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, `~ $.definition.plans[0]['microsoft-azure-virtualmachines.skuTitle']: "skuTitle" -> "new title"`, changes[0].String())
+}
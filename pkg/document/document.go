@@ -0,0 +1,152 @@
+// Package document reads offer and SKU documents written as JSON or YAML from files or stdin and converts them to JSON
+// for unmarshalling into the partner types.
+//
+// YAML is detected by a .yaml or .yml extension, or by content which does not start like JSON. A YAML file may hold
+// several documents separated by ---. Keys which YAML would read as numbers, like an image version of 1.0, must be
+// quoted.
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Stdin is the file path which reads from stdin
+const Stdin = "-"
+
+// Read reads the file at path, or stdin if path is Stdin
+func Read(path string, stdin io.Reader) ([]byte, error) {
+	if path == Stdin {
+		return ioutil.ReadAll(stdin)
+	}
+	return ioutil.ReadFile(path)
+}
+
+// ReadJSON reads the file at path, or stdin if path is Stdin, and returns each document it contains as JSON
+func ReadJSON(path string, stdin io.Reader) ([][]byte, error) {
+	bits, err := Read(path, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToJSON(path, bits)
+}
+
+// ReadOneJSON is ReadJSON for a file which must contain exactly one document
+func ReadOneJSON(path string, stdin io.Reader) ([]byte, error) {
+	docs, err := ReadJSON(path, stdin)
+	if err != nil {
+		return nil, err
+	}
+	return Single(path, docs)
+}
+
+// Single returns the only document read from the file at path, or an error if there is not exactly one
+func Single(path string, docs [][]byte) ([]byte, error) {
+	if len(docs) != 1 {
+		return nil, fmt.Errorf("%s must contain exactly one document, but has %d", displayName(path), len(docs))
+	}
+	return docs[0], nil
+}
+
+// IsYAML returns true if the file at path, with content bits, should be read as YAML
+func IsYAML(path string, bits []byte) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	case ".json":
+		return false
+	}
+
+	trimmed := bytes.TrimSpace(bits)
+	return len(trimmed) > 0 && trimmed[0] != '{' && trimmed[0] != '['
+}
+
+// ToJSON converts the content of the file at path to JSON documents. JSON content is returned as a single document
+// and empty content has no documents.
+func ToJSON(path string, bits []byte) ([][]byte, error) {
+	if len(bytes.TrimSpace(bits)) == 0 {
+		return nil, nil
+	}
+
+	if !IsYAML(path, bits) {
+		return [][]byte{bits}, nil
+	}
+
+	var docs [][]byte
+	decoder := yaml.NewDecoder(bytes.NewReader(bits))
+	for {
+		var doc interface{}
+		err := decoder.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %v", displayName(path), err)
+		}
+
+		if doc == nil {
+			continue
+		}
+
+		converted, err := fromYAML(doc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %v", displayName(path), err)
+		}
+
+		jsonBits, err := json.Marshal(converted)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, jsonBits)
+	}
+	return docs, nil
+}
+
+// fromYAML converts the map[interface{}]interface{} objects decoded by yaml into map[string]interface{} objects which
+// can be marshalled to JSON
+func fromYAML(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, child := range t {
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("key %v must be a string; quote it to use it as a key", k)
+			}
+
+			converted, err := fromYAML(child)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = converted
+		}
+		return m, nil
+	case []interface{}:
+		arr := make([]interface{}, len(t))
+		for i, child := range t {
+			converted, err := fromYAML(child)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = converted
+		}
+		return arr, nil
+	default:
+		return v, nil
+	}
+}
+
+func displayName(path string) string {
+	if path == Stdin {
+		return "stdin"
+	}
+	return path
+}
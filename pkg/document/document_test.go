@@ -0,0 +1,75 @@
+package document_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/document"
+)
+
+func TestIsYAML(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, document.IsYAML("offer.yaml", []byte(`{"id": "foo"}`)))
+	assert.True(t, document.IsYAML("offer.YML", nil))
+	assert.False(t, document.IsYAML("offer.json", []byte("id: foo")))
+	assert.True(t, document.IsYAML("-", []byte("# comment\nid: foo")))
+	assert.False(t, document.IsYAML("-", []byte("  \n[{}]")))
+	assert.False(t, document.IsYAML("offer", []byte(`{"id": "foo"}`)))
+}
+
+func TestToJSON(t *testing.T) {
+	t.Parallel()
+
+	docs, err := document.ToJSON("skus.yaml", []byte(`
+---
+# the first SKU
+planId: one
+regions: [US, CA]
+microsoft-azure-virtualmachines.vmImages:
+  "1.0":
+    osVhdUrl: https://contoso.com/one.vhd
+---
+planId: two
+microsoft-azure-virtualmachines.hideSKUForSolutionTemplate: true
+`))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.JSONEq(t, `{
+  "planId": "one",
+  "regions": ["US", "CA"],
+  "microsoft-azure-virtualmachines.vmImages": {"1.0": {"osVhdUrl": "https://contoso.com/one.vhd"}}
+}`, string(docs[0]))
+	assert.JSONEq(t, `{"planId": "two", "microsoft-azure-virtualmachines.hideSKUForSolutionTemplate": true}`, string(docs[1]))
+
+	jsonBits := []byte(`{"planId": "one"}`)
+	docs, err = document.ToJSON("sku.json", jsonBits)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{jsonBits}, docs)
+
+	_, err = document.ToJSON("sku.yaml", []byte("vmImages:\n  1.0: {}\n"))
+	assert.Error(t, err)
+
+	_, err = document.ToJSON("sku.yaml", []byte("planId: [one\n"))
+	assert.Error(t, err)
+}
+
+func TestReadOneJSON(t *testing.T) {
+	t.Parallel()
+
+	bits, err := document.ReadOneJSON(document.Stdin, strings.NewReader("id: foo\n"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": "foo"}`, string(bits))
+
+	fName, del := test.NewTmpFileWithContent(t, "offer*.yml", "id: foo\n---\nid: bar\n")
+	defer del()
+	_, err = document.ReadOneJSON(fName, nil)
+	assert.Error(t, err)
+
+	_, err = document.ReadOneJSON(document.Stdin, strings.NewReader(""))
+	assert.Error(t, err)
+}
@@ -0,0 +1,334 @@
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+type (
+	// Position is a 1-based line and column in a file
+	Position struct {
+		Line   int
+		Column int
+	}
+
+	// yamlLine is a significant line of a YAML document with its indentation
+	yamlLine struct {
+		number int
+		indent int
+		text   string
+	}
+
+	// jsonScanner finds the positions of the values in a JSON document
+	jsonScanner struct {
+		bits []byte
+		pos  int
+	}
+)
+
+// Locate returns the position of the value at keys, which are object keys (string) and array indexes (int), in the
+// document with the index of the file at path. For a value in an object, the position is of its key. If the value
+// cannot be found, the position of the closest parent which can be is returned. Locate is best effort: YAML is only
+// located within block style collections.
+func Locate(path string, bits []byte, index int, keys []interface{}) Position {
+	if IsYAML(path, bits) {
+		return locateYAML(bits, index, keys)
+	}
+
+	s := &jsonScanner{bits: bits}
+	s.skipSpace()
+	pos := s.position()
+	if found, ok := s.locate(keys); ok {
+		pos = found
+	}
+	return pos
+}
+
+func locateYAML(bits []byte, index int, keys []interface{}) Position {
+	lines := yamlDocument(bits, index)
+	if len(lines) == 0 {
+		return Position{Line: 1, Column: 1}
+	}
+
+	pos := Position{Line: lines[0].number, Column: lines[0].indent + 1}
+	for _, key := range keys {
+		if len(lines) == 0 {
+			break
+		}
+
+		var found bool
+		switch k := key.(type) {
+		case string:
+			lines, found = yamlMember(lines, k)
+		case int:
+			lines, found = yamlItem(lines, k)
+		}
+
+		if !found {
+			break
+		}
+		pos = Position{Line: lines[0].number, Column: lines[0].indent + 1}
+		lines = lines[1:]
+		if len(lines) > 0 && lines[0].text == "" {
+			lines = lines[1:]
+		}
+	}
+	return pos
+}
+
+// yamlDocument returns the significant lines of the document with the index, skipping blank lines and comments
+func yamlDocument(bits []byte, index int) []yamlLine {
+	var lines []yamlLine
+	doc, started := 0, false
+	for i, raw := range strings.Split(string(bits), "\n") {
+		raw = strings.TrimRight(raw, "\r")
+		trimmed := strings.TrimLeft(raw, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(raw, "---") || strings.HasPrefix(raw, "...") {
+			// a document with content before the first separator is the first document
+			if started {
+				doc++
+			}
+			started = false
+			continue
+		}
+
+		started = true
+		if doc == index {
+			lines = append(lines, yamlLine{number: i + 1, indent: len(raw) - len(trimmed), text: trimmed})
+		}
+	}
+	return lines
+}
+
+// yamlMember finds the key in the block mapping at the start of lines. It returns the line of the key, with the text
+// after the key as the first line, followed by the lines of its value.
+func yamlMember(lines []yamlLine, key string) ([]yamlLine, bool) {
+	indent := lines[0].indent
+	for i, line := range lines {
+		if line.indent < indent {
+			break
+		}
+
+		if line.indent != indent {
+			continue
+		}
+
+		rest, ok := trimKey(line.text, key)
+		if !ok {
+			continue
+		}
+
+		// block sequences may be at the same indentation as their key
+		end := i + 1
+		sequence := rest == "" && end < len(lines) && lines[end].indent == indent && isItem(lines[end].text)
+		for end < len(lines) && (lines[end].indent > indent || sequence && lines[end].indent == indent && isItem(lines[end].text)) {
+			end++
+		}
+
+		value := append([]yamlLine{line, {number: line.number, indent: indent + len(line.text) - len(rest), text: rest}}, lines[i+1:end]...)
+		return value, true
+	}
+	return nil, false
+}
+
+// yamlItem finds the item with the index in the block sequence at the start of lines. It returns the line of the item,
+// with the text after the dash as the first line, followed by the rest of the lines of the item.
+func yamlItem(lines []yamlLine, index int) ([]yamlLine, bool) {
+	indent := lines[0].indent
+	item := -1
+	for i, line := range lines {
+		if line.indent < indent {
+			break
+		}
+
+		if line.indent != indent || !isItem(line.text) {
+			continue
+		}
+
+		item++
+		if item != index {
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && lines[end].indent > indent {
+			end++
+		}
+
+		rest := strings.TrimLeft(line.text[1:], " ")
+		itemLine := yamlLine{number: line.number, indent: indent + len(line.text) - len(rest), text: rest}
+		return append([]yamlLine{line, itemLine}, lines[i+1:end]...), true
+	}
+	return nil, false
+}
+
+// trimKey returns the text after key: if the line starts with the key, which may be quoted
+func trimKey(text, key string) (string, bool) {
+	for _, quoted := range []string{key, `"` + key + `"`, "'" + key + "'"} {
+		if strings.HasPrefix(text, quoted+":") {
+			return strings.TrimLeft(text[len(quoted)+1:], " "), true
+		}
+	}
+	return "", false
+}
+
+func isItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// locate moves the scanner to the value at keys and returns the position of it, or of its key in an object
+func (s *jsonScanner) locate(keys []interface{}) (Position, bool) {
+	s.skipSpace()
+	pos := s.position()
+	if len(keys) == 0 {
+		return pos, true
+	}
+
+	switch key := keys[0].(type) {
+	case string:
+		if !s.consume('{') {
+			return pos, false
+		}
+
+		for {
+			s.skipSpace()
+			if s.consume('}') {
+				return pos, false
+			}
+
+			keyPos := s.position()
+			name, ok := s.readString()
+			if !ok {
+				return pos, false
+			}
+
+			s.skipSpace()
+			if !s.consume(':') {
+				return pos, false
+			}
+
+			if name == key {
+				if len(keys) == 1 {
+					return keyPos, true
+				}
+				return s.locate(keys[1:])
+			}
+
+			if !s.skipValue() {
+				return pos, false
+			}
+
+			s.skipSpace()
+			s.consume(',')
+		}
+	case int:
+		if !s.consume('[') {
+			return pos, false
+		}
+
+		for i := 0; ; i++ {
+			s.skipSpace()
+			if s.consume(']') {
+				return pos, false
+			}
+
+			if i == key {
+				return s.locate(keys[1:])
+			}
+
+			if !s.skipValue() {
+				return pos, false
+			}
+
+			s.skipSpace()
+			s.consume(',')
+		}
+	default:
+		return pos, false
+	}
+}
+
+// skipValue moves the scanner past the value at its position
+func (s *jsonScanner) skipValue() bool {
+	s.skipSpace()
+	if s.pos >= len(s.bits) {
+		return false
+	}
+
+	switch s.bits[s.pos] {
+	case '"':
+		_, ok := s.readString()
+		return ok
+	case '{', '[':
+		depth := 0
+		for s.pos < len(s.bits) {
+			switch s.bits[s.pos] {
+			case '"':
+				if _, ok := s.readString(); !ok {
+					return false
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+			s.pos++
+			if depth == 0 {
+				return true
+			}
+		}
+		return false
+	default:
+		for s.pos < len(s.bits) && bytes.IndexByte([]byte(",}] \t\r\n"), s.bits[s.pos]) < 0 {
+			s.pos++
+		}
+		return true
+	}
+}
+
+// readString reads the JSON string at the position of the scanner
+func (s *jsonScanner) readString() (string, bool) {
+	if s.pos >= len(s.bits) || s.bits[s.pos] != '"' {
+		return "", false
+	}
+
+	for end := s.pos + 1; end < len(s.bits); end++ {
+		switch s.bits[end] {
+		case '\\':
+			end++
+		case '"':
+			var str string
+			err := json.Unmarshal(s.bits[s.pos:end+1], &str)
+			s.pos = end + 1
+			return str, err == nil
+		}
+	}
+	return "", false
+}
+
+func (s *jsonScanner) consume(c byte) bool {
+	if s.pos < len(s.bits) && s.bits[s.pos] == c {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+func (s *jsonScanner) skipSpace() {
+	for s.pos < len(s.bits) && bytes.IndexByte([]byte(" \t\r\n"), s.bits[s.pos]) >= 0 {
+		s.pos++
+	}
+}
+
+// position returns the line and column of the scanner
+func (s *jsonScanner) position() Position {
+	before := s.bits[:s.pos]
+	line := bytes.Count(before, []byte("\n")) + 1
+	return Position{Line: line, Column: s.pos - bytes.LastIndexByte(before, '\n')}
+}
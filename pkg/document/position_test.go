@@ -0,0 +1,77 @@
+package document_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devigned/pub/pkg/document"
+)
+
+func TestLocate_YAML(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`# offer
+id: widget
+definition:
+  displayText: Widget
+  plans:
+  - planId: basic
+    "microsoft-azure-corevm.deploymentModels":
+      - ARM
+
+      - RDFE
+  -
+    planId: premium
+---
+id: gadget
+`)
+	cases := []struct {
+		index    int
+		keys     []interface{}
+		expected document.Position
+	}{
+		{keys: nil, expected: document.Position{Line: 2, Column: 1}},
+		{keys: []interface{}{"definition", "displayText"}, expected: document.Position{Line: 4, Column: 3}},
+		{keys: []interface{}{"definition", "plans", 0}, expected: document.Position{Line: 6, Column: 3}},
+		{keys: []interface{}{"definition", "plans", 0, "planId"}, expected: document.Position{Line: 6, Column: 5}},
+		{keys: []interface{}{"definition", "plans", 0, "microsoft-azure-corevm.deploymentModels", 1}, expected: document.Position{Line: 10, Column: 7}},
+		{keys: []interface{}{"definition", "plans", 1, "planId"}, expected: document.Position{Line: 12, Column: 5}},
+		{keys: []interface{}{"definition", "missing"}, expected: document.Position{Line: 3, Column: 1}},
+		{index: 1, keys: []interface{}{"id"}, expected: document.Position{Line: 14, Column: 1}},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, document.Locate("offer.yaml", content, c.index, c.keys), "%v", c.keys)
+	}
+}
+
+func TestLocate_JSON(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`{
+  "id": "widget",
+  "definition": {
+    "displayText": "Widget \"1\"",
+    "offer": {"a": [1, {"b": 2}]},
+    "plans": [
+      {"planId": "basic"},
+      {"planId": "premium", "regions": ["us", "eu"]}
+    ]
+  }
+}`)
+	cases := []struct {
+		keys     []interface{}
+		expected document.Position
+	}{
+		{keys: nil, expected: document.Position{Line: 1, Column: 1}},
+		{keys: []interface{}{"id"}, expected: document.Position{Line: 2, Column: 3}},
+		{keys: []interface{}{"definition", "plans", 1, "planId"}, expected: document.Position{Line: 8, Column: 8}},
+		{keys: []interface{}{"definition", "plans", 1, "regions", 1}, expected: document.Position{Line: 8, Column: 47}},
+		{keys: []interface{}{"definition", "missing"}, expected: document.Position{Line: 1, Column: 1}},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, document.Locate("offer.json", content, 0, c.keys), "%v", c.keys)
+	}
+}
@@ -0,0 +1,446 @@
+// Package export dumps everything a set of publishers own in the Cloud Partner Portal to a directory, for disaster
+// recovery and audits.
+//
+// The export is written in a deterministic layout, with a manifest of what was exported:
+//
+//	<dir>/manifest.json
+//	<dir>/<publisher>/publisher.json
+//	<dir>/<publisher>/<offer>/draft.json
+//	<dir>/<publisher>/<offer>/preview.json
+//	<dir>/<publisher>/<offer>/production.json
+//	<dir>/<publisher>/<offer>/status.json
+//	<dir>/<publisher>/<offer>/operations.json
+//
+// An offer is skipped when the directory already has an export of it with the same version and changed time in the
+// manifest, and the same status and operations, so re-running an export only fetches the offers which have changed.
+// The status and operations are always fetched, since publishing an offer changes them and its slots without changing
+// its draft.
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/date"
+
+	"github.com/devigned/pub/pkg/parallel"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+const (
+	// ManifestFileName is the name of the manifest file in the export directory
+	ManifestFileName = "manifest.json"
+	// PublisherFileName is the name of the publisher file in the directory of each publisher
+	PublisherFileName = "publisher.json"
+)
+
+type (
+	// Client is the part of the Cloud Partner Portal client used to export publishers
+	Client interface {
+		ListPublishers(ctx context.Context) ([]partner.Publisher, error)
+		ListOffers(ctx context.Context, params partner.ListOffersParams) ([]partner.Offer, error)
+		GetOffer(ctx context.Context, params partner.ShowOfferParams) (*partner.Offer, error)
+		GetOfferBySlot(ctx context.Context, params partner.ShowOfferBySlotParams) (*partner.Offer, error)
+		GetOfferStatus(ctx context.Context, params partner.ShowOfferParams) (*partner.OfferStatus, error)
+		ListOperations(ctx context.Context, params partner.ListOperationsParams) ([]partner.Operation, error)
+	}
+
+	// Exporter exports every offer of the publishers the client can access to a directory
+	Exporter struct {
+		Client Client
+		Dir    string
+		// Parallelism is the number of offers fetched at once, and defaults to parallel.DefaultLimit
+		Parallelism int
+		// Full exports every offer, even those which have not changed since the last export
+		Full bool
+		// Now returns the current time, and defaults to time.Now
+		Now func() time.Time
+	}
+
+	// Manifest lists what was exported to the directory
+	Manifest struct {
+		ExportedTime time.Time        `json:"exportedTime"`
+		Publishers   []PublisherEntry `json:"publishers"`
+	}
+
+	// PublisherEntry is an exported publisher with its offers
+	PublisherEntry struct {
+		ID     string       `json:"id"`
+		Offers []OfferEntry `json:"offers"`
+		Error  string       `json:"error,omitempty"`
+	}
+
+	// OfferEntry is an exported offer with its files, relative to the export directory
+	OfferEntry struct {
+		ID          string    `json:"id"`
+		Version     int       `json:"version"`
+		ChangedTime date.Time `json:"changedTime"`
+		Files       []string  `json:"files"`
+		Errors      []string  `json:"errors,omitempty"`
+	}
+
+	// Summary reports the outcome of an export
+	Summary struct {
+		Dir        string   `json:"dir"`
+		Publishers int      `json:"publishers"`
+		Offers     int      `json:"offers"`
+		Exported   int      `json:"exported"`
+		Skipped    int      `json:"skipped"`
+		Failed     int      `json:"failed"`
+		Errors     []string `json:"errors,omitempty"`
+	}
+
+	// offerFile is a file exported for each offer
+	offerFile struct {
+		name  string
+		fetch func() (interface{}, error)
+		// published is set for the files which change when the offer is published, without a new draft version
+		published bool
+	}
+
+	// offerJob is an offer to export
+	offerJob struct {
+		publisher int
+		offer     partner.Offer
+		entry     OfferEntry
+		skipped   bool
+	}
+)
+
+// Export writes every offer of every publisher to the directory, followed by the manifest, and returns a summary. An
+// error is only returned if the export could not run; offers which could not be exported are reported in the summary
+// and the manifest.
+func (e *Exporter) Export(ctx context.Context) (*Summary, error) {
+	previous, err := ReadManifest(e.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	publishers, err := e.Client.ListPublishers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list publishers: %v", err)
+	}
+
+	sort.Slice(publishers, func(i, j int) bool {
+		return publishers[i].ID < publishers[j].ID
+	})
+
+	manifest := &Manifest{ExportedTime: e.now().UTC(), Publishers: make([]PublisherEntry, len(publishers))}
+	offers := make([][]partner.Offer, len(publishers))
+	err = parallel.ForEach(ctx, len(publishers), e.Parallelism, func(ctx context.Context, i int) error {
+		manifest.Publishers[i] = PublisherEntry{ID: publishers[i].ID, Offers: []OfferEntry{}}
+		if err := e.writePublisher(&publishers[i]); err != nil {
+			manifest.Publishers[i].Error = err.Error()
+			return nil
+		}
+
+		list, err := e.Client.ListOffers(ctx, partner.ListOffersParams{PublisherID: publishers[i].ID})
+		if err != nil {
+			manifest.Publishers[i].Error = fmt.Sprintf("unable to list offers: %v", err)
+			return ctx.Err()
+		}
+
+		sort.Slice(list, func(a, b int) bool {
+			return list[a].ID < list[b].ID
+		})
+		offers[i] = list
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []*offerJob
+	for i, list := range offers {
+		for _, offer := range list {
+			jobs = append(jobs, &offerJob{publisher: i, offer: offer})
+		}
+	}
+
+	err = parallel.ForEach(ctx, len(jobs), e.Parallelism, func(ctx context.Context, i int) error {
+		job := jobs[i]
+		if prev, ok := e.unchanged(ctx, previous, manifest.Publishers[job.publisher].ID, job.offer); ok {
+			job.entry, job.skipped = prev, true
+			return nil
+		}
+
+		job.entry = e.exportOffer(ctx, manifest.Publishers[job.publisher].ID, job.offer)
+		return ctx.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{Dir: e.Dir, Publishers: len(publishers), Offers: len(jobs)}
+	for _, p := range manifest.Publishers {
+		if p.Error != "" {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %s", p.ID, p.Error))
+		}
+	}
+
+	for _, job := range jobs {
+		p := &manifest.Publishers[job.publisher]
+		p.Offers = append(p.Offers, job.entry)
+		switch {
+		case len(job.entry.Errors) > 0:
+			summary.Failed++
+			for _, msg := range job.entry.Errors {
+				summary.Errors = append(summary.Errors, fmt.Sprintf("%s/%s: %s", p.ID, job.entry.ID, msg))
+			}
+		case job.skipped:
+			summary.Skipped++
+		default:
+			summary.Exported++
+		}
+	}
+
+	if err := writeJSON(filepath.Join(e.Dir, ManifestFileName), manifest); err != nil {
+		return summary, fmt.Errorf("unable to write manifest: %v", err)
+	}
+	return summary, nil
+}
+
+// ReadManifest reads the manifest of the export in the directory, or returns an empty manifest if there is none
+func ReadManifest(dir string) (*Manifest, error) {
+	bits, err := ioutil.ReadFile(filepath.Join(dir, ManifestFileName))
+	if os.IsNotExist(err) {
+		return &Manifest{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(bits, &m); err != nil {
+		return nil, fmt.Errorf("unable to read manifest in %s: %v", dir, err)
+	}
+	return &m, nil
+}
+
+// Offer returns the entry of the offer of the publisher, if the manifest has one
+func (m *Manifest) Offer(publisherID, offerID string) (OfferEntry, bool) {
+	for _, p := range m.Publishers {
+		if p.ID != publisherID {
+			continue
+		}
+
+		for _, o := range p.Offers {
+			if o.ID == offerID {
+				return o, true
+			}
+		}
+	}
+	return OfferEntry{}, false
+}
+
+// ReadDrafts reads the draft of each offer of the publishers, or of every publisher if none are given, from the export
+// in the directory. Offers whose draft could not be exported are skipped.
+func ReadDrafts(dir string, publisherIDs ...string) ([]*partner.Offer, error) {
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(m.Publishers) == 0 {
+		return nil, fmt.Errorf("there is no export in %s; run publishers export --dir %s first", dir, dir)
+	}
+
+	wanted := make(map[string]bool, len(publisherIDs))
+	for _, id := range publisherIDs {
+		wanted[id] = true
+	}
+
+	var offers []*partner.Offer
+	for _, p := range m.Publishers {
+		if len(wanted) > 0 && !wanted[p.ID] {
+			continue
+		}
+
+		for _, o := range p.Offers {
+			draft := strings.Join([]string{p.ID, o.ID, "draft.json"}, "/")
+			if !contains(o.Files, draft) {
+				continue
+			}
+
+			bits, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(draft)))
+			if err != nil {
+				return nil, err
+			}
+
+			var offer partner.Offer
+			if err := json.Unmarshal(bits, &offer); err != nil {
+				return nil, fmt.Errorf("unable to read %s: %v", draft, err)
+			}
+			offers = append(offers, &offer)
+		}
+	}
+	return offers, nil
+}
+
+// unchanged returns the entry of the last export of the offer, if it was exported without errors at the same version
+// and changed time, all of its files are still in the directory and its status and operations have not changed since
+func (e *Exporter) unchanged(ctx context.Context, previous *Manifest, publisherID string, offer partner.Offer) (OfferEntry, bool) {
+	if e.Full {
+		return OfferEntry{}, false
+	}
+
+	prev, ok := previous.Offer(publisherID, offer.ID)
+	if !ok || len(prev.Errors) > 0 || prev.Version != offer.Version || !prev.ChangedTime.Equal(offer.ChangedTime.Time) {
+		return OfferEntry{}, false
+	}
+
+	for _, file := range prev.Files {
+		if _, err := os.Stat(filepath.Join(e.Dir, filepath.FromSlash(file))); err != nil {
+			return OfferEntry{}, false
+		}
+	}
+
+	for _, f := range e.offerFiles(ctx, publisherID, offer.ID) {
+		if f.published && !e.sameFile(prev, offerPath(publisherID, offer.ID, f.name), f) {
+			return OfferEntry{}, false
+		}
+	}
+	return prev, true
+}
+
+// sameFile returns true if the file fetched now matches the file of the last export, or if the file is still not found
+func (e *Exporter) sameFile(prev OfferEntry, rel string, f offerFile) bool {
+	v, err := f.fetch()
+	if err == partner.ErrOfferNotFound {
+		return !contains(prev.Files, rel)
+	}
+
+	if err != nil {
+		return false
+	}
+
+	bits, err := marshalJSON(v)
+	if err != nil {
+		return false
+	}
+
+	current, err := ioutil.ReadFile(filepath.Join(e.Dir, filepath.FromSlash(rel)))
+	return err == nil && bytes.Equal(bits, current)
+}
+
+// exportOffer fetches and writes the files of the offer. A file which cannot be fetched or written is reported in the
+// errors of the entry, and the rest of the files are still exported.
+func (e *Exporter) exportOffer(ctx context.Context, publisherID string, offer partner.Offer) OfferEntry {
+	entry := OfferEntry{ID: offer.ID, Version: offer.Version, ChangedTime: offer.ChangedTime, Files: []string{}}
+	if err := partner.CheckPathID(publisherID); err != nil {
+		entry.Errors = append(entry.Errors, err.Error())
+		return entry
+	}
+
+	if err := partner.CheckPathID(offer.ID); err != nil {
+		entry.Errors = append(entry.Errors, err.Error())
+		return entry
+	}
+
+	for _, f := range e.offerFiles(ctx, publisherID, offer.ID) {
+		rel := offerPath(publisherID, offer.ID, f.name)
+		v, err := f.fetch()
+		if err == partner.ErrOfferNotFound && f.name != "draft" {
+			// an offer which is not in a slot has no file for it, so the file of an earlier export is removed
+			if err := os.Remove(filepath.Join(e.Dir, filepath.FromSlash(rel))); err != nil && !os.IsNotExist(err) {
+				entry.Errors = append(entry.Errors, fmt.Sprintf("unable to remove %s: %v", rel, err))
+			}
+			continue
+		}
+
+		if err != nil {
+			entry.Errors = append(entry.Errors, fmt.Sprintf("unable to get %s: %v", f.name, err))
+			continue
+		}
+
+		if err := writeJSON(filepath.Join(e.Dir, filepath.FromSlash(rel)), v); err != nil {
+			entry.Errors = append(entry.Errors, fmt.Sprintf("unable to write %s: %v", rel, err))
+			continue
+		}
+		entry.Files = append(entry.Files, rel)
+	}
+	return entry
+}
+
+// offerFiles returns the files exported for the offer
+func (e *Exporter) offerFiles(ctx context.Context, publisherID, offerID string) []offerFile {
+	showParams := partner.ShowOfferParams{PublisherID: publisherID, OfferID: offerID}
+	slot := func(name string) func() (interface{}, error) {
+		return func() (interface{}, error) {
+			return e.Client.GetOfferBySlot(ctx, partner.ShowOfferBySlotParams{PublisherID: publisherID, OfferID: offerID, SlotID: name})
+		}
+	}
+
+	return []offerFile{
+		{name: "draft", fetch: func() (interface{}, error) {
+			return e.Client.GetOffer(ctx, showParams)
+		}},
+		{name: "preview", fetch: slot(partner.PreviewSlot)},
+		{name: "production", fetch: slot(partner.ProductionSlot)},
+		{name: "status", published: true, fetch: func() (interface{}, error) {
+			return e.Client.GetOfferStatus(ctx, showParams)
+		}},
+		{name: "operations", published: true, fetch: func() (interface{}, error) {
+			return e.Client.ListOperations(ctx, partner.ListOperationsParams{PublisherID: publisherID, OfferID: offerID})
+		}},
+	}
+}
+
+// offerPath returns the path of a file of the offer, relative to the export directory
+func offerPath(publisherID, offerID, name string) string {
+	return strings.Join([]string{publisherID, offerID, name + ".json"}, "/")
+}
+
+func (e *Exporter) writePublisher(publisher *partner.Publisher) error {
+	if err := partner.CheckPathID(publisher.ID); err != nil {
+		return err
+	}
+	return writeJSON(filepath.Join(e.Dir, publisher.ID, PublisherFileName), publisher)
+}
+
+func (e *Exporter) now() time.Time {
+	if e.Now == nil {
+		return time.Now()
+	}
+	return e.Now()
+}
+
+// writeJSON writes the value as indented JSON, creating the directory of the file if needed
+func writeJSON(path string, v interface{}) error {
+	bits, err := marshalJSON(v)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bits, 0644)
+}
+
+// marshalJSON returns the value as indented JSON, as it is written to the export
+func marshalJSON(v interface{}) ([]byte, error) {
+	bits, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(bits, '\n'), nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,248 @@
+package export_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/export"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+var changed = date.Time{Time: time.Date(2019, 11, 3, 16, 55, 7, 0, time.UTC)}
+
+func newListedOffer(publisherID, id string) partner.Offer {
+	return partner.Offer{Entity: partner.Entity{ID: id, Version: 2}, PublisherID: publisherID, ChangedTime: changed}
+}
+
+// newExportMock returns a client for the publishers and their offers. Each offer is in the Production slot, but not
+// in the Preview slot.
+func newExportMock(offers map[string][]partner.Offer) *test.CloudPartnerServiceMock {
+	return newExportMockWithStatus(offers, "succeeded")
+}
+
+// newExportMockWithStatus returns a client like newExportMock, with the status of every offer
+func newExportMockWithStatus(offers map[string][]partner.Offer, status string) *test.CloudPartnerServiceMock {
+	svcMock := new(test.CloudPartnerServiceMock)
+	var publishers []partner.Publisher
+	for publisherID, list := range offers {
+		publishers = append(publishers, partner.Publisher{Entity: partner.Entity{ID: publisherID}})
+		svcMock.On("ListOffers", mock.Anything, partner.ListOffersParams{PublisherID: publisherID}).Return(list, nil)
+		for i := range list {
+			offer := &list[i]
+			show := partner.ShowOfferParams{PublisherID: publisherID, OfferID: offer.ID}
+			slot := func(id string) partner.ShowOfferBySlotParams {
+				return partner.ShowOfferBySlotParams{PublisherID: publisherID, OfferID: offer.ID, SlotID: id}
+			}
+			svcMock.On("GetOffer", mock.Anything, show).Return(offer, nil)
+			svcMock.On("GetOfferBySlot", mock.Anything, slot(partner.PreviewSlot)).Return((*partner.Offer)(nil), partner.ErrOfferNotFound)
+			svcMock.On("GetOfferBySlot", mock.Anything, slot(partner.ProductionSlot)).Return(offer, nil)
+			svcMock.On("GetOfferStatus", mock.Anything, show).Return(&partner.OfferStatus{Status: status}, nil)
+			svcMock.On("ListOperations", mock.Anything, partner.ListOperationsParams{PublisherID: publisherID, OfferID: offer.ID}).Return([]partner.Operation{}, nil)
+		}
+	}
+	svcMock.On("ListPublishers", mock.Anything).Return(publishers, nil)
+	return svcMock
+}
+
+func TestExporter_Export(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	svcMock := newExportMock(map[string][]partner.Offer{
+		"b": {newListedOffer("b", "offer")},
+		"a": {newListedOffer("a", "two"), newListedOffer("a", "one")},
+	})
+
+	exporter := &export.Exporter{Client: svcMock, Dir: dir, Parallelism: 2}
+	summary, err := exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &export.Summary{Dir: dir, Publishers: 2, Offers: 3, Exported: 3}, summary)
+
+	for _, file := range []string{"manifest.json", "a/publisher.json", "b/publisher.json", "a/one/draft.json", "a/one/production.json", "a/one/status.json", "a/one/operations.json"} {
+		assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(file)))
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "a", "one", "preview.json"))
+	assert.True(t, os.IsNotExist(err), "an offer which is not in preview has no preview file")
+
+	manifest, err := export.ReadManifest(dir)
+	require.NoError(t, err)
+	require.Len(t, manifest.Publishers, 2)
+	assert.Equal(t, "a", manifest.Publishers[0].ID)
+	require.Len(t, manifest.Publishers[0].Offers, 2)
+	assert.Equal(t, "one", manifest.Publishers[0].Offers[0].ID)
+	assert.Equal(t, []string{"a/one/draft.json", "a/one/production.json", "a/one/status.json", "a/one/operations.json"}, manifest.Publishers[0].Offers[0].Files)
+}
+
+func TestExporter_ExportSkipsUnchangedOffers(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	offers := map[string][]partner.Offer{"a": {newListedOffer("a", "one"), newListedOffer("a", "two")}}
+	exporter := &export.Exporter{Client: newExportMock(offers), Dir: dir}
+	_, err := exporter.Export(context.Background())
+	require.NoError(t, err)
+
+	offers["a"][1].ChangedTime = date.Time{Time: changed.Add(time.Hour)}
+	svcMock := newExportMock(offers)
+	exporter.Client = svcMock
+	summary, err := exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Skipped)
+	assert.Equal(t, 1, summary.Exported)
+	svcMock.AssertNotCalled(t, "GetOffer", mock.Anything, partner.ShowOfferParams{PublisherID: "a", OfferID: "one"})
+
+	manifest, err := export.ReadManifest(dir)
+	require.NoError(t, err)
+	entry, ok := manifest.Offer("a", "one")
+	require.True(t, ok)
+	assert.NotEmpty(t, entry.Files)
+
+	exporter.Full = true
+	summary, err = exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, summary.Skipped)
+	assert.Equal(t, 2, summary.Exported)
+}
+
+func TestExporter_ExportRefreshesPublishedOffers(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	offers := map[string][]partner.Offer{"a": {newListedOffer("a", "one")}}
+	exporter := &export.Exporter{Client: newExportMock(offers), Dir: dir}
+	_, err := exporter.Export(context.Background())
+	require.NoError(t, err)
+
+	// publishing changes the status of the offer, but not its draft
+	svcMock := newExportMockWithStatus(offers, "running")
+	exporter.Client = svcMock
+	summary, err := exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, summary.Skipped)
+	assert.Equal(t, 1, summary.Exported)
+	svcMock.AssertCalled(t, "GetOfferBySlot", mock.Anything, partner.ShowOfferBySlotParams{PublisherID: "a", OfferID: "one", SlotID: partner.ProductionSlot})
+
+	bits, err := ioutil.ReadFile(filepath.Join(dir, "a", "one", "status.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(bits), "running")
+
+	summary, err = exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Skipped)
+}
+
+func TestExporter_ExportRemovesFilesOfMissingSlots(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	offer := newListedOffer("a", "one")
+	exporter := &export.Exporter{Client: newExportMock(map[string][]partner.Offer{"a": {offer}}), Dir: dir, Full: true}
+	_, err := exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "a", "one", "production.json"))
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("ListPublishers", mock.Anything).Return([]partner.Publisher{{Entity: partner.Entity{ID: "a"}}}, nil)
+	svcMock.On("ListOffers", mock.Anything, mock.Anything).Return([]partner.Offer{offer}, nil)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(&offer, nil)
+	svcMock.On("GetOfferBySlot", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), partner.ErrOfferNotFound)
+	svcMock.On("GetOfferStatus", mock.Anything, mock.Anything).Return(&partner.OfferStatus{Status: "succeeded"}, nil)
+	svcMock.On("ListOperations", mock.Anything, mock.Anything).Return([]partner.Operation{}, nil)
+	exporter.Client = svcMock
+	summary, err := exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Exported)
+
+	_, err = os.Stat(filepath.Join(dir, "a", "one", "production.json"))
+	assert.True(t, os.IsNotExist(err), "the file of a slot the offer is no longer in is removed")
+
+	manifest, err := export.ReadManifest(dir)
+	require.NoError(t, err)
+	entry, ok := manifest.Offer("a", "one")
+	require.True(t, ok)
+	assert.Equal(t, []string{"a/one/draft.json", "a/one/status.json", "a/one/operations.json"}, entry.Files)
+}
+
+func TestExporter_ExportReportsFailures(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	boomErr := errors.New("boom")
+	offer := newListedOffer("a", "one")
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("ListPublishers", mock.Anything).Return([]partner.Publisher{
+		{Entity: partner.Entity{ID: "a"}},
+		{Entity: partner.Entity{ID: "b"}},
+	}, nil)
+	svcMock.On("ListOffers", mock.Anything, partner.ListOffersParams{PublisherID: "a"}).Return([]partner.Offer{offer}, nil)
+	svcMock.On("ListOffers", mock.Anything, partner.ListOffersParams{PublisherID: "b"}).Return([]partner.Offer{}, boomErr)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return(&offer, nil)
+	svcMock.On("GetOfferBySlot", mock.Anything, mock.Anything).Return(&offer, nil)
+	svcMock.On("GetOfferStatus", mock.Anything, mock.Anything).Return((*partner.OfferStatus)(nil), boomErr)
+	svcMock.On("ListOperations", mock.Anything, mock.Anything).Return([]partner.Operation{}, nil)
+
+	exporter := &export.Exporter{Client: svcMock, Dir: dir}
+	summary, err := exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, []string{
+		"b: unable to list offers: boom",
+		"a/one: unable to get status: boom",
+	}, summary.Errors)
+
+	// an offer which failed is exported again on the next run
+	summary, err = exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, summary.Skipped)
+	assert.Equal(t, 1, summary.Failed)
+}
+
+func TestExporter_ExportFailsOnListPublishersError(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("ListPublishers", mock.Anything).Return([]partner.Publisher{}, errors.New("boom"))
+
+	_, err := (&export.Exporter{Client: svcMock, Dir: dir}).Export(context.Background())
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(dir, export.ManifestFileName))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestReadDrafts(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	_, err := export.ReadDrafts(dir)
+	assert.Error(t, err, "a directory without an export has no drafts")
+
+	exporter := &export.Exporter{Client: newExportMock(map[string][]partner.Offer{
+		"a": {newListedOffer("a", "one"), newListedOffer("a", "two")},
+		"b": {newListedOffer("b", "one")},
+	}), Dir: dir}
+	_, err = exporter.Export(context.Background())
+	require.NoError(t, err)
+
+	offers, err := export.ReadDrafts(dir)
+	require.NoError(t, err)
+	assert.Len(t, offers, 3)
+
+	offers, err = export.ReadDrafts(dir, "b")
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	assert.Equal(t, "b", offers[0].PublisherID)
+	assert.Equal(t, "one", offers[0].ID)
+}
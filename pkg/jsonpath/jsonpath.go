@@ -0,0 +1,254 @@
+// Package jsonpath evaluates a subset of JSONPath over decoded JSON documents.
+//
+// Supported syntax is a root of $ (or @ for a path relative to the current node) followed by any number of
+// .name, ['name'], ["name"], [index], .* and [*] selectors. Names which contain characters other than letters,
+// digits and underscores, such as the dotted keys used by the Cloud Partner Portal, must use the bracket form:
+//
+//	$.definition.plans[*]['microsoft-azure-virtualmachines.skuTitle']
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Match is a value selected by a path along with the normalized path to the value
+	Match struct {
+		Path  string
+		Value interface{}
+	}
+
+	// Path is a parsed JSONPath expression
+	Path struct {
+		Root      string
+		Selectors []Selector
+	}
+
+	// Selector is a single step in a path. A wildcard selects all children, otherwise either Name or Index is used.
+	Selector struct {
+		Name     string
+		Index    int
+		IsIndex  bool
+		Wildcard bool
+	}
+)
+
+// Parse parses a JSONPath expression
+func Parse(expr string) (*Path, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" || (expr[0] != '$' && expr[0] != '@') {
+		return nil, fmt.Errorf("path %q must start with $ or @", expr)
+	}
+
+	p := &Path{Root: expr[:1]}
+	rest := expr[1:]
+	for len(rest) > 0 {
+		switch {
+		case strings.HasPrefix(rest, ".*"):
+			p.Selectors = append(p.Selectors, Selector{Wildcard: true})
+			rest = rest[2:]
+		case rest[0] == '.':
+			end := 1
+			for end < len(rest) && isNameChar(rest[end]) {
+				end++
+			}
+			if end == 1 {
+				return nil, fmt.Errorf("path %q has an empty name at %q", expr, rest)
+			}
+			p.Selectors = append(p.Selectors, Selector{Name: rest[1:end]})
+			rest = rest[end:]
+		case rest[0] == '[':
+			sel, n, err := parseBracket(rest)
+			if err != nil {
+				return nil, fmt.Errorf("path %q: %v", expr, err)
+			}
+			p.Selectors = append(p.Selectors, sel)
+			rest = rest[n:]
+		default:
+			return nil, fmt.Errorf("path %q has unexpected characters at %q", expr, rest)
+		}
+	}
+	return p, nil
+}
+
+// Select parses the expression and selects all matching values from the document
+func Select(doc interface{}, expr string) ([]Match, error) {
+	p, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Select(doc), nil
+}
+
+// ToDocument converts a value, such as a partner.Offer, into a decoded JSON document by round tripping it through
+// encoding/json
+func ToDocument(v interface{}) (interface{}, error) {
+	bits, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(bits, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// IsDefinite returns true if the path can select at most one value
+func (p *Path) IsDefinite() bool {
+	for _, s := range p.Selectors {
+		if s.Wildcard {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the normalized form of the path
+func (p *Path) String() string {
+	var sb strings.Builder
+	sb.WriteString(p.Root)
+	for _, s := range p.Selectors {
+		sb.WriteString(s.String())
+	}
+	return sb.String()
+}
+
+// Select returns all values in the document which match the path. Matches on maps are returned in key order so
+// results are deterministic.
+func (p *Path) Select(doc interface{}) []Match {
+	matches := []Match{{Path: p.Root, Value: doc}}
+	for _, s := range p.Selectors {
+		var next []Match
+		for _, m := range matches {
+			next = append(next, s.apply(m)...)
+		}
+		matches = next
+	}
+	return matches
+}
+
+// String returns the normalized form of the selector
+func (s Selector) String() string {
+	switch {
+	case s.Wildcard:
+		return "[*]"
+	case s.IsIndex:
+		return fmt.Sprintf("[%d]", s.Index)
+	default:
+		return Child(s.Name)
+	}
+}
+
+// Child returns the normalized selector for a key, using the bracket form when the key is not a simple name
+func Child(name string) string {
+	for i := 0; i < len(name); i++ {
+		if !isSimpleNameChar(name[i]) {
+			return fmt.Sprintf("['%s']", strings.Replace(name, "'", `\'`, -1))
+		}
+	}
+	if name == "" {
+		return "['']"
+	}
+	return "." + name
+}
+
+func (s Selector) apply(m Match) []Match {
+	switch v := m.Value.(type) {
+	case map[string]interface{}:
+		if s.Wildcard {
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			matches := make([]Match, len(keys))
+			for i, k := range keys {
+				matches[i] = Match{Path: m.Path + Child(k), Value: v[k]}
+			}
+			return matches
+		}
+
+		if s.IsIndex {
+			return nil
+		}
+
+		if child, ok := v[s.Name]; ok {
+			return []Match{{Path: m.Path + Child(s.Name), Value: child}}
+		}
+	case []interface{}:
+		if s.Wildcard {
+			matches := make([]Match, len(v))
+			for i, child := range v {
+				matches[i] = Match{Path: fmt.Sprintf("%s[%d]", m.Path, i), Value: child}
+			}
+			return matches
+		}
+
+		idx := s.Index
+		if idx < 0 {
+			idx += len(v)
+		}
+
+		if s.IsIndex && idx >= 0 && idx < len(v) {
+			return []Match{{Path: fmt.Sprintf("%s[%d]", m.Path, idx), Value: v[idx]}}
+		}
+	}
+	return nil
+}
+
+func parseBracket(s string) (Selector, int, error) {
+	if len(s) < 3 {
+		return Selector{}, 0, fmt.Errorf("unterminated bracket at %q", s)
+	}
+
+	if s[1] == '\'' || s[1] == '"' {
+		quote := s[1]
+		var sb strings.Builder
+		for i := 2; i < len(s); i++ {
+			switch {
+			case s[i] == '\\' && i+1 < len(s):
+				i++
+				sb.WriteByte(s[i])
+			case s[i] == quote:
+				if i+1 >= len(s) || s[i+1] != ']' {
+					return Selector{}, 0, fmt.Errorf("expected ] after quoted name at %q", s)
+				}
+				return Selector{Name: sb.String()}, i + 2, nil
+			default:
+				sb.WriteByte(s[i])
+			}
+		}
+		return Selector{}, 0, fmt.Errorf("unterminated quoted name at %q", s)
+	}
+
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return Selector{}, 0, fmt.Errorf("unterminated bracket at %q", s)
+	}
+
+	inner := strings.TrimSpace(s[1:end])
+	if inner == "*" {
+		return Selector{Wildcard: true}, end + 1, nil
+	}
+
+	idx, err := strconv.Atoi(inner)
+	if err != nil {
+		return Selector{}, 0, fmt.Errorf("%q is not an index, wildcard or quoted name", inner)
+	}
+	return Selector{Index: idx, IsIndex: true}, end + 1, nil
+}
+
+func isNameChar(c byte) bool {
+	return isSimpleNameChar(c) || c == '-'
+}
+
+func isSimpleNameChar(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
@@ -0,0 +1,80 @@
+package jsonpath_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/jsonpath"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"$":                                "$",
+		"$.definition.plans[0]":            "$.definition.plans[0]",
+		"$.definition.plans[*].planId":     "$.definition.plans[*].planId",
+		"$.definition.plans.*":             "$.definition.plans[*]",
+		`$["definition"]['offer']`:         "$.definition.offer",
+		"$.definition.offer['a.b']":        "$.definition.offer['a.b']",
+		"@.virtualMachinePricing.isByol":   "@.virtualMachinePricing.isByol",
+		"$.definition.offer.with-dash":     "$.definition.offer['with-dash']",
+		`$['it\'s']`:                       `$['it\'s']`,
+		"$.definition.plans[-1]['planId']": "$.definition.plans[-1].planId",
+	}
+
+	for input, expected := range cases {
+		p, err := jsonpath.Parse(input)
+		if assert.NoError(t, err, input) {
+			assert.Equal(t, expected, p.String(), input)
+		}
+	}
+
+	for _, input := range []string{"", "definition", "$.", "$[", "$['a'", "$[a]", "$..a", "$ a"} {
+		_, err := jsonpath.Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	doc, err := jsonpath.ToDocument(test.NewMarketplaceVMOffer())
+	require.NoError(t, err)
+
+	matches, err := jsonpath.Select(doc, "$.definition.plans[*]['microsoft-azure-virtualmachines.vmImages'].*.osVhdUrl")
+	require.NoError(t, err)
+	assert.Equal(t, []jsonpath.Match{
+		{
+			Path:  "$.definition.plans[0]['microsoft-azure-virtualmachines.vmImages']['2018.1.1'].osVhdUrl",
+			Value: "osVhdUrl_one",
+		},
+		{
+			Path:  "$.definition.plans[0]['microsoft-azure-virtualmachines.vmImages']['2019.10.11'].osVhdUrl",
+			Value: "osVhdUrl_two",
+		},
+	}, matches)
+
+	matches, err = jsonpath.Select(doc, "$.definition.plans[-1].planId")
+	require.NoError(t, err)
+	assert.Equal(t, []jsonpath.Match{{Path: "$.definition.plans[0].planId", Value: "planId_one"}}, matches)
+
+	matches, err = jsonpath.Select(doc, "$.definition.plans[3].planId")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestPath_IsDefinite(t *testing.T) {
+	t.Parallel()
+
+	p, err := jsonpath.Parse("$.definition.plans[0].planId")
+	require.NoError(t, err)
+	assert.True(t, p.IsDefinite())
+
+	p, err = jsonpath.Parse("$.definition.plans[*].planId")
+	require.NoError(t, err)
+	assert.False(t, p.IsDefinite())
+}
@@ -0,0 +1,240 @@
+// Package layout writes offers to files for authoring and reads them back, either as a single JSON or YAML file or as
+// an exploded directory with the offer in offer.yaml and each SKU in skus/<planId>.yaml:
+//
+//	offer/
+//	  offer.yaml
+//	  skus/
+//	    sku-one.yaml
+//	    sku-two.yaml
+//
+// Fields which are set by the Cloud Partner Portal, like the ETag and the changed time, are not written.
+package layout
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/devigned/pub/pkg/document"
+	"github.com/devigned/pub/pkg/jsonpath"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+const (
+	// OfferFileName is the name of the offer file in an exploded directory
+	OfferFileName = "offer.yaml"
+
+	// SKUDirName is the name of the directory of SKU files in an exploded directory
+	SKUDirName = "skus"
+)
+
+var (
+	// serviceFields are the offer fields set by the Cloud Partner Portal which are left out of authored files
+	serviceFields = []string{"Etag", "changedTime"}
+)
+
+// IsDir returns true if path is a directory
+func IsDir(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
+
+// IsSKUFile returns true if path is a SKU file in an exploded directory
+func IsSKUFile(path string) bool {
+	return filepath.Base(filepath.Dir(path)) == SKUDirName
+}
+
+// WriteFile writes the offer to a single file, as YAML if the file has a .yaml or .yml extension and otherwise as JSON
+func WriteFile(path string, offer *partner.Offer, overwrite bool) error {
+	doc, err := authoringDocument(offer)
+	if err != nil {
+		return err
+	}
+	return writeDocument(path, doc, overwrite)
+}
+
+// WriteDir writes the offer to the directory in the exploded layout
+func WriteDir(dir string, offer *partner.Offer, overwrite bool) error {
+	doc, err := authoringDocument(offer)
+	if err != nil {
+		return err
+	}
+
+	var plans []interface{}
+	if definition, ok := doc["definition"].(map[string]interface{}); ok {
+		plans, _ = definition["plans"].([]interface{})
+		delete(definition, "plans")
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, SKUDirName), 0755); err != nil {
+		return err
+	}
+
+	if err := writeDocument(filepath.Join(dir, OfferFileName), doc, overwrite); err != nil {
+		return err
+	}
+
+	for i, plan := range plans {
+		m, _ := plan.(map[string]interface{})
+		id, _ := m["planId"].(string)
+		if id == "" || strings.ContainsAny(id, `/\`) {
+			return fmt.Errorf("plan %d has an ID which cannot be used as a file name: %q", i, id)
+		}
+
+		if err := writeDocument(filepath.Join(dir, SKUDirName, id+".yaml"), plan, overwrite); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadDir reads an offer from a directory in the exploded layout and returns it as a JSON document. Each file is
+// passed through transform, for example to render it as a template, before it is parsed.
+func ReadDir(dir string, transform func(path string, bits []byte) ([]byte, error)) ([]byte, error) {
+	offerPath, err := findOfferFile(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	offerDocs, err := readFile(offerPath, transform)
+	if err != nil {
+		return nil, err
+	}
+
+	offerBits, err := document.Single(offerPath, offerDocs)
+	if err != nil {
+		return nil, err
+	}
+
+	var offer map[string]interface{}
+	if err := json.Unmarshal(offerBits, &offer); err != nil {
+		return nil, fmt.Errorf("%s must contain an offer object: %v", offerPath, err)
+	}
+
+	skuPaths, err := skuFiles(filepath.Join(dir, SKUDirName))
+	if err != nil {
+		return nil, err
+	}
+
+	plans := []interface{}{}
+	for _, path := range skuPaths {
+		docs, err := readFile(path, transform)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, bits := range docs {
+			var plan interface{}
+			if err := json.Unmarshal(bits, &plan); err != nil {
+				return nil, fmt.Errorf("unable to read %s: %v", path, err)
+			}
+			plans = append(plans, plan)
+		}
+	}
+
+	definition, ok := offer["definition"].(map[string]interface{})
+	if !ok {
+		definition = make(map[string]interface{})
+		offer["definition"] = definition
+	}
+
+	if _, ok := definition["plans"]; ok && len(plans) > 0 {
+		return nil, fmt.Errorf("%s must not contain plans when %s has SKU files", offerPath, SKUDirName)
+	}
+
+	if len(plans) > 0 {
+		definition["plans"] = plans
+	}
+	return json.Marshal(offer)
+}
+
+// authoringDocument converts the offer to a JSON document without the fields set by the Cloud Partner Portal
+func authoringDocument(offer *partner.Offer) (map[string]interface{}, error) {
+	doc, err := jsonpath.ToDocument(offer)
+	if err != nil {
+		return nil, err
+	}
+
+	m := doc.(map[string]interface{})
+	for _, field := range serviceFields {
+		delete(m, field)
+	}
+	return m, nil
+}
+
+func writeDocument(path string, doc interface{}, overwrite bool) error {
+	if !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		}
+	}
+
+	var bits []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		bits, err = yaml.Marshal(doc)
+	default:
+		bits, err = json.MarshalIndent(doc, "", "  ")
+		bits = append(bits, '\n')
+	}
+
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bits, 0644)
+}
+
+func findOfferFile(dir string) (string, error) {
+	for _, name := range []string{OfferFileName, "offer.yml", "offer.json"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("directory %s does not contain an %s file", dir, OfferFileName)
+}
+
+// skuFiles returns the JSON and YAML files in the SKU directory in sorted order, or none if it does not exist
+func skuFiles(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, fi := range infos {
+		switch strings.ToLower(filepath.Ext(fi.Name())) {
+		case ".yaml", ".yml", ".json":
+			if !fi.IsDir() {
+				paths = append(paths, filepath.Join(dir, fi.Name()))
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func readFile(path string, transform func(path string, bits []byte) ([]byte, error)) ([][]byte, error) {
+	bits, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if transform != nil {
+		if bits, err = transform(path, bits); err != nil {
+			return nil, err
+		}
+	}
+	return document.ToJSON(path, bits)
+}
@@ -0,0 +1,106 @@
+package layout
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func newOffer() *partner.Offer {
+	return &partner.Offer{
+		Entity:      partner.Entity{ID: "widget"},
+		Etag:        "\"1\"",
+		TypeID:      partner.VirtualMachineOfferType,
+		PublisherID: "contoso",
+		Definition: partner.OfferDefinition{
+			DisplayText: "Widget",
+			Plans: []partner.Plan{
+				{ID: "widget-basic"},
+				{ID: "widget-premium"},
+			},
+		},
+	}
+}
+
+func TestWriteDir_ReadDir(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	require.NoError(t, WriteDir(dir, newOffer(), false))
+	assert.FileExists(t, filepath.Join(dir, OfferFileName))
+	assert.FileExists(t, filepath.Join(dir, SKUDirName, "widget-basic.yaml"))
+	assert.FileExists(t, filepath.Join(dir, SKUDirName, "widget-premium.yaml"))
+
+	bits, err := ReadDir(dir, nil)
+	require.NoError(t, err)
+
+	var offer partner.Offer
+	require.NoError(t, json.Unmarshal(bits, &offer))
+	assert.Equal(t, "widget", offer.ID)
+	assert.Empty(t, offer.Etag)
+	require.Len(t, offer.Definition.Plans, 2)
+	assert.Equal(t, "widget-basic", offer.Definition.Plans[0].ID)
+	assert.Equal(t, "widget-premium", offer.Definition.Plans[1].ID)
+
+	assert.Error(t, WriteDir(dir, newOffer(), false))
+	assert.NoError(t, WriteDir(dir, newOffer(), true))
+}
+
+func TestReadDir_Transform(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	require.NoError(t, WriteDir(dir, newOffer(), false))
+	bits, err := ReadDir(dir, func(path string, bits []byte) ([]byte, error) {
+		if filepath.Base(path) == OfferFileName {
+			return []byte("id: gadget\n"), nil
+		}
+		return bits, nil
+	})
+	require.NoError(t, err)
+
+	var offer partner.Offer
+	require.NoError(t, json.Unmarshal(bits, &offer))
+	assert.Equal(t, "gadget", offer.ID)
+	assert.Len(t, offer.Definition.Plans, 2)
+}
+
+func TestReadDir_FailOnPlansInBothFiles(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	require.NoError(t, WriteDir(dir, newOffer(), false))
+	require.NoError(t, WriteFile(filepath.Join(dir, OfferFileName), newOffer(), true))
+	_, err := ReadDir(dir, nil)
+	assert.Error(t, err)
+}
+
+func TestReadDir_FailWithoutOfferFile(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	_, err := ReadDir(dir, nil)
+	assert.Error(t, err)
+}
+
+func TestWriteFile_JSON(t *testing.T) {
+	dir, del := test.NewTmpDir(t)
+	defer del()
+
+	path := filepath.Join(dir, "offer.json")
+	require.NoError(t, WriteFile(path, newOffer(), false))
+	bits, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(bits, &doc))
+	assert.Equal(t, "widget", doc["id"])
+	assert.NotContains(t, doc, "Etag")
+}
@@ -0,0 +1,195 @@
+// Package lint checks offers against marketplace certification rules before they are submitted to the Cloud Partner
+// Portal.
+package lint
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/devigned/pub/pkg/partner"
+)
+
+const (
+	// ErrorSeverity is a finding which will cause certification to fail
+	ErrorSeverity Severity = "error"
+
+	// WarningSeverity is a finding which is likely to be a mistake, but will not necessarily fail certification
+	WarningSeverity Severity = "warning"
+
+	// JSONReportFormat outputs the report as is
+	JSONReportFormat ReportFormat = "json"
+
+	// SARIFReportFormat outputs the report as a SARIF log
+	SARIFReportFormat ReportFormat = "sarif"
+)
+
+type (
+	// Severity is the level of a lint finding
+	Severity string
+
+	// ReportFormat is the output format of a report
+	ReportFormat string
+
+	// Violation is a single problem found by a rule at a JSON path within the offer
+	Violation struct {
+		Path    string
+		Message string
+	}
+
+	// Rule is a check over an offer which produces violations
+	Rule struct {
+		ID          string
+		Description string
+		Severity    Severity
+		Check       func(offer *partner.Offer) []Violation
+	}
+
+	// Finding is a violation of a rule reported by the Linter
+	Finding struct {
+		RuleID   string   `json:"ruleId"`
+		Severity Severity `json:"severity"`
+		Path     string   `json:"path"`
+		Message  string   `json:"message"`
+	}
+
+	// Report is the result of linting an offer
+	Report struct {
+		PublisherID string    `json:"publisherId,omitempty"`
+		OfferID     string    `json:"offerId,omitempty"`
+		Source      string    `json:"source,omitempty"`
+		Errors      int       `json:"errors"`
+		Warnings    int       `json:"warnings"`
+		Findings    []Finding `json:"findings"`
+		rules       []Rule
+	}
+
+	// Linter evaluates a set of rules over an offer
+	Linter struct {
+		Rules      []Rule
+		Suppressed map[string]bool
+	}
+)
+
+// New creates a Linter with the given rules, skipping any rules whose IDs are suppressed
+func New(rules []Rule, suppress ...string) (*Linter, error) {
+	known := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		known[rule.ID] = true
+	}
+
+	suppressed := make(map[string]bool, len(suppress))
+	for _, id := range suppress {
+		if !known[id] {
+			return nil, fmt.Errorf("unable to suppress unknown rule %q", id)
+		}
+		suppressed[id] = true
+	}
+
+	return &Linter{
+		Rules:      rules,
+		Suppressed: suppressed,
+	}, nil
+}
+
+// Lint runs each of the rules which are not suppressed over the offer
+func (l *Linter) Lint(offer *partner.Offer) *Report {
+	report := &Report{
+		PublisherID: offer.PublisherID,
+		OfferID:     offer.ID,
+		Findings:    []Finding{},
+	}
+
+	for _, rule := range l.Rules {
+		if l.Suppressed[rule.ID] {
+			continue
+		}
+		report.rules = append(report.rules, rule)
+
+		for _, v := range rule.Check(offer) {
+			report.Findings = append(report.Findings, Finding{
+				RuleID:   rule.ID,
+				Severity: rule.Severity,
+				Path:     v.Path,
+				Message:  v.Message,
+			})
+
+			if rule.Severity == ErrorSeverity {
+				report.Errors++
+			} else {
+				report.Warnings++
+			}
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Path < report.Findings[j].Path
+	})
+	return report
+}
+
+// HasErrors returns true if any of the findings are errors
+func (r *Report) HasErrors() bool {
+	return r.Errors > 0
+}
+
+// OfferDetailPath returns the JSON path to a field of the offer detail structs in partner, such as
+// partner.MarketplaceDetail{} and "Title"
+func OfferDetailPath(detail interface{}, field string) string {
+	return fmt.Sprintf("$.definition.offer%s", fieldSelector(detail, field))
+}
+
+// PlanPath returns the JSON path to a field of the plan detail structs in partner for the plan at index i
+func PlanPath(i int, detail interface{}, field string) string {
+	return fmt.Sprintf("$.definition.plans[%d]%s", i, fieldSelector(detail, field))
+}
+
+// JSONKey returns the JSON key for a field of a struct based on its json tag
+func JSONKey(v interface{}, field string) string {
+	f, ok := reflect.TypeOf(v).FieldByName(field)
+	if !ok {
+		panic(fmt.Sprintf("%T has no field %s", v, field))
+	}
+
+	key := strings.Split(f.Tag.Get("json"), ",")[0]
+	if key == "" {
+		return field
+	}
+	return key
+}
+
+func fieldSelector(v interface{}, field string) string {
+	key := JSONKey(v, field)
+	if strings.ContainsAny(key, ".-") {
+		return fmt.Sprintf("['%s']", key)
+	}
+	return "." + key
+}
+
+// String returns the finding formatted for display on a single line
+func (f Finding) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", f.Severity, f.RuleID, f.Path, f.Message)
+}
+
+// Output returns the report in the given format, suitable for printing
+func (r *Report) Output(format ReportFormat) (interface{}, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	if format == SARIFReportFormat {
+		return r.SARIF(), nil
+	}
+	return r, nil
+}
+
+// Validate returns an error if the format is not known
+func (f ReportFormat) Validate() error {
+	switch f {
+	case JSONReportFormat, SARIFReportFormat:
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q; must be one of: %s, %s", f, JSONReportFormat, SARIFReportFormat)
+	}
+}
@@ -0,0 +1,187 @@
+package lint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/lint"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func newValidVMOffer() *partner.Offer {
+	offer := test.NewMarketplaceVMOffer()
+	offer.Definition.OfferDetail.PrivacyURL = "https://contoso.com/privacy"
+	offer.Definition.OfferDetail.SupportContactEmail = "support@contoso.com"
+	return offer
+}
+
+func findingIDs(report *lint.Report) []string {
+	ids := make([]string, len(report.Findings))
+	for i, f := range report.Findings {
+		ids[i] = f.RuleID
+	}
+	return ids
+}
+
+func TestNew_FailOnUnknownSuppression(t *testing.T) {
+	_, err := lint.New(lint.DefaultRules(), "not-a-rule")
+	assert.Error(t, err)
+}
+
+func TestLint_ValidVMOffer(t *testing.T) {
+	linter, err := lint.New(lint.DefaultRules())
+	require.NoError(t, err)
+
+	report := linter.Lint(newValidVMOffer())
+	assert.Empty(t, report.Findings)
+	assert.False(t, report.HasErrors())
+}
+
+func TestLint_VMOfferFindings(t *testing.T) {
+	offer := newValidVMOffer()
+	offer.Definition.OfferDetail.MarketplaceDetail.Summary = strings.Repeat("a", lint.MaxSummaryLength+1)
+	offer.Definition.OfferDetail.SmallLogo = ""
+	offer.Definition.OfferDetail.PrivacyURL = "privacy"
+	offer.Definition.OfferDetail.SupportContactEmail = "nope"
+	plan := &offer.Definition.Plans[0]
+	plan.PlanVirtualMachineDetail.OperatingSystemFamily = ""
+	plan.PlanVirtualMachineDetail.RecommendedVirtualMachineSizes = nil
+	plan.PlanVirtualMachineDetail.VMImages["latest"] = partner.VirtualMachineImage{}
+
+	linter, err := lint.New(lint.DefaultRules())
+	require.NoError(t, err)
+	report := linter.Lint(offer)
+
+	assert.ElementsMatch(t, []string{
+		"summary-length",
+		"logos-required",
+		"privacy-url",
+		"support-email",
+		"os-family",
+		"recommended-vm-sizes",
+		"image-versions",
+		"image-version-format",
+	}, findingIDs(report))
+	assert.Equal(t, 6, report.Errors)
+	assert.Equal(t, 2, report.Warnings)
+
+	for _, f := range report.Findings {
+		switch f.RuleID {
+		case "summary-length":
+			assert.Equal(t, "$.definition.offer['microsoft-azure-marketplace.summary']", f.Path)
+		case "os-family":
+			assert.Equal(t, "$.definition.plans[0]['microsoft-azure-virtualmachines.operatingSystemFamily']", f.Path)
+		case "image-versions":
+			assert.Equal(t, "$.definition.plans[0]['microsoft-azure-virtualmachines.vmImages']['latest'].osVhdUrl", f.Path)
+		}
+	}
+}
+
+func TestLint_Suppress(t *testing.T) {
+	offer := newValidVMOffer()
+	offer.Definition.OfferDetail.SupportContactEmail = "nope"
+
+	linter, err := lint.New(lint.DefaultRules(), "support-email")
+	require.NoError(t, err)
+	report := linter.Lint(offer)
+	assert.Empty(t, report.Findings)
+
+	for _, rule := range report.SARIF().Runs[0].Tool.Driver.Rules {
+		assert.NotEqual(t, "support-email", rule.ID)
+	}
+}
+
+func TestLint_CoreVMCloudDescriptions(t *testing.T) {
+	offer := test.NewMarketplaceCoreVMOffer()
+	offer.Definition.Plans[0].PlanCoreVMDetail.CloudAvailability = []partner.CloudAvailabilityOption{
+		partner.PublicOption,
+		partner.GovCloud,
+		partner.ChinaOption,
+	}
+	offer.Definition.Plans[0].PlanCoreVMDetail.SKUDescriptionMooncake = "mooncake"
+
+	linter, err := lint.New(lint.DefaultRules())
+	require.NoError(t, err)
+	report := linter.Lint(offer)
+
+	var findings []lint.Finding
+	for _, f := range report.Findings {
+		if f.RuleID == "cloud-descriptions" {
+			findings = append(findings, f)
+		}
+	}
+	require.Len(t, findings, 1)
+	assert.Equal(t, "$.definition.plans[0]['microsoft-azure-corevm.skuDescriptionFairfax']", findings[0].Path)
+}
+
+func TestReport_SARIF(t *testing.T) {
+	offer := newValidVMOffer()
+	offer.Definition.OfferDetail.MarketplaceDetail.Title = ""
+
+	linter, err := lint.New(lint.DefaultRules())
+	require.NoError(t, err)
+	report := linter.Lint(offer)
+	report.Source = "offer.json"
+
+	log := report.SARIF()
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, len(lint.DefaultRules()))
+	require.Len(t, log.Runs[0].Results, 1)
+
+	result := log.Runs[0].Results[0]
+	assert.Equal(t, "title-required", result.RuleID)
+	assert.Equal(t, "error", result.Level)
+	assert.Equal(t, "offer.json", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "$.definition.offer['microsoft-azure-marketplace.title']", result.Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
+
+func TestPublishRules(t *testing.T) {
+	offer := newValidVMOffer()
+	offer.Definition.OfferDetail.SupportContactEmail = "nope"
+	offer.Definition.Plans[0].PlanVirtualMachineDetail.VMImages = nil
+
+	linter, err := lint.New(lint.PublishRules())
+	require.NoError(t, err)
+	report := linter.Lint(offer)
+	assert.Equal(t, []string{"image-versions"}, findingIDs(report))
+}
+
+func TestLint_ApplicationOffer(t *testing.T) {
+	linter, err := lint.New(lint.DefaultRules())
+	require.NoError(t, err)
+
+	offer := test.NewMarketplaceApplicationOffer()
+	assert.Empty(t, linter.Lint(offer).Findings)
+
+	plan := &offer.Definition.Plans[0].PlanApplicationDetail
+	plan.Authorizations = nil
+	plan.Packages["latest"] = partner.ApplicationPackage{}
+	report := linter.Lint(offer)
+
+	assert.ElementsMatch(t, []string{
+		"image-version-format",
+		"package-versions",
+		"managed-app-authorizations",
+	}, findingIDs(report))
+
+	for _, f := range report.Findings {
+		if f.RuleID == "package-versions" {
+			assert.Equal(t, "$.definition.plans[0]['microsoft-azure-applications.packages']['latest'].packageUrl", f.Path)
+		}
+	}
+}
+
+func TestLint_ContainerAndSaaSOffers(t *testing.T) {
+	linter, err := lint.New(lint.DefaultRules())
+	require.NoError(t, err)
+
+	for _, offer := range []*partner.Offer{test.NewMarketplaceContainerOffer(), test.NewMarketplaceSaaSOffer()} {
+		offer.Definition.OfferDetail.SupportContactEmail = "support@contoso.com"
+		assert.Empty(t, linter.Lint(offer).Findings, offer.TypeID)
+	}
+}
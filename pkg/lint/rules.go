@@ -0,0 +1,439 @@
+package lint
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"unicode/utf8"
+
+	"github.com/devigned/pub/pkg/partner"
+)
+
+const (
+	// MaxTitleLength is the maximum number of characters in an offer title
+	MaxTitleLength = 50
+
+	// MaxSummaryLength is the maximum number of characters in an offer summary
+	MaxSummaryLength = 100
+
+	// MaxLongSummaryLength is the maximum number of characters in an offer long summary
+	MaxLongSummaryLength = 256
+)
+
+type (
+	// listing is the set of offer listing fields shared by the offer types with their JSON paths
+	listing struct {
+		Title, TitlePath             string
+		Summary, SummaryPath         string
+		LongSummary, LongSummaryPath string
+		PrivacyURL, PrivacyURLPath   string
+	}
+)
+
+// DefaultRules returns the built-in marketplace certification rules
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			ID:          "title-required",
+			Description: fmt.Sprintf("The offer must have a title of at most %d characters", MaxTitleLength),
+			Severity:    ErrorSeverity,
+			Check:       checkTitle,
+		},
+		{
+			ID:          "summary-length",
+			Description: fmt.Sprintf("The offer must have a summary of at most %d characters and a long summary of at most %d characters", MaxSummaryLength, MaxLongSummaryLength),
+			Severity:    ErrorSeverity,
+			Check:       checkSummary,
+		},
+		{
+			ID:          "logos-required",
+			Description: "The small and medium logos must be provided",
+			Severity:    ErrorSeverity,
+			Check:       checkLogos,
+		},
+		{
+			ID:          "privacy-url",
+			Description: "The privacy policy URL must be an absolute http or https URL",
+			Severity:    ErrorSeverity,
+			Check:       checkPrivacyURL,
+		},
+		{
+			ID:          "support-email",
+			Description: "The support contact email should be a valid email address",
+			Severity:    WarningSeverity,
+			Check:       checkSupportEmail,
+		},
+		{
+			ID:          "recommended-vm-sizes",
+			Description: "Each plan should recommend at least one VM size",
+			Severity:    WarningSeverity,
+			Check:       checkRecommendedVMSizes,
+		},
+		{
+			ID:          "os-family",
+			Description: "Each plan must specify an operating system family of Linux or Windows",
+			Severity:    ErrorSeverity,
+			Check:       checkOSFamily,
+		},
+		{
+			ID:          "cloud-descriptions",
+			Description: "Each cloud a plan is available in must have a matching SKU description",
+			Severity:    ErrorSeverity,
+			Check:       checkCloudDescriptions,
+		},
+		{
+			ID:          "image-versions",
+			Description: "Each plan must have at least one image version with an OS VHD URL",
+			Severity:    ErrorSeverity,
+			Check:       checkImageVersions,
+		},
+		{
+			ID:          "image-version-format",
+			Description: "Image and package versions must be in the Major.Minor.Patch format",
+			Severity:    ErrorSeverity,
+			Check:       checkImageVersionFormat,
+		},
+		{
+			ID:          "package-versions",
+			Description: "Each Azure Application plan must have at least one package version with a package URL",
+			Severity:    ErrorSeverity,
+			Check:       checkPackageVersions,
+		},
+		{
+			ID:          "managed-app-authorizations",
+			Description: "Each managed application plan must authorize at least one principal with a role definition",
+			Severity:    ErrorSeverity,
+			Check:       checkManagedAppAuthorizations,
+		},
+	}
+}
+
+// PublishRules returns the subset of the built-in rules which must pass before an offer can be published: the
+// listing must have a title, summary and logos and each plan must have an image or package version to publish
+func PublishRules() []Rule {
+	ids := map[string]bool{
+		"title-required":   true,
+		"summary-length":   true,
+		"logos-required":   true,
+		"image-versions":   true,
+		"package-versions": true,
+	}
+
+	var rules []Rule
+	for _, rule := range DefaultRules() {
+		if ids[rule.ID] {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func getListing(offer *partner.Offer) *listing {
+	detail := offer.Definition.OfferDetail
+	if detail == nil {
+		detail = new(partner.OfferDetail)
+	}
+
+	if offer.TypeID == partner.CoreVMOfferType {
+		cvm := partner.CoreVMOfferDetail{}
+		return &listing{
+			Title:       detail.CoreVMOfferDetail.Title,
+			TitlePath:   OfferDetailPath(cvm, "Title"),
+			Summary:     detail.CoreVMOfferDetail.Summary,
+			SummaryPath: OfferDetailPath(cvm, "Summary"),
+		}
+	}
+
+	md := partner.MarketplaceDetail{}
+	return &listing{
+		Title:           detail.MarketplaceDetail.Title,
+		TitlePath:       OfferDetailPath(md, "Title"),
+		Summary:         detail.MarketplaceDetail.Summary,
+		SummaryPath:     OfferDetailPath(md, "Summary"),
+		LongSummary:     detail.MarketplaceDetail.LongSummary,
+		LongSummaryPath: OfferDetailPath(md, "LongSummary"),
+		PrivacyURL:      detail.MarketplaceDetail.PrivacyURL,
+		PrivacyURLPath:  OfferDetailPath(md, "PrivacyURL"),
+	}
+}
+
+func checkTitle(offer *partner.Offer) []Violation {
+	l := getListing(offer)
+	switch {
+	case l.Title == "":
+		return []Violation{{Path: l.TitlePath, Message: "title is required"}}
+	case utf8.RuneCountInString(l.Title) > MaxTitleLength:
+		return []Violation{{Path: l.TitlePath, Message: fmt.Sprintf("title is %d characters, but must be at most %d", utf8.RuneCountInString(l.Title), MaxTitleLength)}}
+	default:
+		return nil
+	}
+}
+
+func checkSummary(offer *partner.Offer) []Violation {
+	l := getListing(offer)
+	var violations []Violation
+	switch {
+	case l.Summary == "":
+		violations = append(violations, Violation{Path: l.SummaryPath, Message: "summary is required"})
+	case utf8.RuneCountInString(l.Summary) > MaxSummaryLength:
+		violations = append(violations, Violation{Path: l.SummaryPath, Message: fmt.Sprintf("summary is %d characters, but must be at most %d", utf8.RuneCountInString(l.Summary), MaxSummaryLength)})
+	}
+
+	if utf8.RuneCountInString(l.LongSummary) > MaxLongSummaryLength {
+		violations = append(violations, Violation{Path: l.LongSummaryPath, Message: fmt.Sprintf("long summary is %d characters, but must be at most %d", utf8.RuneCountInString(l.LongSummary), MaxLongSummaryLength)})
+	}
+	return violations
+}
+
+func checkLogos(offer *partner.Offer) []Violation {
+	var violations []Violation
+	if offer.TypeID == partner.CoreVMOfferType {
+		for i, plan := range offer.Definition.Plans {
+			for field, value := range map[string]string{
+				"SmallLogo":  plan.PlanCoreVMDetail.SmallLogo,
+				"MediumLogo": plan.PlanCoreVMDetail.MediumLogo,
+			} {
+				if value == "" {
+					violations = append(violations, Violation{Path: PlanPath(i, partner.PlanCoreVMDetail{}, field), Message: fmt.Sprintf("plan %q is missing a logo", plan.ID)})
+				}
+			}
+		}
+		return violations
+	}
+
+	var md partner.MarketplaceDetail
+	if offer.Definition.OfferDetail != nil {
+		md = offer.Definition.OfferDetail.MarketplaceDetail
+	}
+
+	for field, value := range map[string]string{
+		"SmallLogo":  md.SmallLogo,
+		"MediumLogo": md.MediumLogo,
+	} {
+		if value == "" {
+			violations = append(violations, Violation{Path: OfferDetailPath(md, field), Message: "logo is required"})
+		}
+	}
+	return violations
+}
+
+func checkPrivacyURL(offer *partner.Offer) []Violation {
+	if offer.TypeID == partner.CoreVMOfferType {
+		var violations []Violation
+		for i, plan := range offer.Definition.Plans {
+			if msg := validateURL(plan.PlanCoreVMDetail.PrivacyURL); msg != "" {
+				violations = append(violations, Violation{Path: PlanPath(i, partner.PlanCoreVMDetail{}, "PrivacyURL"), Message: msg})
+			}
+		}
+		return violations
+	}
+
+	l := getListing(offer)
+	if msg := validateURL(l.PrivacyURL); msg != "" {
+		return []Violation{{Path: l.PrivacyURLPath, Message: msg}}
+	}
+	return nil
+}
+
+func checkSupportEmail(offer *partner.Offer) []Violation {
+	if offer.TypeID == partner.CoreVMOfferType || offer.Definition.OfferDetail == nil {
+		return nil
+	}
+
+	md := offer.Definition.OfferDetail.MarketplaceDetail
+	if _, err := mail.ParseAddress(md.SupportContactEmail); err != nil {
+		return []Violation{{Path: OfferDetailPath(md, "SupportContactEmail"), Message: fmt.Sprintf("%q is not a valid email address", md.SupportContactEmail)}}
+	}
+	return nil
+}
+
+func checkRecommendedVMSizes(offer *partner.Offer) []Violation {
+	if !isVMOffer(offer) {
+		return nil
+	}
+
+	var violations []Violation
+	for i, plan := range offer.Definition.Plans {
+		if offer.TypeID == partner.CoreVMOfferType {
+			if len(plan.PlanCoreVMDetail.RecommendedVMSizes) == 0 {
+				violations = append(violations, Violation{Path: PlanPath(i, plan.PlanCoreVMDetail, "RecommendedVMSizes"), Message: fmt.Sprintf("plan %q has no recommended VM sizes", plan.ID)})
+			}
+			continue
+		}
+
+		if len(plan.PlanVirtualMachineDetail.RecommendedVirtualMachineSizes) == 0 {
+			violations = append(violations, Violation{Path: PlanPath(i, plan.PlanVirtualMachineDetail, "RecommendedVirtualMachineSizes"), Message: fmt.Sprintf("plan %q has no recommended VM sizes", plan.ID)})
+		}
+	}
+	return violations
+}
+
+func checkOSFamily(offer *partner.Offer) []Violation {
+	if !isVMOffer(offer) {
+		return nil
+	}
+
+	var violations []Violation
+	for i, plan := range offer.Definition.Plans {
+		family, path := plan.PlanVirtualMachineDetail.OperatingSystemFamily, PlanPath(i, plan.PlanVirtualMachineDetail, "OperatingSystemFamily")
+		if offer.TypeID == partner.CoreVMOfferType {
+			family, path = plan.PlanCoreVMDetail.OperatingSystemFamily, PlanPath(i, plan.PlanCoreVMDetail, "OperatingSystemFamily")
+		}
+
+		switch family {
+		case "Linux", "Windows":
+		case "":
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("plan %q is missing an operating system family", plan.ID)})
+		default:
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("plan %q has operating system family %q, but must be Linux or Windows", plan.ID, family)})
+		}
+	}
+	return violations
+}
+
+func checkCloudDescriptions(offer *partner.Offer) []Violation {
+	if offer.TypeID != partner.CoreVMOfferType {
+		return nil
+	}
+
+	var violations []Violation
+	for i, plan := range offer.Definition.Plans {
+		detail := plan.PlanCoreVMDetail
+		for _, cloud := range detail.CloudAvailability {
+			var description, field string
+			switch cloud {
+			case partner.GovCloud:
+				description, field = detail.SKUDescriptionFairfax, "SKUDescriptionFairfax"
+			case partner.ChinaOption:
+				description, field = detail.SKUDescriptionMooncake, "SKUDescriptionMooncake"
+			default:
+				continue
+			}
+
+			if description == "" {
+				violations = append(violations, Violation{Path: PlanPath(i, detail, field), Message: fmt.Sprintf("plan %q is available in %s, but has no %s SKU description", plan.ID, cloud, cloud)})
+			}
+		}
+	}
+	return violations
+}
+
+func checkImageVersions(offer *partner.Offer) []Violation {
+	if !isVMOffer(offer) {
+		return nil
+	}
+
+	var violations []Violation
+	for i, plan := range offer.Definition.Plans {
+		path := PlanPath(i, plan.PlanVirtualMachineDetail, "VMImages")
+		if offer.TypeID == partner.CoreVMOfferType {
+			path = PlanPath(i, plan.PlanCoreVMDetail, "VMImages")
+		}
+
+		images := plan.GetVMImages()
+		if len(images) == 0 {
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("plan %q has no image versions", plan.ID)})
+			continue
+		}
+
+		for _, version := range images.Versions() {
+			if images[version].OSVHDURL == "" {
+				violations = append(violations, Violation{Path: fmt.Sprintf("%s['%s'].osVhdUrl", path, version), Message: fmt.Sprintf("plan %q image version %q has no OS VHD URL", plan.ID, version)})
+			}
+		}
+	}
+	return violations
+}
+
+func checkImageVersionFormat(offer *partner.Offer) []Violation {
+	var violations []Violation
+	for i, plan := range offer.Definition.Plans {
+		path, versions := PlanPath(i, plan.PlanVirtualMachineDetail, "VMImages"), plan.GetVMImages().Versions()
+		switch offer.TypeID {
+		case partner.CoreVMOfferType:
+			path = PlanPath(i, plan.PlanCoreVMDetail, "VMImages")
+		case partner.ApplicationOfferType:
+			path, versions = PlanPath(i, plan.PlanApplicationDetail, "Packages"), plan.GetApplicationPackages().Versions()
+		}
+
+		for _, version := range versions {
+			if _, err := partner.ParseImageVersion(version); err != nil {
+				violations = append(violations, Violation{Path: fmt.Sprintf("%s['%s']", path, version), Message: err.Error()})
+			}
+		}
+	}
+	return violations
+}
+
+func checkPackageVersions(offer *partner.Offer) []Violation {
+	if offer.TypeID != partner.ApplicationOfferType {
+		return nil
+	}
+
+	var violations []Violation
+	for i, plan := range offer.Definition.Plans {
+		path := PlanPath(i, plan.PlanApplicationDetail, "Packages")
+		packages := plan.GetApplicationPackages()
+		if len(packages) == 0 {
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("plan %q has no package versions", plan.ID)})
+			continue
+		}
+
+		for _, version := range packages.Versions() {
+			if packages[version].PackageURI == "" {
+				violations = append(violations, Violation{Path: fmt.Sprintf("%s['%s'].packageUrl", path, version), Message: fmt.Sprintf("plan %q package version %q has no package URL", plan.ID, version)})
+			}
+		}
+	}
+	return violations
+}
+
+func checkManagedAppAuthorizations(offer *partner.Offer) []Violation {
+	if offer.TypeID != partner.ApplicationOfferType {
+		return nil
+	}
+
+	var violations []Violation
+	for i, plan := range offer.Definition.Plans {
+		detail := plan.PlanApplicationDetail
+		if detail.SolutionType != partner.ManagedApplicationType {
+			continue
+		}
+
+		path := PlanPath(i, detail, "Authorizations")
+		if len(detail.Authorizations) == 0 {
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("managed application plan %q has no authorizations", plan.ID)})
+			continue
+		}
+
+		for j, auth := range detail.Authorizations {
+			if auth.PrincipalID == "" || auth.RoleDefinitionID == "" {
+				violations = append(violations, Violation{Path: fmt.Sprintf("%s[%d]", path, j), Message: fmt.Sprintf("managed application plan %q has an authorization without a principalId and roleDefinitionId", plan.ID)})
+			}
+		}
+	}
+	return violations
+}
+
+// isVMOffer returns true if the offer is one of the virtual machine offer types, which have images, VM sizes and
+// operating systems. Offers without a type are treated as virtual machine offers.
+func isVMOffer(offer *partner.Offer) bool {
+	switch offer.TypeID {
+	case "", partner.VirtualMachineOfferType, partner.CoreVMOfferType:
+		return true
+	default:
+		return false
+	}
+}
+
+func validateURL(raw string) string {
+	if raw == "" {
+		return "privacy policy URL is required"
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Sprintf("%q is not an absolute http or https URL", raw)
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package lint
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type (
+	// SARIFLog is a minimal Static Analysis Results Interchange Format (SARIF) 2.1.0 log, which CI systems use to
+	// annotate findings
+	SARIFLog struct {
+		Version string     `json:"version"`
+		Schema  string     `json:"$schema"`
+		Runs    []SARIFRun `json:"runs"`
+	}
+
+	// SARIFRun is a single run of a tool
+	SARIFRun struct {
+		Tool    SARIFTool     `json:"tool"`
+		Results []SARIFResult `json:"results"`
+	}
+
+	// SARIFTool describes the tool which produced the results
+	SARIFTool struct {
+		Driver SARIFDriver `json:"driver"`
+	}
+
+	// SARIFDriver describes the tool component and the rules it evaluates
+	SARIFDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri,omitempty"`
+		Rules          []SARIFRule `json:"rules"`
+	}
+
+	// SARIFRule describes a rule
+	SARIFRule struct {
+		ID               string       `json:"id"`
+		ShortDescription SARIFMessage `json:"shortDescription"`
+	}
+
+	// SARIFResult is a single finding
+	SARIFResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   SARIFMessage    `json:"message"`
+		Locations []SARIFLocation `json:"locations,omitempty"`
+	}
+
+	// SARIFMessage is a plain text message
+	SARIFMessage struct {
+		Text string `json:"text"`
+	}
+
+	// SARIFLocation is the physical file and logical JSON path of a result
+	SARIFLocation struct {
+		PhysicalLocation *SARIFPhysicalLocation `json:"physicalLocation,omitempty"`
+		LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
+	}
+
+	// SARIFPhysicalLocation is the file containing a result
+	SARIFPhysicalLocation struct {
+		ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	}
+
+	// SARIFArtifactLocation is the URI of a file
+	SARIFArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+
+	// SARIFLogicalLocation is the JSON path of a result within a file
+	SARIFLogicalLocation struct {
+		FullyQualifiedName string `json:"fullyQualifiedName"`
+		Kind               string `json:"kind,omitempty"`
+	}
+)
+
+// SARIF converts the report to a SARIF log. If the report has a Source, it is used as the artifact location of each
+// result.
+func (r *Report) SARIF() *SARIFLog {
+	driver := SARIFDriver{
+		Name:           "pub",
+		InformationURI: "https://github.com/devigned/pub",
+		Rules:          make([]SARIFRule, len(r.rules)),
+	}
+
+	for i, rule := range r.rules {
+		driver.Rules[i] = SARIFRule{
+			ID:               rule.ID,
+			ShortDescription: SARIFMessage{Text: rule.Description},
+		}
+	}
+
+	results := make([]SARIFResult, len(r.Findings))
+	for i, f := range r.Findings {
+		loc := SARIFLocation{
+			LogicalLocations: []SARIFLogicalLocation{
+				{
+					FullyQualifiedName: f.Path,
+					Kind:               "member",
+				},
+			},
+		}
+
+		if r.Source != "" {
+			loc.PhysicalLocation = &SARIFPhysicalLocation{
+				ArtifactLocation: SARIFArtifactLocation{URI: r.Source},
+			}
+		}
+
+		results[i] = SARIFResult{
+			RuleID:    f.RuleID,
+			Level:     string(f.Severity),
+			Message:   SARIFMessage{Text: f.Message},
+			Locations: []SARIFLocation{loc},
+		}
+	}
+
+	return &SARIFLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []SARIFRun{
+			{
+				Tool:    SARIFTool{Driver: driver},
+				Results: results,
+			},
+		},
+	}
+}
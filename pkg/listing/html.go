@@ -0,0 +1,62 @@
+package listing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// AllowedTags are the HTML tags allowed in marketplace descriptions
+	AllowedTags = []string{"a", "b", "br", "em", "h1", "h2", "h3", "h4", "h5", "h6", "i", "li", "ol", "p", "strong", "u", "ul"}
+
+	// allowedAttributes are the attributes allowed on each of the allowed tags
+	allowedAttributes = map[string][]string{
+		"a": {"href", "target", "title"},
+	}
+
+	tagRegex       = regexp.MustCompile(`<\s*(/?)\s*([a-zA-Z][a-zA-Z0-9]*)([^>]*)>`)
+	attributeRegex = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)\s*(?:=\s*("[^"]*"|'[^']*'|[^\s"'>]+))?`)
+)
+
+// ValidateHTML returns an error for the first tag or attribute which is not allowed in a marketplace description
+func ValidateHTML(html string) error {
+	allowed := make(map[string]bool, len(AllowedTags))
+	for _, tag := range AllowedTags {
+		allowed[tag] = true
+	}
+
+	for _, m := range tagRegex.FindAllStringSubmatch(html, -1) {
+		tag := strings.ToLower(m[2])
+		if !allowed[tag] {
+			return fmt.Errorf("tag <%s> is not allowed; allowed tags are %s", tag, strings.Join(AllowedTags, ", "))
+		}
+
+		if m[1] == "/" {
+			continue
+		}
+
+		attrs := strings.TrimSuffix(strings.TrimSpace(m[3]), "/")
+		for _, a := range attributeRegex.FindAllStringSubmatch(attrs, -1) {
+			name := strings.ToLower(a[1])
+			if !contains(allowedAttributes[tag], name) {
+				return fmt.Errorf("attribute %q is not allowed on <%s>", name, tag)
+			}
+
+			value := strings.ToLower(strings.Trim(a[2], `"'`))
+			if name == "href" && !(strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "mailto:")) {
+				return fmt.Errorf("link %q must be an http, https or mailto URL", strings.Trim(a[2], `"'`))
+			}
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
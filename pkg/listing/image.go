@@ -0,0 +1,73 @@
+package listing
+
+import (
+	"fmt"
+	"image"
+	_ "image/png" // register the PNG decoder, since marketplace logos must be PNG images
+	"os"
+)
+
+type (
+	// LogoKind is a marketplace logo size
+	LogoKind string
+
+	// Size is the dimensions of an image in pixels
+	Size struct {
+		Width  int
+		Height int
+	}
+)
+
+const (
+	// SmallLogo is the logo shown in search results
+	SmallLogo LogoKind = "small"
+
+	// MediumLogo is the logo shown when creating a resource
+	MediumLogo LogoKind = "medium"
+
+	// WideLogo is the logo shown on the offer page
+	WideLogo LogoKind = "wide"
+)
+
+var (
+	// LogoSizes are the dimensions required for each marketplace logo
+	LogoSizes = map[LogoKind]Size{
+		SmallLogo:  {Width: 48, Height: 48},
+		MediumLogo: {Width: 90, Height: 90},
+		WideLogo:   {Width: 255, Height: 115},
+	}
+)
+
+func (s Size) String() string {
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+// ValidateLogoFile returns an error if the local file is not a PNG image of the dimensions required for the logo
+func ValidateLogoFile(path string, kind LogoKind) error {
+	want, ok := LogoSizes[kind]
+	if !ok {
+		return fmt.Errorf("unknown logo kind %q", kind)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	config, format, err := image.DecodeConfig(f)
+	if err != nil {
+		return fmt.Errorf("%s logo %s is not a PNG image: %v", kind, path, err)
+	}
+
+	if format != "png" {
+		return fmt.Errorf("%s logo %s is a %s image, but must be a PNG image", kind, path, format)
+	}
+
+	if got := (Size{Width: config.Width, Height: config.Height}); got != want {
+		return fmt.Errorf("%s logo %s is %v pixels, but must be %v pixels", kind, path, got, want)
+	}
+	return nil
+}
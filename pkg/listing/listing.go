@@ -0,0 +1,145 @@
+// Package listing reads, validates and changes the marketplace listing of an offer: the text and media which
+// describe the offer in the marketplace.
+package listing
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"unicode/utf8"
+
+	"github.com/devigned/pub/pkg/lint"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+const (
+	// MaxDescriptionLength is the maximum number of characters in an offer description, including HTML tags
+	MaxDescriptionLength = 3000
+)
+
+type (
+	// Listing is the marketplace listing of an offer
+	Listing struct {
+		Title       string   `json:"title"`
+		Summary     string   `json:"summary"`
+		LongSummary string   `json:"longSummary"`
+		Description string   `json:"description"`
+		SmallLogo   string   `json:"smallLogo"`
+		MediumLogo  string   `json:"mediumLogo"`
+		WideLogo    string   `json:"wideLogo"`
+		Screenshots []string `json:"screenshots"`
+		Videos      []string `json:"videos"`
+	}
+)
+
+// Get returns the listing of an offer. Core VM offers are not supported, since their media is set on each plan.
+func Get(offer *partner.Offer) (*Listing, error) {
+	detail, err := marketplaceDetail(offer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Listing{
+		Title:       detail.Title,
+		Summary:     detail.Summary,
+		LongSummary: detail.LongSummary,
+		Description: detail.Description,
+		SmallLogo:   detail.SmallLogo,
+		MediumLogo:  detail.MediumLogo,
+		WideLogo:    detail.WideLogo,
+		Screenshots: nonNil(detail.ScreenShots),
+		Videos:      nonNil(detail.Videos),
+	}, nil
+}
+
+// Set replaces the listing of an offer
+func Set(offer *partner.Offer, l *Listing) error {
+	detail, err := marketplaceDetail(offer)
+	if err != nil {
+		return err
+	}
+
+	detail.Title = l.Title
+	detail.Summary = l.Summary
+	detail.LongSummary = l.LongSummary
+	detail.Description = l.Description
+	detail.SmallLogo = l.SmallLogo
+	detail.MediumLogo = l.MediumLogo
+	detail.WideLogo = l.WideLogo
+	detail.ScreenShots = l.Screenshots
+	detail.Videos = l.Videos
+	return nil
+}
+
+// Validate returns an error if the listing text is too long, the description has HTML which is not allowed or the
+// media are not https URLs
+func (l *Listing) Validate() error {
+	for _, field := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{name: "title", value: l.Title, max: lint.MaxTitleLength},
+		{name: "summary", value: l.Summary, max: lint.MaxSummaryLength},
+		{name: "long summary", value: l.LongSummary, max: lint.MaxLongSummaryLength},
+		{name: "description", value: l.Description, max: MaxDescriptionLength},
+	} {
+		if n := utf8.RuneCountInString(field.value); n > field.max {
+			return fmt.Errorf("%s is %d characters, but must be at most %d", field.name, n, field.max)
+		}
+	}
+
+	if err := ValidateHTML(l.Description); err != nil {
+		return fmt.Errorf("description: %v", err)
+	}
+
+	media := map[string][]string{
+		"small logo":  {l.SmallLogo},
+		"medium logo": {l.MediumLogo},
+		"wide logo":   {l.WideLogo},
+		"screenshot":  l.Screenshots,
+		"video":       l.Videos,
+	}
+	for _, name := range []string{"small logo", "medium logo", "wide logo", "screenshot", "video"} {
+		for _, u := range media[name] {
+			if err := validateMediaURL(u); err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateMediaURL(mediaURL string) error {
+	if mediaURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(mediaURL)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("URL %q must be an absolute https URL", mediaURL)
+	}
+	return nil
+}
+
+func marketplaceDetail(offer *partner.Offer) (*partner.MarketplaceDetail, error) {
+	if offer.TypeID == partner.CoreVMOfferType {
+		return nil, errors.New("the listing of core VM offers is set on each plan and is not supported")
+	}
+
+	if offer.Definition.OfferDetail == nil {
+		offer.Definition.OfferDetail = new(partner.OfferDetail)
+	}
+	return &offer.Definition.OfferDetail.MarketplaceDetail, nil
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
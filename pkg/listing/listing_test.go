@@ -0,0 +1,99 @@
+package listing_test
+
+import (
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/listing"
+)
+
+func TestValidateHTML(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		`<p>ok <a href="https://contoso.com" target="_blank">link</a><br/></p>`: "",
+		`<p><script>alert(1)</script></p>`:                                      "tag <script> is not allowed",
+		`<p style="color: red">red</p>`:                                         `attribute "style" is not allowed on <p>`,
+		`<a href="javascript:alert(1)">x</a>`:                                   `link "javascript:alert(1)" must be an http, https or mailto URL`,
+	}
+
+	for html, msg := range cases {
+		err := listing.ValidateHTML(html)
+		if msg == "" {
+			assert.NoError(t, err, html)
+			continue
+		}
+		require.Error(t, err, html)
+		assert.Contains(t, err.Error(), msg)
+	}
+}
+
+func TestListing_Validate(t *testing.T) {
+	t.Parallel()
+
+	l := &listing.Listing{
+		Title:       "title",
+		Summary:     "summary",
+		Description: "<p>description</p>",
+		SmallLogo:   "https://contoso.com/small.png",
+	}
+	assert.NoError(t, l.Validate())
+
+	long := *l
+	long.Title = strings.Repeat("t", 51)
+	assert.EqualError(t, long.Validate(), "title is 51 characters, but must be at most 50")
+
+	media := *l
+	media.Screenshots = []string{"http://contoso.com/screenshot.png"}
+	assert.EqualError(t, media.Validate(), `screenshot: URL "http://contoso.com/screenshot.png" must be an absolute https URL`)
+}
+
+func TestGetSet(t *testing.T) {
+	t.Parallel()
+
+	offer := test.NewMarketplaceVMOffer()
+	l, err := listing.Get(offer)
+	require.NoError(t, err)
+	assert.Equal(t, "title", l.Title)
+
+	l.Description = "<p>new</p>"
+	require.NoError(t, listing.Set(offer, l))
+	assert.Equal(t, "<p>new</p>", offer.Definition.OfferDetail.MarketplaceDetail.Description)
+}
+
+func TestGet_CoreVMNotSupported(t *testing.T) {
+	t.Parallel()
+
+	_, err := listing.Get(test.NewMarketplaceCoreVMOffer())
+	assert.Error(t, err)
+}
+
+func TestValidateLogoFile(t *testing.T) {
+	t.Parallel()
+
+	small := newPNGFile(t, 48, 48)
+	defer func() {
+		_ = os.Remove(small)
+	}()
+
+	assert.NoError(t, listing.ValidateLogoFile(small, listing.SmallLogo))
+	assert.EqualError(t, listing.ValidateLogoFile(small, listing.WideLogo), "wide logo "+small+" is 48x48 pixels, but must be 255x115 pixels")
+}
+
+func newPNGFile(t *testing.T, width, height int) string {
+	f, err := ioutil.TempFile("", "logo")
+	require.NoError(t, err)
+	defer func() {
+		_ = f.Close()
+	}()
+	require.NoError(t, png.Encode(f, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return f.Name()
+}
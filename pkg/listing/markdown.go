@@ -0,0 +1,97 @@
+package listing
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+const (
+	// hardBreakSuffix ends a markdown line which is followed by a line break
+	hardBreakSuffix = "  "
+
+	unorderedList    = "ul"
+	orderedList      = "ol"
+	paragraphElement = "p"
+)
+
+var (
+	headingRegex    = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	unorderedRegex  = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedRegex    = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	linkRegex       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	strongRegex     = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	emphasisRegex   = regexp.MustCompile(`(^|[^\w*])[*_]([^*_]+)[*_]`)
+	inlineCodeRegex = regexp.MustCompile("`([^`]+)`")
+)
+
+// MarkdownToHTML converts markdown to the subset of HTML allowed in marketplace descriptions. Headings, paragraphs,
+// ordered and unordered lists, bold, italics and links are converted. Other markdown, like code, is kept as text.
+func MarkdownToHTML(markdown string) string {
+	var (
+		b     strings.Builder
+		open  string
+		lines []string
+	)
+
+	flush := func() {
+		if open == paragraphElement && len(lines) > 0 {
+			b.WriteString("<p>" + strings.Join(lines, "") + "</p>\n")
+		} else if open != "" {
+			b.WriteString("</" + open + ">\n")
+		}
+		open, lines = "", nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+		case headingRegex.MatchString(line):
+			flush()
+			m := headingRegex.FindStringSubmatch(line)
+			b.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n", len(m[1]), inline(m[2]), len(m[1])))
+		case unorderedRegex.MatchString(line):
+			if open != unorderedList {
+				flush()
+				open = unorderedList
+				b.WriteString("<ul>\n")
+			}
+			b.WriteString("<li>" + inline(unorderedRegex.FindStringSubmatch(line)[1]) + "</li>\n")
+		case orderedRegex.MatchString(line):
+			if open != orderedList {
+				flush()
+				open = orderedList
+				b.WriteString("<ol>\n")
+			}
+			b.WriteString("<li>" + inline(orderedRegex.FindStringSubmatch(line)[1]) + "</li>\n")
+		default:
+			if open != paragraphElement {
+				flush()
+				open = paragraphElement
+			}
+
+			text := inline(strings.TrimSpace(line))
+			if strings.HasSuffix(line, hardBreakSuffix) {
+				text += "<br>"
+			} else {
+				text += " "
+			}
+			lines = append(lines, text)
+		}
+	}
+	flush()
+
+	return strings.TrimSpace(strings.ReplaceAll(b.String(), " </p>", "</p>"))
+}
+
+// inline escapes the text and converts inline markdown
+func inline(text string) string {
+	text = html.EscapeString(text)
+	text = inlineCodeRegex.ReplaceAllString(text, "$1")
+	text = linkRegex.ReplaceAllString(text, `<a href="$2">$1</a>`)
+	text = strongRegex.ReplaceAllString(text, "<strong>$2</strong>")
+	text = emphasisRegex.ReplaceAllString(text, "$1<em>$2</em>")
+	return text
+}
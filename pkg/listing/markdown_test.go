@@ -0,0 +1,45 @@
+package listing_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devigned/pub/pkg/listing"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+	t.Parallel()
+
+	markdown := `# Contoso Server
+
+Contoso Server is **fast** and *secure*.
+See the [docs](https://contoso.com/docs?a=1&b=2).
+
+- one
+- two <script>
+
+1. first
+2. second
+`
+	expected := `<h1>Contoso Server</h1>
+<p>Contoso Server is <strong>fast</strong> and <em>secure</em>. See the <a href="https://contoso.com/docs?a=1&amp;b=2">docs</a>.</p>
+<ul>
+<li>one</li>
+<li>two &lt;script&gt;</li>
+</ul>
+<ol>
+<li>first</li>
+<li>second</li>
+</ol>`
+
+	html := listing.MarkdownToHTML(markdown)
+	assert.Equal(t, expected, html)
+	assert.NoError(t, listing.ValidateHTML(html))
+}
+
+func TestMarkdownToHTML_LineBreak(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "<p>one<br>two</p>", listing.MarkdownToHTML("one  \ntwo"))
+}
@@ -0,0 +1,63 @@
+// Package parallel runs independent calls to the Cloud Partner Portal concurrently with a bound on how many are in
+// flight at once, so large publishers can be walked quickly without being throttled.
+package parallel
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultLimit is the number of calls run at once when no limit is given
+const DefaultLimit = 4
+
+// ForEach calls fn for each index from 0 to n-1 with at most limit calls running at once, and returns the error of
+// the first call which fails. Once a call fails, the context of the calls which are still running is cancelled and no
+// more calls are started.
+func ForEach(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) error {
+	if limit < 1 {
+		limit = DefaultLimit
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		sem      = make(chan struct{}, limit)
+	)
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	if firstErr == nil && ctx.Err() != nil {
+		// the parent context was cancelled before all calls were started
+		return ctx.Err()
+	}
+	return firstErr
+}
@@ -0,0 +1,54 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEach_BoundsConcurrency(t *testing.T) {
+	var running, maxRunning, calls int32
+	err := ForEach(context.Background(), 20, 3, func(ctx context.Context, i int) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		atomic.AddInt32(&calls, 1)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(20), calls)
+	assert.True(t, maxRunning <= 3)
+}
+
+func TestForEach_ReturnsFirstError(t *testing.T) {
+	boom := errors.New("boom")
+	err := ForEach(context.Background(), 10, 1, func(ctx context.Context, i int) error {
+		if i == 2 {
+			return boom
+		}
+		return ctx.Err()
+	})
+	assert.Equal(t, boom, err)
+}
+
+func TestForEach_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls int32
+	err := ForEach(ctx, 10, 2, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int32(0), calls)
+}
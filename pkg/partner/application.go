@@ -0,0 +1,17 @@
+package partner
+
+type (
+	// ApplicationPackages is a set of ApplicationPackages keyed by version which marshals to JSON in semantic version
+	// order
+	ApplicationPackages map[string]ApplicationPackage
+)
+
+// Versions returns the versions of the packages in ascending semantic order
+func (packages ApplicationPackages) Versions() ImageVersions {
+	return sortedVersions(packages)
+}
+
+// MarshalJSON writes the packages as a JSON object with keys in ascending semantic version order
+func (packages ApplicationPackages) MarshalJSON() ([]byte, error) {
+	return marshalVersionMap(packages)
+}
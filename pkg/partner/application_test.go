@@ -0,0 +1,88 @@
+package partner_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+const (
+	testApplicationPlanJSON = `
+{
+  "planId": "managed",
+  "microsoft-azure-applications.skuTitle": "skuTitle",
+  "microsoft-azure-applications.solutionType": "ManagedApplication",
+  "microsoft-azure-applications.packages": {
+    "1.0.0": {
+      "packageUrl": "https://account.blob.core.windows.net/packages/app-1.0.0.zip"
+    }
+  },
+  "microsoft-azure-applications.authorizations": [
+    {
+      "principalId": "9f4f6b3e-7c6f-4a8d-9d5c-0b7b0c9a2f10",
+      "roleDefinitionId": "8e3af657-a8ff-443c-a75c-2fe8c4bcb635"
+    }
+  ],
+  "microsoft-azure-applications.allowedCustomerActions": [
+    "Microsoft.Compute/virtualMachines/restart/action"
+  ]
+}`
+)
+
+func TestPlan_Application_JSON(t *testing.T) {
+	t.Parallel()
+
+	var plan partner.Plan
+	require.NoError(t, json.Unmarshal([]byte(testApplicationPlanJSON), &plan))
+
+	assert.Equal(t, partner.Plan{
+		ID: "managed",
+		PlanApplicationDetail: partner.PlanApplicationDetail{
+			SKUTitle:     "skuTitle",
+			SolutionType: partner.ManagedApplicationType,
+			Packages: map[string]partner.ApplicationPackage{
+				"1.0.0": {PackageURI: "https://account.blob.core.windows.net/packages/app-1.0.0.zip"},
+			},
+			Authorizations: []partner.ApplicationAuthorization{
+				{
+					PrincipalID:      "9f4f6b3e-7c6f-4a8d-9d5c-0b7b0c9a2f10",
+					RoleDefinitionID: "8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+				},
+			},
+			AllowedCustomerActions: []string{"Microsoft.Compute/virtualMachines/restart/action"},
+		},
+	}, plan)
+
+	bits, err := json.Marshal(plan)
+	require.NoError(t, err)
+	var roundTripped partner.Plan
+	require.NoError(t, json.Unmarshal(bits, &roundTripped))
+	assert.Equal(t, plan, roundTripped)
+}
+
+func TestApplicationPackages(t *testing.T) {
+	t.Parallel()
+
+	packages := test.NewMarketplaceApplicationOffer().Definition.Plans[0].GetApplicationPackages()
+	packages["1.0.10"] = partner.ApplicationPackage{}
+	assert.Equal(t, partner.ImageVersions{"1.0.0", "1.0.10", "1.1.0"}, packages.Versions())
+
+	next, err := packages.Versions().Next(partner.PatchVersionPart)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.1", next.String())
+
+	bits, err := partner.JSONMarshalWithNoHTMLEscaping(partner.ApplicationPackages{
+		"1.10.0": {},
+		"1.2.0":  {},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"1.2.0\":{},\"1.10.0\":{}}\n", string(bits))
+
+	var plan partner.Plan
+	assert.Nil(t, plan.GetApplicationPackages())
+}
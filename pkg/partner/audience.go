@@ -0,0 +1,30 @@
+package partner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	subscriptionIDRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+)
+
+// ParseSubscriptionIDs validates each ID is a subscription GUID and returns the IDs in lower case with duplicates
+// removed, keeping the order of the first occurrence of each
+func ParseSubscriptionIDs(ids []string) ([]string, error) {
+	seen := make(map[string]bool, len(ids))
+	parsed := make([]string, 0, len(ids))
+	for _, id := range ids {
+		normalized := strings.ToLower(strings.TrimSpace(id))
+		if !subscriptionIDRegex.MatchString(normalized) {
+			return nil, fmt.Errorf("subscription ID %q is not a GUID like 00000000-0000-0000-0000-000000000000", id)
+		}
+
+		if !seen[normalized] {
+			seen[normalized] = true
+			parsed = append(parsed, normalized)
+		}
+	}
+	return parsed, nil
+}
@@ -0,0 +1,78 @@
+package partner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// Markets are the market (region) codes in which marketplace plans can be made available
+	Markets = []string{
+		"AD", "AE", "AF", "AL", "AM", "AO", "AR", "AT", "AU", "AZ", "BA", "BB", "BD", "BE", "BG", "BH",
+		"BM", "BN", "BO", "BR", "BW", "BY", "BZ", "CA", "CH", "CI", "CL", "CM", "CN", "CO", "CR", "CV",
+		"CW", "CY", "CZ", "DE", "DK", "DO", "DZ", "EC", "EE", "EG", "ES", "ET", "FI", "FJ", "FO", "FR",
+		"GB", "GE", "GH", "GR", "GT", "HK", "HN", "HR", "HU", "ID", "IE", "IL", "IN", "IQ", "IS", "IT",
+		"JM", "JO", "JP", "KE", "KG", "KN", "KR", "KW", "KY", "KZ", "LB", "LI", "LK", "LT", "LU", "LV",
+		"LY", "MA", "MC", "MD", "ME", "MK", "MN", "MO", "MT", "MU", "MX", "MY", "NA", "NG", "NI", "NL",
+		"NO", "NP", "NZ", "OM", "PA", "PE", "PH", "PK", "PL", "PR", "PS", "PT", "PY", "QA", "RO", "RS",
+		"RU", "RW", "SA", "SE", "SG", "SI", "SK", "SN", "SV", "TH", "TJ", "TM", "TN", "TR", "TT", "TW",
+		"TZ", "UA", "UG", "US", "UY", "UZ", "VA", "VE", "VI", "VN", "YE", "ZA", "ZM", "ZW",
+	}
+
+	// CloudAvailabilityOptions are the clouds in which a plan can be made available
+	CloudAvailabilityOptions = []CloudAvailabilityOption{PublicOption, GovCloud, ChinaOption, Blackforest}
+)
+
+// ParseMarkets validates each market is a known market code and returns the codes in upper case, sorted and with
+// duplicates removed
+func ParseMarkets(markets []string) ([]string, error) {
+	known := make(map[string]bool, len(Markets))
+	for _, m := range Markets {
+		known[m] = true
+	}
+
+	seen := make(map[string]bool, len(markets))
+	parsed := make([]string, 0, len(markets))
+	for _, market := range markets {
+		code := strings.ToUpper(strings.TrimSpace(market))
+		if !known[code] {
+			return nil, fmt.Errorf("unknown market code %q", market)
+		}
+
+		if !seen[code] {
+			seen[code] = true
+			parsed = append(parsed, code)
+		}
+	}
+	sort.Strings(parsed)
+	return parsed, nil
+}
+
+// ParseCloudAvailability validates each cloud is a known CloudAvailabilityOption, ignoring case, and returns the
+// options with duplicates removed
+func ParseCloudAvailability(clouds []string) ([]CloudAvailabilityOption, error) {
+	seen := make(map[CloudAvailabilityOption]bool, len(clouds))
+	parsed := make([]CloudAvailabilityOption, 0, len(clouds))
+	for _, cloud := range clouds {
+		option, ok := parseCloudAvailabilityOption(strings.TrimSpace(cloud))
+		if !ok {
+			return nil, fmt.Errorf("unknown cloud %q; must be one of %v", cloud, CloudAvailabilityOptions)
+		}
+
+		if !seen[option] {
+			seen[option] = true
+			parsed = append(parsed, option)
+		}
+	}
+	return parsed, nil
+}
+
+func parseCloudAvailabilityOption(cloud string) (CloudAvailabilityOption, bool) {
+	for _, option := range CloudAvailabilityOptions {
+		if strings.EqualFold(string(option), cloud) {
+			return option, true
+		}
+	}
+	return "", false
+}
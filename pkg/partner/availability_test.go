@@ -0,0 +1,32 @@
+package partner_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestParseMarkets(t *testing.T) {
+	t.Parallel()
+
+	markets, err := partner.ParseMarkets([]string{"us", " DE", "US"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DE", "US"}, markets)
+
+	_, err = partner.ParseMarkets([]string{"USA"})
+	assert.EqualError(t, err, `unknown market code "USA"`)
+}
+
+func TestParseCloudAvailability(t *testing.T) {
+	t.Parallel()
+
+	clouds, err := partner.ParseCloudAvailability([]string{"mooncake", "PublicAzure", "Mooncake"})
+	require.NoError(t, err)
+	assert.Equal(t, []partner.CloudAvailabilityOption{partner.ChinaOption, partner.PublicOption}, clouds)
+
+	_, err = partner.ParseCloudAvailability([]string{"Azure"})
+	assert.Error(t, err)
+}
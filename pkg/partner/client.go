@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -24,6 +25,22 @@ const (
 
 	// DefaultHost is the default host name for the Cloud Partner Portal
 	DefaultHost = "https://cloudpartner.azure.com/"
+
+	// PreviewSlot is the slot containing the offer version currently in preview
+	PreviewSlot = "Preview"
+
+	// ProductionSlot is the slot containing the offer version currently in production
+	ProductionSlot = "Production"
+
+	// DraftSlot is the slot containing the draft of the offer, which is what GetOffer fetches
+	DraftSlot = "Draft"
+)
+
+var (
+	// ErrOfferChanged is returned when an offer is put with an Etag, but the offer has changed since it was fetched
+	ErrOfferChanged = errors.New("offer has changed since it was fetched; fetch the offer and try again")
+	// ErrOfferNotFound is returned when an offer, or a slot of an offer, which does not exist is fetched
+	ErrOfferNotFound = errors.New("offer was not found")
 )
 
 type (
@@ -33,7 +50,16 @@ type (
 		Authorizer autorest.Authorizer
 		APIVersion string
 		Host       string
-		mwStack    []MiddlewareFunc
+		// Cache, if set, is used to revalidate offers with If-None-Match, and is invalidated when an offer is changed
+		Cache   OfferCache
+		mwStack []MiddlewareFunc
+	}
+
+	// OfferCache stores the body and Etag of fetched offers by publisher, offer and slot
+	OfferCache interface {
+		Get(publisherID, offerID, slot string) (etag string, body []byte, ok bool)
+		Put(publisherID, offerID, slot, etag string, body []byte) error
+		Invalidate(publisherID, offerID string) error
 	}
 
 	// ClientOption is a variadic optional configuration func
@@ -187,6 +213,14 @@ func New(apiVersion string, opts ...ClientOption) (*Client, error) {
 	return c, nil
 }
 
+// WithOfferCache configures the client to revalidate the offers it fetches with the cache
+func WithOfferCache(cache OfferCache) ClientOption {
+	return func(c *Client) error {
+		c.Cache = cache
+		return nil
+	}
+}
+
 // GetOperationByURI will fetch an operation given the path to the operation
 func (c *Client) GetOperationByURI(ctx context.Context, operationURI string) (*OperationDetail, error) {
 	res, err := c.execute(ctx, http.MethodGet, operationURI, nil)
@@ -215,6 +249,7 @@ func (c *Client) GetOperationByURI(ctx context.Context, operationURI string) (*O
 
 // CancelOperation cancels the currently active operation on an offer
 func (c *Client) CancelOperation(ctx context.Context, params CancelOperationParams) (string, error) {
+	defer c.invalidate(ctx, params.PublisherID, params.OfferID)
 	path := fmt.Sprintf("api/publishers/%s/offers/%s/cancel?api-version=%s", params.PublisherID, params.OfferID, c.APIVersion)
 	res, err := c.execute(ctx, http.MethodPost, path, nil)
 	defer closeResponse(ctx, res)
@@ -296,6 +331,7 @@ func (c *Client) ListOperations(ctx context.Context, params ListOperationsParams
 
 // GoLiveWithOffer opens a published offer to the world, not just the preview subscriptions
 func (c *Client) GoLiveWithOffer(ctx context.Context, params GoLiveParams) (string, error) {
+	defer c.invalidate(ctx, params.PublisherID, params.OfferID)
 	path := fmt.Sprintf("api/publishers/%s/offers/%s/golive?api-version=%s", params.PublisherID, params.OfferID, c.APIVersion)
 	bodyJSON, err := JSONMarshalWithNoHTMLEscaping(Publish{
 		Metadata: PublishMetadata{
@@ -326,6 +362,7 @@ func (c *Client) GoLiveWithOffer(ctx context.Context, params GoLiveParams) (stri
 // PublishOffer starts the publish process for the offer. This is a long running operation, so the method returns a
 // uri which can be used to query the status of the operation.
 func (c *Client) PublishOffer(ctx context.Context, publishParams PublishOfferParams) (string, error) {
+	defer c.invalidate(ctx, publishParams.PublisherID, publishParams.OfferID)
 	pubJSON, err := JSONMarshalWithNoHTMLEscaping(Publish{
 		Metadata: PublishMetadata{
 			NotificationEmails: publishParams.NotificationEmails,
@@ -359,13 +396,27 @@ func (c *Client) PublishOffer(ctx context.Context, publishParams PublishOfferPar
 
 // PutOffer will PUT an offer to the API and return the offer
 func (c *Client) PutOffer(ctx context.Context, offer *Offer) (*Offer, error) {
+	return c.putOffer(ctx, offer, MatchesAll())
+}
+
+// PutOfferIfMatch will create or update an offer only if it has not changed since it was fetched, which is checked
+// with the offer's Etag. If the offer has changed, ErrOfferChanged is returned.
+func (c *Client) PutOfferIfMatch(ctx context.Context, offer *Offer) (*Offer, error) {
+	if offer.Etag == "" {
+		return nil, errors.New("offer has no Etag; fetch the offer before putting it")
+	}
+	return c.putOffer(ctx, offer, IfMatches(offer.Etag))
+}
+
+func (c *Client) putOffer(ctx context.Context, offer *Offer, match MiddlewareFunc) (*Offer, error) {
+	defer c.invalidate(ctx, offer.PublisherID, offer.ID)
 	offerJSON, err := JSONMarshalWithNoHTMLEscaping(offer)
 	if err != nil {
 		return nil, err
 	}
 
 	path := fmt.Sprintf("api/publishers/%s/offers/%s?api-version=%s", offer.PublisherID, offer.ID, c.APIVersion)
-	res, err := c.execute(ctx, http.MethodPut, path, bytes.NewReader(offerJSON), MatchesAll())
+	res, err := c.execute(ctx, http.MethodPut, path, bytes.NewReader(offerJSON), match)
 	defer closeResponse(ctx, res)
 
 	if err != nil {
@@ -377,6 +428,10 @@ func (c *Client) PutOffer(ctx context.Context, offer *Offer) (*Offer, error) {
 		return nil, err
 	}
 
+	if res.StatusCode == http.StatusPreconditionFailed {
+		return nil, ErrOfferChanged
+	}
+
 	if res.StatusCode > 299 {
 		return nil, fmt.Errorf(fmt.Sprintf("uri: %s, status: %d, body: %s", res.Request.URL, res.StatusCode, body))
 	}
@@ -392,22 +447,11 @@ func (c *Client) PutOffer(ctx context.Context, offer *Offer) (*Offer, error) {
 // GetOfferBySlot will get an offer by publisher and offer ID and version
 func (c *Client) GetOfferBySlot(ctx context.Context, params ShowOfferBySlotParams) (*Offer, error) {
 	path := fmt.Sprintf("api/publishers/%s/offers/%s/slot/%s?api-version=%s", params.PublisherID, params.OfferID, params.SlotID, c.APIVersion)
-	res, err := c.execute(ctx, http.MethodGet, path, nil)
-	defer closeResponse(ctx, res)
-
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := ioutil.ReadAll(res.Body)
+	body, _, err := c.getOffer(ctx, path, params.PublisherID, params.OfferID, params.SlotID)
 	if err != nil {
 		return nil, err
 	}
 
-	if res.StatusCode > 299 {
-		return nil, fmt.Errorf(fmt.Sprintf("uri: %s, status: %d, body: %s", res.Request.URL, res.StatusCode, body))
-	}
-
 	var offer Offer
 	if err := json.Unmarshal(body, &offer); err != nil {
 		return nil, err
@@ -446,32 +490,81 @@ func (c *Client) GetOfferByVersion(ctx context.Context, params ShowOfferByVersio
 // GetOffer will get an offer by publisher and offer ID
 func (c *Client) GetOffer(ctx context.Context, params ShowOfferParams) (*Offer, error) {
 	path := fmt.Sprintf("api/publishers/%s/offers/%s?api-version=%s", params.PublisherID, params.OfferID, c.APIVersion)
-	res, err := c.execute(ctx, http.MethodGet, path, nil)
+	body, etag, err := c.getOffer(ctx, path, params.PublisherID, params.OfferID, DraftSlot)
+	if err != nil {
+		return nil, err
+	}
+
+	var offer Offer
+	if err := json.Unmarshal(body, &offer); err != nil {
+		return nil, err
+	}
+
+	if etag != "" {
+		offer.Etag = etag
+	}
+
+	return &offer, nil
+}
+
+// getOffer fetches the body and Etag of an offer in a slot. If the offer is in the cache, it is revalidated with
+// If-None-Match, and the cached body is returned when the Cloud Partner Portal responds that it is not modified.
+func (c *Client) getOffer(ctx context.Context, path, publisherID, offerID, slot string) ([]byte, string, error) {
+	var cachedEtag string
+	var cachedBody []byte
+	var cached bool
+	if c.Cache != nil {
+		cachedEtag, cachedBody, cached = c.Cache.Get(publisherID, offerID, slot)
+	}
+
+	var match MiddlewareFunc
+	if cached {
+		match = IfNoneMatches(cachedEtag)
+	}
+
+	res, err := c.execute(ctx, http.MethodGet, path, nil, match)
 	defer closeResponse(ctx, res)
 
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	if cached && res.StatusCode == http.StatusNotModified {
+		return cachedBody, cachedEtag, nil
 	}
 
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, "", ErrOfferNotFound
 	}
 
 	if res.StatusCode > 299 {
-		return nil, fmt.Errorf(fmt.Sprintf("uri: %s, status: %d, body: %s", res.Request.URL, res.StatusCode, body))
+		return nil, "", fmt.Errorf(fmt.Sprintf("uri: %s, status: %d, body: %s", res.Request.URL, res.StatusCode, body))
 	}
 
-	var offer Offer
-	if err := json.Unmarshal(body, &offer); err != nil {
-		return nil, err
+	etag := res.Header.Get("Etag")
+	if c.Cache != nil && etag != "" {
+		if err := c.Cache.Put(publisherID, offerID, slot, etag, body); err != nil {
+			tab.For(ctx).Error(err)
+		}
 	}
 
-	if etag, ok := res.Header["Etag"]; ok {
-		offer.Etag = etag[0]
+	return body, etag, nil
+}
+
+// invalidate removes an offer from the cache, if there is one, after the offer has been changed
+func (c *Client) invalidate(ctx context.Context, publisherID, offerID string) {
+	if c.Cache == nil {
+		return
 	}
 
-	return &offer, nil
+	if err := c.Cache.Invalidate(publisherID, offerID); err != nil {
+		tab.For(ctx).Error(err)
+	}
 }
 
 // GetOfferStatus gets the status of a given offer
@@ -646,6 +739,20 @@ func IfMatches(etag string) MiddlewareFunc {
 	}
 }
 
+// IfNoneMatches adds an If-None-Match header with the Etag to the request, so the response is 304 Not Modified if the
+// entity has not changed
+func IfNoneMatches(etag string) MiddlewareFunc {
+	return func(next RestHandler) RestHandler {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			if etag != "" {
+				req.Header.Add("If-None-Match", etag)
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
+
 // MatchesAll adds an If-Match=* header to the request.
 // More details on why can be found in https://github.com/devigned/pub/issues/22
 func MatchesAll() MiddlewareFunc {
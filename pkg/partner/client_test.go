@@ -1,9 +1,15 @@
 package partner
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNew(t *testing.T) {
@@ -14,5 +20,146 @@ func TestNew(t *testing.T) {
 }
 
 func TestClient_GetOffer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/publishers/publisher/offers/offer" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Etag", "etag")
+		_, _ = w.Write([]byte(`{"id": "offer", "publisherId": "publisher"}`))
+	}))
+	defer srv.Close()
 
+	client := &Client{
+		HTTPClient: srv.Client(),
+		Authorizer: autorest.NullAuthorizer{},
+		APIVersion: "version",
+		Host:       srv.URL + "/",
+	}
+
+	offer, err := client.GetOffer(context.Background(), ShowOfferParams{PublisherID: "publisher", OfferID: "offer"})
+	require.NoError(t, err)
+	assert.Equal(t, "offer", offer.ID)
+	assert.Equal(t, "etag", offer.Etag)
+
+	_, err = client.GetOffer(context.Background(), ShowOfferParams{PublisherID: "publisher", OfferID: "missing"})
+	assert.Equal(t, ErrOfferNotFound, err)
+}
+
+func TestClient_GetOfferBySlot_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := &Client{
+		HTTPClient: srv.Client(),
+		Authorizer: autorest.NullAuthorizer{},
+		APIVersion: "version",
+		Host:       srv.URL + "/",
+	}
+
+	_, err := client.GetOfferBySlot(context.Background(), ShowOfferBySlotParams{PublisherID: "publisher", OfferID: "offer", SlotID: ProductionSlot})
+	assert.Equal(t, ErrOfferNotFound, err)
+}
+
+func TestClient_PutOfferIfMatch(t *testing.T) {
+	var ifMatch string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifMatch = r.Header.Get("If-Match")
+		if ifMatch != "etag" {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "offer", "publisherId": "publisher"}`))
+	}))
+	defer srv.Close()
+
+	client := &Client{
+		HTTPClient: srv.Client(),
+		Authorizer: autorest.NullAuthorizer{},
+		APIVersion: "version",
+		Host:       srv.URL + "/",
+	}
+
+	offer := &Offer{Entity: Entity{ID: "offer"}, PublisherID: "publisher", Etag: "etag"}
+	updated, err := client.PutOfferIfMatch(context.Background(), offer)
+	require.NoError(t, err)
+	assert.Equal(t, "etag", ifMatch)
+	assert.Equal(t, "offer", updated.ID)
+
+	offer.Etag = "stale"
+	_, err = client.PutOfferIfMatch(context.Background(), offer)
+	assert.Equal(t, ErrOfferChanged, err)
+
+	offer.Etag = ""
+	_, err = client.PutOfferIfMatch(context.Background(), offer)
+	assert.Error(t, err)
+}
+
+type memCache map[string][2]string
+
+func (m memCache) Get(publisherID, offerID, slot string) (string, []byte, bool) {
+	e, ok := m[publisherID+"/"+offerID+"/"+slot]
+	return e[0], []byte(e[1]), ok
+}
+
+func (m memCache) Put(publisherID, offerID, slot, etag string, body []byte) error {
+	m[publisherID+"/"+offerID+"/"+slot] = [2]string{etag, string(body)}
+	return nil
+}
+
+func (m memCache) Invalidate(publisherID, offerID string) error {
+	for key := range m {
+		if strings.HasPrefix(key, publisherID+"/"+offerID+"/") {
+			delete(m, key)
+		}
+	}
+	return nil
+}
+
+func TestClient_GetOfferWithCache(t *testing.T) {
+	var ifNoneMatch []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			_, _ = w.Write([]byte(`{"id": "offer", "publisherId": "publisher"}`))
+			return
+		}
+
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == "etag" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Etag", "etag")
+		_, _ = w.Write([]byte(`{"id": "offer", "publisherId": "publisher", "definition": {"displayText": "text"}}`))
+	}))
+	defer srv.Close()
+
+	cache := memCache{}
+	client := &Client{
+		HTTPClient: srv.Client(),
+		Authorizer: autorest.NullAuthorizer{},
+		APIVersion: "version",
+		Host:       srv.URL + "/",
+		Cache:      cache,
+	}
+
+	params := ShowOfferParams{PublisherID: "publisher", OfferID: "offer"}
+	for i := 0; i < 2; i++ {
+		offer, err := client.GetOffer(context.Background(), params)
+		require.NoError(t, err)
+		assert.Equal(t, "text", offer.Definition.DisplayText)
+		assert.Equal(t, "etag", offer.Etag)
+	}
+	assert.Equal(t, []string{"", "etag"}, ifNoneMatch)
+	assert.Contains(t, cache, "publisher/offer/"+DraftSlot)
+
+	_, err := client.PutOffer(context.Background(), &Offer{Entity: Entity{ID: "offer"}, PublisherID: "publisher"})
+	require.NoError(t, err)
+	assert.Empty(t, cache, "putting an offer invalidates it")
+
+	_, err = client.GetOffer(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "etag", ""}, ifNoneMatch)
 }
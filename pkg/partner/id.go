@@ -0,0 +1,15 @@
+package partner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CheckPathID returns an error if the ID cannot be used as a single path element, for example the name of the
+// directory of a publisher or an offer
+func CheckPathID(id string) error {
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("%q is not a valid ID; IDs cannot be empty or contain path separators", id)
+	}
+	return nil
+}
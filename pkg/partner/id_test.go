@@ -0,0 +1,36 @@
+package partner_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestCheckPathID(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{
+		"offer":          true,
+		"offer.v2":       true,
+		"":               false,
+		".":              false,
+		"..":             false,
+		"publisher/../x": false,
+		`offer\x`:        false,
+	}
+
+	for id, valid := range cases {
+		id, valid := id, valid
+		t.Run(id, func(t *testing.T) {
+			t.Parallel()
+			err := partner.CheckPathID(id)
+			if valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package partner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// ImageVersion is a VM image version in the Major.Minor.Patch format required by the Cloud Partner Portal
+	ImageVersion struct {
+		Major int
+		Minor int
+		Patch int
+	}
+
+	// ImageVersionPart identifies the part of an ImageVersion to increment
+	ImageVersionPart string
+
+	// ImageVersions is the version keys of VirtualMachineImages or ApplicationPackages in ascending semantic order
+	ImageVersions []string
+
+	// VirtualMachineImages is a set of VirtualMachineImages keyed by version which marshals to JSON in semantic
+	// version order
+	VirtualMachineImages map[string]VirtualMachineImage
+)
+
+var (
+	// MajorVersionPart increments the major version and resets the minor and patch versions
+	MajorVersionPart ImageVersionPart = "major"
+
+	// MinorVersionPart increments the minor version and resets the patch version
+	MinorVersionPart ImageVersionPart = "minor"
+
+	// PatchVersionPart increments the patch version
+	PatchVersionPart ImageVersionPart = "patch"
+)
+
+// ParseImageVersion parses a Major.Minor.Patch version string. Each part must be a non-negative integer which fits
+// within a 32-bit signed integer.
+func ParseImageVersion(version string) (ImageVersion, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return ImageVersion{}, fmt.Errorf("version %q must be in the Major.Minor.Patch format; for example, 1.0.0", version)
+	}
+
+	var nums [3]int
+	for i, part := range parts {
+		if part == "" || strings.TrimLeft(part, "0123456789") != "" {
+			return ImageVersion{}, fmt.Errorf("version %q must only contain non-negative integers separated by '.'", version)
+		}
+
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n > math.MaxInt32 {
+			return ImageVersion{}, fmt.Errorf("version %q has a part which is larger than %d", version, math.MaxInt32)
+		}
+		nums[i] = int(n)
+	}
+
+	return ImageVersion{
+		Major: nums[0],
+		Minor: nums[1],
+		Patch: nums[2],
+	}, nil
+}
+
+// String returns the Major.Minor.Patch representation of the version
+func (v ImageVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1 if v is less than other, 1 if v is greater than other and 0 if they are equal
+func (v ImageVersion) Compare(other ImageVersion) int {
+	switch {
+	case v.Major != other.Major:
+		return compareInts(v.Major, other.Major)
+	case v.Minor != other.Minor:
+		return compareInts(v.Minor, other.Minor)
+	default:
+		return compareInts(v.Patch, other.Patch)
+	}
+}
+
+// Next returns the version which follows v after incrementing the given part
+func (v ImageVersion) Next(part ImageVersionPart) (ImageVersion, error) {
+	switch part {
+	case MajorVersionPart:
+		return ImageVersion{Major: v.Major + 1}, nil
+	case MinorVersionPart:
+		return ImageVersion{Major: v.Major, Minor: v.Minor + 1}, nil
+	case PatchVersionPart:
+		return ImageVersion{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}, nil
+	default:
+		return v, fmt.Errorf("unknown version part %q; must be one of: %s, %s, %s", part, MajorVersionPart, MinorVersionPart, PatchVersionPart)
+	}
+}
+
+// SortImageVersions sorts version strings in ascending semantic order. Versions which are not in the
+// Major.Minor.Patch format are sorted lexically after all valid versions.
+func SortImageVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, errI := ParseImageVersion(versions[i])
+		vj, errJ := ParseImageVersion(versions[j])
+		switch {
+		case errI == nil && errJ == nil:
+			if c := vi.Compare(vj); c != 0 {
+				return c < 0
+			}
+			return versions[i] < versions[j]
+		case errI == nil:
+			return true
+		case errJ == nil:
+			return false
+		default:
+			return versions[i] < versions[j]
+		}
+	})
+}
+
+// Versions returns the versions of the images in ascending semantic order
+func (images VirtualMachineImages) Versions() ImageVersions {
+	return sortedVersions(images)
+}
+
+// MarshalJSON writes the images as a JSON object with keys in ascending semantic version order
+func (images VirtualMachineImages) MarshalJSON() ([]byte, error) {
+	return marshalVersionMap(images)
+}
+
+// Latest returns the greatest valid version. If there are no valid versions, ok will be false.
+func (versions ImageVersions) Latest() (version ImageVersion, ok bool) {
+	for _, key := range versions {
+		v, err := ParseImageVersion(key)
+		if err != nil {
+			continue
+		}
+
+		if !ok || v.Compare(version) > 0 {
+			version = v
+			ok = true
+		}
+	}
+	return version, ok
+}
+
+// Next returns the version which follows the latest valid version after incrementing the given part. If there are
+// no valid versions, the part is incremented from 0.0.0.
+func (versions ImageVersions) Next(part ImageVersionPart) (ImageVersion, error) {
+	latest, _ := versions.Latest()
+	return latest.Next(part)
+}
+
+// sortedVersions returns the keys of a map keyed by version, such as VirtualMachineImages, in ascending semantic
+// order
+func sortedVersions(m interface{}) ImageVersions {
+	keys := reflect.ValueOf(m).MapKeys()
+	versions := make(ImageVersions, len(keys))
+	for i, key := range keys {
+		versions[i] = key.String()
+	}
+	SortImageVersions(versions)
+	return versions
+}
+
+// marshalVersionMap writes a map keyed by version, such as VirtualMachineImages, as a JSON object with keys in
+// ascending semantic version order
+func marshalVersionMap(m interface{}) ([]byte, error) {
+	v := reflect.ValueOf(m)
+	if v.IsNil() {
+		return []byte("null"), nil
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, version := range sortedVersions(m) {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(version)
+		if err != nil {
+			return nil, err
+		}
+
+		bits, err := JSONMarshalWithNoHTMLEscaping(v.MapIndex(reflect.ValueOf(version)).Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(bytes.TrimSpace(bits))
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
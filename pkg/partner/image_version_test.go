@@ -0,0 +1,109 @@
+package partner_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestParseImageVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Input    string
+		Expected partner.ImageVersion
+		Err      bool
+	}{
+		{Input: "1.0.0", Expected: partner.ImageVersion{Major: 1}},
+		{Input: "2018.11.05", Expected: partner.ImageVersion{Major: 2018, Minor: 11, Patch: 5}},
+		{Input: "2147483647.0.0", Expected: partner.ImageVersion{Major: 2147483647}},
+		{Input: "2147483648.0.0", Err: true},
+		{Input: "1234", Err: true},
+		{Input: "1.0", Err: true},
+		{Input: "1.0.0.0", Err: true},
+		{Input: "1..0", Err: true},
+		{Input: "v1.0.0", Err: true},
+		{Input: "1.-1.0", Err: true},
+	}
+
+	for _, c := range cases {
+		actual, err := partner.ParseImageVersion(c.Input)
+		if c.Err {
+			assert.Error(t, err, c.Input)
+			continue
+		}
+		assert.NoError(t, err, c.Input)
+		assert.Equal(t, c.Expected, actual, c.Input)
+	}
+}
+
+func TestImageVersion_Next(t *testing.T) {
+	t.Parallel()
+
+	v := partner.ImageVersion{Major: 1, Minor: 2, Patch: 3}
+	for part, expected := range map[partner.ImageVersionPart]string{
+		partner.MajorVersionPart: "2.0.0",
+		partner.MinorVersionPart: "1.3.0",
+		partner.PatchVersionPart: "1.2.4",
+	} {
+		next, err := v.Next(part)
+		require.NoError(t, err)
+		assert.Equal(t, expected, next.String())
+	}
+
+	_, err := v.Next("build")
+	assert.Error(t, err)
+}
+
+func TestVirtualMachineImages_Versions(t *testing.T) {
+	t.Parallel()
+
+	images := partner.VirtualMachineImages{
+		"1.10.0":  {},
+		"1.9.0":   {},
+		"latest":  {},
+		"10.0.0":  {},
+		"1.9.10":  {},
+		"2.0.0":   {},
+		"1.09.01": {},
+	}
+	assert.Equal(t, partner.ImageVersions{"1.9.0", "1.09.01", "1.9.10", "1.10.0", "2.0.0", "10.0.0", "latest"}, images.Versions())
+}
+
+func TestVirtualMachineImages_Next(t *testing.T) {
+	t.Parallel()
+
+	images := partner.VirtualMachineImages{
+		"2018.1.1":   {},
+		"2019.10.11": {},
+		"2019.9.12":  {},
+		"invalid":    {},
+	}
+	next, err := images.Versions().Next(partner.PatchVersionPart)
+	require.NoError(t, err)
+	assert.Equal(t, "2019.10.12", next.String())
+
+	next, err = partner.VirtualMachineImages(nil).Versions().Next(partner.MinorVersionPart)
+	require.NoError(t, err)
+	assert.Equal(t, "0.1.0", next.String())
+}
+
+func TestVirtualMachineImages_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	images := partner.VirtualMachineImages{
+		"1.10.0": {OSVHDURL: "https://foo/bar.vhd?a=1&b=2"},
+		"1.2.0":  {Label: "<label>"},
+	}
+	bits, err := partner.JSONMarshalWithNoHTMLEscaping(images)
+	require.NoError(t, err)
+	assert.Equal(t, `{"1.2.0":{"label":"<label>"},"1.10.0":{"osVhdUrl":"https://foo/bar.vhd?a=1&b=2"}}`+"\n", string(bits))
+
+	var roundTrip partner.VirtualMachineImages
+	require.NoError(t, json.Unmarshal(bits, &roundTrip))
+	assert.Equal(t, images, roundTrip)
+}
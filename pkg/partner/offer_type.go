@@ -0,0 +1,355 @@
+package partner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type (
+	// OfferType provides uniform access to the details of an offer which each offer type stores in different fields
+	OfferType interface {
+		// ID returns the offer type ID, for example microsoft-azure-virtualmachines
+		ID() string
+
+		// Title returns the title of the offer listing
+		Title(offer *Offer) string
+		// SetTitle sets the title of the offer listing
+		SetTitle(offer *Offer, title string)
+		// Summary returns the summary of the offer listing
+		Summary(offer *Offer) string
+		// SetSummary sets the summary of the offer listing
+		SetSummary(offer *Offer, summary string)
+		// Description returns the description of the offer listing
+		Description(offer *Offer) string
+		// SetDescription sets the description of the offer listing
+		SetDescription(offer *Offer, description string)
+		// AllowedSubscriptions returns the IDs of the subscriptions which can see the offer in preview
+		AllowedSubscriptions(offer *Offer) []string
+		// SetAllowedSubscriptions sets the IDs of the subscriptions which can see the offer in preview
+		SetAllowedSubscriptions(offer *Offer, subscriptionIDs []string)
+
+		// SupportsCloudAvailability returns true if the plans of the offer type can be made available in other clouds
+		SupportsCloudAvailability() bool
+		// CloudAvailability returns the clouds in which the plan is available
+		CloudAvailability(plan *Plan) ([]CloudAvailabilityOption, error)
+		// SetCloudAvailability sets the clouds in which the plan is available
+		SetCloudAvailability(plan *Plan, clouds []CloudAvailabilityOption) error
+		// MissingCloudDetails returns the names of the per cloud plan fields, like the description and useful links,
+		// which must be set before the plan is available in the cloud
+		MissingCloudDetails(plan *Plan, cloud CloudAvailabilityOption) []string
+
+		// SupportsImages returns true if the plans of the offer type have VM image versions
+		SupportsImages() bool
+		// ImageVersions returns the image versions of the plan
+		ImageVersions(plan *Plan) (VirtualMachineImages, error)
+		// GetImageVersion returns the image version of the plan if it exists
+		GetImageVersion(plan *Plan, version string) (VirtualMachineImage, bool, error)
+		// SetImageVersion adds or replaces an image version of the plan
+		SetImageVersion(plan *Plan, version string, image VirtualMachineImage) error
+		// DeleteImageVersion removes an image version from the plan
+		DeleteImageVersion(plan *Plan, version string) error
+	}
+
+	// UnsupportedOfferTypeError is returned for offer type IDs which are not known
+	UnsupportedOfferTypeError struct {
+		TypeID string
+	}
+
+	// CloudAvailabilityNotSupportedError is returned when accessing the cloud availability of an offer type which is
+	// only available in the public cloud
+	CloudAvailabilityNotSupportedError struct {
+		TypeID string
+	}
+
+	// ImagesNotSupportedError is returned when accessing the image versions of an offer type which has no images
+	ImagesNotSupportedError struct {
+		TypeID string
+	}
+
+	// offerType implements OfferType with accessors for the fields used by the offer type
+	offerType struct {
+		id string
+		// listing returns pointers to the title, summary and description fields of the offer detail
+		listing func(detail *OfferDetail) (title, summary, description *string)
+		// audience returns a pointer to the allowed subscriptions field of the offer detail
+		audience func(detail *OfferDetail) *[]string
+		// clouds gets and sets the cloud availability of the plan or is nil if the type is only in the public cloud
+		clouds *cloudAvailability
+		// cloudDetails returns the per cloud plan fields by name which must be set for the plan to be in the cloud
+		cloudDetails func(plan *Plan, cloud CloudAvailabilityOption) map[string]bool
+		// images returns a pointer to the image versions field of the plan or nil if the type has no images
+		images func(plan *Plan) *map[string]VirtualMachineImage
+	}
+
+	// cloudAvailability gets and sets the cloud availability field of a plan, which is a different type for some
+	// offer types
+	cloudAvailability struct {
+		get func(plan *Plan) []CloudAvailabilityOption
+		set func(plan *Plan, clouds []CloudAvailabilityOption)
+	}
+)
+
+var (
+	offerTypes = map[string]offerType{
+		VirtualMachineOfferType: {
+			id:       VirtualMachineOfferType,
+			listing:  marketplaceListing,
+			audience: marketplaceAudience,
+			clouds: stringCloudAvailability(func(plan *Plan) *[]string {
+				return &plan.PlanVirtualMachineDetail.CloudAvailability
+			}),
+			images: func(plan *Plan) *map[string]VirtualMachineImage {
+				return &plan.PlanVirtualMachineDetail.VMImages
+			},
+		},
+		CoreVMOfferType: {
+			id: CoreVMOfferType,
+			listing: func(detail *OfferDetail) (*string, *string, *string) {
+				return &detail.CoreVMOfferDetail.Title, &detail.CoreVMOfferDetail.Summary, &detail.CoreVMOfferDetail.Description
+			},
+			audience: func(detail *OfferDetail) *[]string {
+				return &detail.CoreVMOfferDetail.AllowedSubscriptions
+			},
+			clouds: &cloudAvailability{
+				get: func(plan *Plan) []CloudAvailabilityOption {
+					return plan.PlanCoreVMDetail.CloudAvailability
+				},
+				set: func(plan *Plan, clouds []CloudAvailabilityOption) {
+					plan.PlanCoreVMDetail.CloudAvailability = clouds
+				},
+			},
+			cloudDetails: coreVMCloudDetails,
+			images: func(plan *Plan) *map[string]VirtualMachineImage {
+				return &plan.PlanCoreVMDetail.VMImages
+			},
+		},
+		ApplicationOfferType: {
+			id:       ApplicationOfferType,
+			listing:  marketplaceListing,
+			audience: marketplaceAudience,
+			clouds: stringCloudAvailability(func(plan *Plan) *[]string {
+				return &plan.PlanApplicationDetail.CloudAvailability
+			}),
+		},
+		ContainerOfferType: {
+			id:       ContainerOfferType,
+			listing:  marketplaceListing,
+			audience: marketplaceAudience,
+		},
+		SaaSOfferType: {
+			id:       SaaSOfferType,
+			listing:  marketplaceListing,
+			audience: marketplaceAudience,
+		},
+	}
+)
+
+// GetOfferType returns the OfferType for an offer type ID
+func GetOfferType(typeID string) (OfferType, error) {
+	if t, ok := offerTypes[typeID]; ok {
+		return t, nil
+	}
+	return nil, &UnsupportedOfferTypeError{TypeID: typeID}
+}
+
+// OfferType returns the OfferType for the offer's TypeID
+func (o *Offer) OfferType() (OfferType, error) {
+	return GetOfferType(o.TypeID)
+}
+
+func (e *UnsupportedOfferTypeError) Error() string {
+	ids := make([]string, 0, len(offerTypes))
+	for id := range offerTypes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return fmt.Sprintf("unsupported offer type %q; must be one of: %s", e.TypeID, strings.Join(ids, ", "))
+}
+
+func (e *CloudAvailabilityNotSupportedError) Error() string {
+	return fmt.Sprintf("offer type %s does not support cloud availability", e.TypeID)
+}
+
+func (e *ImagesNotSupportedError) Error() string {
+	return fmt.Sprintf("offer type %s does not support image versions", e.TypeID)
+}
+
+func (t offerType) ID() string {
+	return t.id
+}
+
+func (t offerType) Title(offer *Offer) string {
+	title, _, _ := t.listing(offerDetail(offer))
+	return *title
+}
+
+func (t offerType) SetTitle(offer *Offer, value string) {
+	title, _, _ := t.listing(offerDetail(offer))
+	*title = value
+}
+
+func (t offerType) Summary(offer *Offer) string {
+	_, summary, _ := t.listing(offerDetail(offer))
+	return *summary
+}
+
+func (t offerType) SetSummary(offer *Offer, value string) {
+	_, summary, _ := t.listing(offerDetail(offer))
+	*summary = value
+}
+
+func (t offerType) Description(offer *Offer) string {
+	_, _, description := t.listing(offerDetail(offer))
+	return *description
+}
+
+func (t offerType) SetDescription(offer *Offer, value string) {
+	_, _, description := t.listing(offerDetail(offer))
+	*description = value
+}
+
+func (t offerType) AllowedSubscriptions(offer *Offer) []string {
+	return *t.audience(offerDetail(offer))
+}
+
+func (t offerType) SetAllowedSubscriptions(offer *Offer, subscriptionIDs []string) {
+	*t.audience(offerDetail(offer)) = subscriptionIDs
+}
+
+func (t offerType) SupportsCloudAvailability() bool {
+	return t.clouds != nil
+}
+
+func (t offerType) CloudAvailability(plan *Plan) ([]CloudAvailabilityOption, error) {
+	if !t.SupportsCloudAvailability() {
+		return nil, &CloudAvailabilityNotSupportedError{TypeID: t.id}
+	}
+	return t.clouds.get(plan), nil
+}
+
+func (t offerType) SetCloudAvailability(plan *Plan, clouds []CloudAvailabilityOption) error {
+	if !t.SupportsCloudAvailability() {
+		return &CloudAvailabilityNotSupportedError{TypeID: t.id}
+	}
+	t.clouds.set(plan, clouds)
+	return nil
+}
+
+func (t offerType) MissingCloudDetails(plan *Plan, cloud CloudAvailabilityOption) []string {
+	if t.cloudDetails == nil {
+		return nil
+	}
+
+	var missing []string
+	for name, ok := range t.cloudDetails(plan, cloud) {
+		if !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func (t offerType) SupportsImages() bool {
+	return t.images != nil
+}
+
+func (t offerType) ImageVersions(plan *Plan) (VirtualMachineImages, error) {
+	if !t.SupportsImages() {
+		return nil, &ImagesNotSupportedError{TypeID: t.id}
+	}
+	return VirtualMachineImages(*t.images(plan)), nil
+}
+
+func (t offerType) GetImageVersion(plan *Plan, version string) (VirtualMachineImage, bool, error) {
+	images, err := t.ImageVersions(plan)
+	if err != nil {
+		return VirtualMachineImage{}, false, err
+	}
+
+	image, ok := images[version]
+	return image, ok, nil
+}
+
+func (t offerType) SetImageVersion(plan *Plan, version string, image VirtualMachineImage) error {
+	if !t.SupportsImages() {
+		return &ImagesNotSupportedError{TypeID: t.id}
+	}
+
+	images := t.images(plan)
+	if *images == nil {
+		*images = make(map[string]VirtualMachineImage)
+	}
+	(*images)[version] = image
+	return nil
+}
+
+func (t offerType) DeleteImageVersion(plan *Plan, version string) error {
+	if !t.SupportsImages() {
+		return &ImagesNotSupportedError{TypeID: t.id}
+	}
+
+	delete(*t.images(plan), version)
+	return nil
+}
+
+func marketplaceListing(detail *OfferDetail) (title, summary, description *string) {
+	return &detail.MarketplaceDetail.Title, &detail.MarketplaceDetail.Summary, &detail.MarketplaceDetail.Description
+}
+
+func marketplaceAudience(detail *OfferDetail) *[]string {
+	return &detail.MarketplaceDetail.AllowedSubscriptions
+}
+
+// stringCloudAvailability gets and sets a cloud availability field of a plan which is a slice of strings
+func stringCloudAvailability(field func(plan *Plan) *[]string) *cloudAvailability {
+	return &cloudAvailability{
+		get: func(plan *Plan) []CloudAvailabilityOption {
+			var clouds []CloudAvailabilityOption
+			for _, cloud := range *field(plan) {
+				clouds = append(clouds, CloudAvailabilityOption(cloud))
+			}
+			return clouds
+		},
+		set: func(plan *Plan, clouds []CloudAvailabilityOption) {
+			values := make([]string, len(clouds))
+			for i, cloud := range clouds {
+				values[i] = string(cloud)
+			}
+			*field(plan) = values
+		},
+	}
+}
+
+// coreVMCloudDetails returns whether the description and useful links of a core VM plan are set for the cloud
+func coreVMCloudDetails(plan *Plan, cloud CloudAvailabilityOption) map[string]bool {
+	detail := plan.PlanCoreVMDetail
+	switch cloud {
+	case PublicOption:
+		return map[string]bool{
+			"microsoft-azure-corevm.skuDescriptionPublicAzure": detail.SKUDescriptionPublicAzure != "",
+			"microsoft-azure-corevm.usefulLinksPublicAzure":    len(detail.UsefulLinksPublicAzure) > 0,
+		}
+	case GovCloud:
+		return map[string]bool{
+			"microsoft-azure-corevm.skuDescriptionFairfax": detail.SKUDescriptionFairfax != "",
+			"microsoft-azure-corevm.usefulLinksFairfax":    len(detail.UsefulLinksFairfax) > 0,
+		}
+	case ChinaOption:
+		return map[string]bool{
+			"microsoft-azure-corevm.skuDescriptionMooncake": detail.SKUDescriptionMooncake != "",
+			"microsoft-azure-corevm.usefulLinksMooncake":    len(detail.UsefulLinksMooncake) > 0,
+		}
+	default:
+		return nil
+	}
+}
+
+// offerDetail returns the offer detail of the offer, creating it if the offer does not have one
+func offerDetail(offer *Offer) *OfferDetail {
+	if offer.Definition.OfferDetail == nil {
+		offer.Definition.OfferDetail = new(OfferDetail)
+	}
+	return offer.Definition.OfferDetail
+}
@@ -0,0 +1,134 @@
+package partner_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestGetOfferType_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	_, err := partner.GetOfferType("microsoft-azure-unknown")
+	require.Error(t, err)
+	assert.IsType(t, &partner.UnsupportedOfferTypeError{}, err)
+	assert.Contains(t, err.Error(), `"microsoft-azure-unknown"`)
+	assert.Contains(t, err.Error(), partner.CoreVMOfferType)
+}
+
+func TestOfferType_Listing(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Name        string
+		Offer       *partner.Offer
+		Description func(offer *partner.Offer) string
+	}{
+		{
+			Name:  "VirtualMachine",
+			Offer: test.NewMarketplaceVMOffer(),
+			Description: func(offer *partner.Offer) string {
+				return offer.Definition.OfferDetail.MarketplaceDetail.Description
+			},
+		},
+		{
+			Name:  "CoreVM",
+			Offer: test.NewMarketplaceCoreVMOffer(),
+			Description: func(offer *partner.Offer) string {
+				return offer.Definition.OfferDetail.CoreVMOfferDetail.Description
+			},
+		},
+		{
+			Name:  "Application",
+			Offer: test.NewMarketplaceApplicationOffer(),
+			Description: func(offer *partner.Offer) string {
+				return offer.Definition.OfferDetail.MarketplaceDetail.Description
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			offerType, err := c.Offer.OfferType()
+			require.NoError(t, err)
+			assert.Equal(t, c.Offer.TypeID, offerType.ID())
+
+			offerType.SetTitle(c.Offer, "new title")
+			offerType.SetSummary(c.Offer, "new summary")
+			offerType.SetDescription(c.Offer, "new description")
+			assert.Equal(t, "new title", offerType.Title(c.Offer))
+			assert.Equal(t, "new summary", offerType.Summary(c.Offer))
+			assert.Equal(t, "new description", offerType.Description(c.Offer))
+			assert.Equal(t, "new description", c.Description(c.Offer))
+		})
+	}
+}
+
+func TestOfferType_ImageVersions(t *testing.T) {
+	t.Parallel()
+
+	offer := test.NewMarketplaceCoreVMOffer()
+	offerType, err := offer.OfferType()
+	require.NoError(t, err)
+	require.True(t, offerType.SupportsImages())
+
+	plan := offer.GetPlanByID("planId_one")
+	require.NotNil(t, plan)
+	require.NoError(t, offerType.SetImageVersion(plan, "2020.1.1", partner.VirtualMachineImage{OSVHDURL: "osVhdUrl_three"}))
+	assert.Equal(t, "osVhdUrl_three", offer.Definition.Plans[0].PlanCoreVMDetail.VMImages["2020.1.1"].OSVHDURL)
+	assert.Empty(t, offer.Definition.Plans[0].PlanVirtualMachineDetail.VMImages)
+
+	image, ok, err := offerType.GetImageVersion(plan, "2020.1.1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "osVhdUrl_three", image.OSVHDURL)
+
+	require.NoError(t, offerType.DeleteImageVersion(plan, "2020.1.1"))
+	images, err := offerType.ImageVersions(plan)
+	require.NoError(t, err)
+	assert.Equal(t, partner.ImageVersions{"2018.10.10", "2018.11.05"}, images.Versions())
+}
+
+func TestOfferType_ImageVersionsNotSupported(t *testing.T) {
+	t.Parallel()
+
+	offer := test.NewMarketplaceApplicationOffer()
+	offerType, err := offer.OfferType()
+	require.NoError(t, err)
+	assert.False(t, offerType.SupportsImages())
+
+	plan := offer.GetPlanByID("managed")
+	require.NotNil(t, plan)
+	_, err = offerType.ImageVersions(plan)
+	assert.IsType(t, &partner.ImagesNotSupportedError{}, err)
+	err = offerType.SetImageVersion(plan, "1.0.0", partner.VirtualMachineImage{})
+	assert.EqualError(t, err, "offer type microsoft-azure-applications does not support image versions")
+}
+
+func TestOfferType_AllowedSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	offer := test.NewMarketplaceCoreVMOffer()
+	offerType, err := offer.OfferType()
+	require.NoError(t, err)
+
+	offerType.SetAllowedSubscriptions(offer, []string{"9f4f6b3e-7c6f-4a8d-9d5c-0b7b0c9a2f10"})
+	assert.Equal(t, []string{"9f4f6b3e-7c6f-4a8d-9d5c-0b7b0c9a2f10"}, offer.Definition.OfferDetail.CoreVMOfferDetail.AllowedSubscriptions)
+	assert.Empty(t, offer.Definition.OfferDetail.MarketplaceDetail.AllowedSubscriptions)
+}
+
+func TestParseSubscriptionIDs(t *testing.T) {
+	t.Parallel()
+
+	ids, err := partner.ParseSubscriptionIDs([]string{" 9F4F6B3E-7C6F-4A8D-9D5C-0B7B0C9A2F10", "4145cbfe-cd94-439d-aa3c-1ec6c7e53074", "9f4f6b3e-7c6f-4a8d-9d5c-0b7b0c9a2f10"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"9f4f6b3e-7c6f-4a8d-9d5c-0b7b0c9a2f10", "4145cbfe-cd94-439d-aa3c-1ec6c7e53074"}, ids)
+
+	_, err = partner.ParseSubscriptionIDs([]string{"9f4f6b3e7c6f4a8d9d5c0b7b0c9a2f10"})
+	assert.Error(t, err)
+}
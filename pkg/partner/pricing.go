@@ -0,0 +1,99 @@
+package partner
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// FreeTrialDurations are the free trial durations in months which can be offered on a virtual machine plan
+	FreeTrialDurations = []int{0, 1, 3}
+)
+
+// GetVMPricing returns the pricing of a virtual machine plan, preferring virtualMachinePricingV2 if it is set, or nil
+// if the plan has no pricing
+func (p *Plan) GetVMPricing() *VirtualMachinePricing {
+	if p.PlanVirtualMachineDetail.VirtualMachinePricingV2 != nil {
+		return p.PlanVirtualMachineDetail.VirtualMachinePricingV2
+	}
+	return p.PlanVirtualMachineDetail.VirtualMachinePricing
+}
+
+// UpdateVMPricing applies update to each version of the pricing set on a virtual machine plan, so they stay in sync.
+// If the plan has no pricing, virtualMachinePricing is created.
+func (p *Plan) UpdateVMPricing(update func(pricing *VirtualMachinePricing)) {
+	detail := &p.PlanVirtualMachineDetail
+	if detail.VirtualMachinePricing == nil && detail.VirtualMachinePricingV2 == nil {
+		detail.VirtualMachinePricing = new(VirtualMachinePricing)
+	}
+
+	for _, pricing := range []*VirtualMachinePricing{detail.VirtualMachinePricing, detail.VirtualMachinePricingV2} {
+		if pricing != nil {
+			update(pricing)
+		}
+	}
+}
+
+// Validate returns an error if the pricing can not be published, for example a bring your own license plan with a
+// price or a free trial of an unsupported duration
+func (vp *VirtualMachinePricing) Validate() error {
+	byol := vp.IsBringYourOwnLicense != nil && *vp.IsBringYourOwnLicense
+	if byol && (vp.CoreMultiplier != nil || len(vp.MarketPrices) > 0) {
+		return errors.New("a bring your own license plan can not have a core price or market prices")
+	}
+
+	if vp.FreeTrialDurationInMonths != nil {
+		if byol && *vp.FreeTrialDurationInMonths != 0 {
+			return errors.New("a bring your own license plan can not have a free trial")
+		}
+
+		if !validFreeTrialDuration(*vp.FreeTrialDurationInMonths) {
+			return fmt.Errorf("free trial duration of %d months must be one of %v", *vp.FreeTrialDurationInMonths, FreeTrialDurations)
+		}
+	}
+
+	if vp.CoreMultiplier != nil {
+		if err := vp.CoreMultiplier.validate(); err != nil {
+			return fmt.Errorf("core price: %v", err)
+		}
+	}
+
+	markets := make([]string, 0, len(vp.MarketPrices))
+	for market := range vp.MarketPrices {
+		markets = append(markets, market)
+	}
+	sort.Strings(markets)
+
+	for _, market := range markets {
+		price := vp.MarketPrices[market]
+		if err := price.validate(); err != nil {
+			return fmt.Errorf("market %s price: %v", market, err)
+		}
+	}
+	return nil
+}
+
+func (cm CoreMultiplier) validate() error {
+	if cm.Currency == "" {
+		return errors.New("currency is required")
+	}
+
+	if cm.Single == nil {
+		return errors.New("price is required")
+	}
+
+	if *cm.Single < 0 {
+		return fmt.Errorf("price %v must not be negative", *cm.Single)
+	}
+	return nil
+}
+
+func validFreeTrialDuration(months int) bool {
+	for _, d := range FreeTrialDurations {
+		if d == months {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package partner_test
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestPlan_UpdateVMPricing(t *testing.T) {
+	t.Parallel()
+
+	plan := test.NewMarketplaceVMOffer().Definition.Plans[0]
+	plan.UpdateVMPricing(func(pricing *partner.VirtualMachinePricing) {
+		pricing.FreeTrialDurationInMonths = to.IntPtr(3)
+	})
+	assert.Equal(t, 3, *plan.PlanVirtualMachineDetail.VirtualMachinePricing.FreeTrialDurationInMonths)
+	assert.Equal(t, 3, *plan.PlanVirtualMachineDetail.VirtualMachinePricingV2.FreeTrialDurationInMonths)
+
+	var empty partner.Plan
+	empty.UpdateVMPricing(func(pricing *partner.VirtualMachinePricing) {
+		pricing.IsBringYourOwnLicense = to.BoolPtr(true)
+	})
+	assert.True(t, *empty.PlanVirtualMachineDetail.VirtualMachinePricing.IsBringYourOwnLicense)
+	assert.Nil(t, empty.PlanVirtualMachineDetail.VirtualMachinePricingV2)
+	assert.Equal(t, empty.PlanVirtualMachineDetail.VirtualMachinePricing, empty.GetVMPricing())
+}
+
+func TestVirtualMachinePricing_Validate(t *testing.T) {
+	t.Parallel()
+
+	price := &partner.CoreMultiplier{Currency: "USD", Single: to.Float32Ptr(0.1)}
+	cases := map[string]struct {
+		Pricing partner.VirtualMachinePricing
+		Error   string
+	}{
+		"Paid": {
+			Pricing: partner.VirtualMachinePricing{IsBringYourOwnLicense: to.BoolPtr(false), FreeTrialDurationInMonths: to.IntPtr(1), CoreMultiplier: price},
+		},
+		"PaidBYOL": {
+			Pricing: partner.VirtualMachinePricing{IsBringYourOwnLicense: to.BoolPtr(true), CoreMultiplier: price},
+			Error:   "a bring your own license plan can not have a core price or market prices",
+		},
+		"FreeTrialBYOL": {
+			Pricing: partner.VirtualMachinePricing{IsBringYourOwnLicense: to.BoolPtr(true), FreeTrialDurationInMonths: to.IntPtr(1)},
+			Error:   "a bring your own license plan can not have a free trial",
+		},
+		"FreeTrialDuration": {
+			Pricing: partner.VirtualMachinePricing{FreeTrialDurationInMonths: to.IntPtr(6)},
+			Error:   "free trial duration of 6 months must be one of [0 1 3]",
+		},
+		"MarketWithoutCurrency": {
+			Pricing: partner.VirtualMachinePricing{MarketPrices: map[string]partner.CoreMultiplier{"DE": {Single: to.Float32Ptr(1)}}},
+			Error:   "market DE price: currency is required",
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			err := c.Pricing.Validate()
+			if c.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, c.Error)
+		})
+	}
+}
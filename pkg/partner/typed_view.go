@@ -0,0 +1,92 @@
+package partner
+
+type (
+	// planHeader holds the fields shared by the plans of every offer type
+	planHeader struct {
+		ID      string   `json:"planId,omitempty"`
+		Regions []string `json:"regions,omitempty"`
+	}
+
+	virtualMachinePlan struct {
+		planHeader
+		PlanVirtualMachineDetail
+	}
+
+	coreVMPlan struct {
+		planHeader
+		PlanCoreVMDetail
+	}
+
+	applicationPlan struct {
+		planHeader
+		PlanApplicationDetail
+	}
+
+	containerPlan struct {
+		planHeader
+		PlanContainerDetail
+	}
+
+	saasPlan struct {
+		planHeader
+		PlanSaaSDetail
+	}
+
+	// typedOffer shadows the definition of the offer with one whose plans only include the details for the offer type
+	typedOffer struct {
+		*Offer
+		Definition typedOfferDefinition `json:"definition,omitempty"`
+	}
+
+	typedOfferDefinition struct {
+		DisplayText string        `json:"displayText,omitempty"`
+		OfferDetail *OfferDetail  `json:"offer,omitempty"`
+		Plans       []interface{} `json:"plans,omitempty"`
+	}
+)
+
+// TypedPlan returns a view of the plan which only includes the details for the offer type, so the plans of one offer
+// type are not printed with the empty details of the others. Plans of unknown offer types are returned unchanged.
+func (o *Offer) TypedPlan(plan Plan) interface{} {
+	header := planHeader{ID: plan.ID, Regions: plan.Regions}
+	switch o.TypeID {
+	case VirtualMachineOfferType:
+		return virtualMachinePlan{planHeader: header, PlanVirtualMachineDetail: plan.PlanVirtualMachineDetail}
+	case CoreVMOfferType:
+		return coreVMPlan{planHeader: header, PlanCoreVMDetail: plan.PlanCoreVMDetail}
+	case ApplicationOfferType:
+		return applicationPlan{planHeader: header, PlanApplicationDetail: plan.PlanApplicationDetail}
+	case ContainerOfferType:
+		return containerPlan{planHeader: header, PlanContainerDetail: plan.PlanContainerDetail}
+	case SaaSOfferType:
+		return saasPlan{planHeader: header, PlanSaaSDetail: plan.PlanSaaSDetail}
+	default:
+		return plan
+	}
+}
+
+// TypedPlans returns a view of each of the plans in the offer which only includes the details for the offer type
+func (o *Offer) TypedPlans() []interface{} {
+	if o.Definition.Plans == nil {
+		return nil
+	}
+
+	plans := make([]interface{}, len(o.Definition.Plans))
+	for i, plan := range o.Definition.Plans {
+		plans[i] = o.TypedPlan(plan)
+	}
+	return plans
+}
+
+// TypedView returns a view of the offer whose plans only include the details for the offer type. The view marshals
+// to JSON which can be unmarshalled back into an Offer.
+func (o *Offer) TypedView() interface{} {
+	return typedOffer{
+		Offer: o,
+		Definition: typedOfferDefinition{
+			DisplayText: o.Definition.DisplayText,
+			OfferDetail: o.Definition.OfferDetail,
+			Plans:       o.TypedPlans(),
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package partner_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestOffer_TypedView(t *testing.T) {
+	cases := map[string]*partner.Offer{
+		"VirtualMachine": test.NewMarketplaceVMOffer(),
+		"CoreVM":         test.NewMarketplaceCoreVMOffer(),
+		"Application":    test.NewMarketplaceApplicationOffer(),
+		"Container":      test.NewMarketplaceContainerOffer(),
+		"SaaS":           test.NewMarketplaceSaaSOffer(),
+	}
+
+	for name, offer := range cases {
+		o := offer
+		t.Run(name, func(t *testing.T) {
+			bits, err := json.Marshal(o.TypedView())
+			require.NoError(t, err)
+
+			var actual partner.Offer
+			require.NoError(t, json.Unmarshal(bits, &actual))
+			actual.Etag = o.Etag
+			expected, err := json.Marshal(o)
+			require.NoError(t, err)
+			roundTripped, err := json.Marshal(actual)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(roundTripped))
+		})
+	}
+}
+
+func TestOffer_TypedPlan(t *testing.T) {
+	offer := test.NewMarketplaceContainerOffer()
+	bits, err := json.Marshal(offer.TypedPlan(offer.Definition.Plans[0]))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"planId": "container",
+		"microsoft-azure-containers.skuTitle": "skuTitle",
+		"microsoft-azure-containers.skuSummary": "skuSummary",
+		"microsoft-azure-containers.imageRepository": "contoso/app",
+		"microsoft-azure-containers.imageTags": ["1.0.0", "latest"]
+	}`, string(bits))
+
+	offer.TypeID = "unknown"
+	assert.Equal(t, offer.Definition.Plans[0], offer.TypedPlan(offer.Definition.Plans[0]))
+}
@@ -80,6 +80,11 @@ type (
 		IsBringYourOwnLicense     *bool           `json:"isByol,omitempty"`
 		FreeTrialDurationInMonths *int            `json:"freeTrialDurationInMonths,omitempty"`
 		CoreMultiplier            *CoreMultiplier `json:"coreMultiplier,omitempty"`
+		// MarketPrices override the prices generated from the core multiplier in individual markets. They are keyed
+		// by the two letter market code and each has the shape of the core multiplier:
+		//
+		//	"marketPrices": {"DE": {"currency": "EUR", "single": 0.09}}
+		MarketPrices map[string]CoreMultiplier `json:"marketPrices,omitempty"`
 	}
 
 	// PlanVirtualMachineDetail contains the details for virtual machine SKUs
@@ -163,12 +168,74 @@ type (
 		MigratedOffer              *bool                          `json:"microsoft-azure-corevm.migratedOffer,omitempty"`
 	}
 
+	// ApplicationSolutionType identifies whether an Azure Application SKU is a solution template or a managed
+	// application
+	ApplicationSolutionType string
+
+	// ApplicationPackage represents a version of an ARM template package for an Azure Application SKU
+	ApplicationPackage struct {
+		PackageURI    string `json:"packageUrl,omitempty"`
+		Description   string `json:"description,omitempty"`
+		PublishedDate string `json:"publishedDate,omitempty"`
+	}
+
+	// ApplicationAuthorization grants a publisher principal a role on the managed resource group of a managed
+	// application
+	ApplicationAuthorization struct {
+		PrincipalID      string `json:"principalId,omitempty"`
+		RoleDefinitionID string `json:"roleDefinitionId,omitempty"`
+	}
+
+	// PlanApplicationDetail contains the details for Azure Application SKUs
+	PlanApplicationDetail struct {
+		SKUTitle               string                        `json:"microsoft-azure-applications.skuTitle,omitempty"`
+		SKUSummary             string                        `json:"microsoft-azure-applications.skuSummary,omitempty"`
+		SKUDescription         string                        `json:"microsoft-azure-applications.skuDescription,omitempty"`
+		SolutionType           ApplicationSolutionType       `json:"microsoft-azure-applications.solutionType,omitempty"`
+		HideSKU                *bool                         `json:"microsoft-azure-applications.hideSKU,omitempty"`
+		CloudAvailability      []string                      `json:"microsoft-azure-applications.cloudAvailability,omitempty"`
+		Packages               map[string]ApplicationPackage `json:"microsoft-azure-applications.packages,omitempty"`
+		Authorizations         []ApplicationAuthorization    `json:"microsoft-azure-applications.authorizations,omitempty"`
+		AllowedCustomerActions []string                      `json:"microsoft-azure-applications.allowedCustomerActions,omitempty"`
+	}
+
+	// PlanContainerDetail contains the details for container SKUs
+	PlanContainerDetail struct {
+		SKUTitle        string   `json:"microsoft-azure-containers.skuTitle,omitempty"`
+		SKUSummary      string   `json:"microsoft-azure-containers.skuSummary,omitempty"`
+		SKULongSummary  string   `json:"microsoft-azure-containers.skuLongSummary,omitempty"`
+		HideSKU         *bool    `json:"microsoft-azure-containers.hideSKU,omitempty"`
+		ImageRepository string   `json:"microsoft-azure-containers.imageRepository,omitempty"`
+		ImageTags       []string `json:"microsoft-azure-containers.imageTags,omitempty"`
+	}
+
+	// SaaSPricing is the pricing for a SaaS SKU
+	SaaSPricing struct {
+		Model       string   `json:"model,omitempty"`
+		BillingTerm string   `json:"billingTerm,omitempty"`
+		Currency    string   `json:"currency,omitempty"`
+		Price       *float64 `json:"price,omitempty"`
+		MinUsers    *int     `json:"minUsers,omitempty"`
+		MaxUsers    *int     `json:"maxUsers,omitempty"`
+	}
+
+	// PlanSaaSDetail contains the details for SaaS SKUs
+	PlanSaaSDetail struct {
+		SKUTitle       string       `json:"microsoft-azure-saas.skuTitle,omitempty"`
+		SKUSummary     string       `json:"microsoft-azure-saas.skuSummary,omitempty"`
+		SKUDescription string       `json:"microsoft-azure-saas.skuDescription,omitempty"`
+		Pricing        *SaaSPricing `json:"microsoft-azure-saas.pricing,omitempty"`
+	}
+
 	// Plan maps to a SKU in the marketplace. In the API it is referred to as a Plan rather than SKU as it is in the UI.
 	Plan struct {
 		ID      string   `json:"planId,omitempty"`
 		Regions []string `json:"regions,omitempty"`
 		PlanVirtualMachineDetail
 		PlanCoreVMDetail
+		PlanApplicationDetail
+		PlanContainerDetail
+		PlanSaaSDetail
 	}
 
 	// OfferDetail holds the details for the marketplace offer
@@ -176,6 +243,15 @@ type (
 		VirtualMachineDetail
 		MarketplaceDetail
 		CoreVMOfferDetail
+		SaaSOfferDetail
+	}
+
+	// SaaSOfferDetail is the technical configuration for a SaaS offer
+	SaaSOfferDetail struct {
+		LandingPageURL    string `json:"microsoft-azure-saas.landingPageUrl,omitempty"`
+		ConnectionWebhook string `json:"microsoft-azure-saas.connectionWebhook,omitempty"`
+		AADTenantID       string `json:"microsoft-azure-saas.azureADTenantId,omitempty"`
+		AADAppID          string `json:"microsoft-azure-saas.azureADAppId,omitempty"`
 	}
 
 	// OfferDefinition contains offer details
@@ -294,6 +370,24 @@ type (
 	}
 )
 
+const (
+	// VirtualMachineOfferType is the offer type ID for marketplace virtual machine offers
+	VirtualMachineOfferType = "microsoft-azure-virtualmachines"
+
+	// CoreVMOfferType is the offer type ID for core virtual machine offers
+	CoreVMOfferType = "microsoft-azure-corevm"
+
+	// ApplicationOfferType is the offer type ID for Azure Application offers, which are solution templates and
+	// managed applications
+	ApplicationOfferType = "microsoft-azure-applications"
+
+	// ContainerOfferType is the offer type ID for container image offers
+	ContainerOfferType = "microsoft-azure-containers"
+
+	// SaaSOfferType is the offer type ID for software as a service offers
+	SaaSOfferType = "microsoft-azure-saas"
+)
+
 var (
 	// ARMDeploymentOption is an option for the deployment model slice in CoreVM which corresponds to Azure Resource Manager
 	ARMDeploymentOption DeploymentModelOption = "ARM"
@@ -311,13 +405,20 @@ var (
 
 	// Blackforest is an option for CloudAvailability for the German sovereign cloud
 	Blackforest CloudAvailabilityOption = "Blackforest"
+
+	// SolutionTemplateType is an Azure Application SKU which deploys an ARM template into the customer's subscription
+	SolutionTemplateType ApplicationSolutionType = "SolutionTemplate"
+
+	// ManagedApplicationType is an Azure Application SKU which the publisher manages on behalf of the customer
+	ManagedApplicationType ApplicationSolutionType = "ManagedApplication"
 )
 
-// GetPlanByID will return the named plan if it exists in the offer or nil
+// GetPlanByID will return the named plan if it exists in the offer or nil. The plan is not a copy, so changes to it
+// are made to the offer.
 func (o *Offer) GetPlanByID(planID string) *Plan {
-	for _, plan := range o.Definition.Plans {
-		if plan.ID == planID {
-			return &plan
+	for i := range o.Definition.Plans {
+		if o.Definition.Plans[i].ID == planID {
+			return &o.Definition.Plans[i]
 		}
 	}
 	return nil
@@ -334,14 +435,25 @@ func (o *Offer) SetPlanByID(plan Plan) {
 	o.Definition.Plans = append(o.Definition.Plans, plan)
 }
 
-// GetVMImages returns a map of VirtualMachineImages by version
-func (p *Plan) GetVMImages() map[string]VirtualMachineImage {
+// GetVMImages returns a map of VirtualMachineImages by version, which marshals in semantic version order
+//
+// Deprecated: GetVMImages guesses the plan detail from the images which are set. Use OfferType().ImageVersions to get
+// the images for the offer's type.
+func (p *Plan) GetVMImages() VirtualMachineImages {
 	switch {
 	case p.PlanCoreVMDetail.VMImages != nil:
-		return p.PlanCoreVMDetail.VMImages
+		return VirtualMachineImages(p.PlanCoreVMDetail.VMImages)
 	case p.PlanVirtualMachineDetail.VMImages != nil:
-		return p.PlanVirtualMachineDetail.VMImages
+		return VirtualMachineImages(p.PlanVirtualMachineDetail.VMImages)
 	default:
 		return nil
 	}
 }
+
+// GetApplicationPackages returns a map of ApplicationPackages by version, which marshals in semantic version order
+func (p *Plan) GetApplicationPackages() ApplicationPackages {
+	if p.PlanApplicationDetail.Packages == nil {
+		return nil
+	}
+	return ApplicationPackages(p.PlanApplicationDetail.Packages)
+}
@@ -0,0 +1,367 @@
+// Package patch applies RFC 6902 JSON Patches and RFC 7386 JSON Merge Patches to decoded JSON documents.
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type (
+	// JSONPatch is an RFC 6902 JSON Patch document
+	JSONPatch []Operation
+
+	// Operation is a single operation of a JSON Patch
+	Operation struct {
+		Op   string `json:"op"`
+		Path string `json:"path"`
+		From string `json:"from,omitempty"`
+		// Value is nil if the value member was not specified, which is distinct from a JSON null
+		Value json.RawMessage `json:"value,omitempty"`
+	}
+
+	// TestFailedError is returned when a test operation does not match the document
+	TestFailedError struct {
+		Index    int
+		Path     string
+		Expected interface{}
+		Actual   interface{}
+		// Missing is true if there is no value at the path
+		Missing bool
+	}
+)
+
+func (e TestFailedError) Error() string {
+	expected, _ := json.Marshal(e.Expected)
+	if e.Missing {
+		return fmt.Sprintf("test operation %d failed: %s does not exist, but expected %s", e.Index, e.Path, expected)
+	}
+	actual, _ := json.Marshal(e.Actual)
+	return fmt.Sprintf("test operation %d failed: %s is %s, but expected %s", e.Index, e.Path, actual, expected)
+}
+
+// ParseJSONPatch parses and checks the operations of a JSON Patch document
+func ParseJSONPatch(bits []byte) (JSONPatch, error) {
+	var p JSONPatch
+	if err := json.Unmarshal(bits, &p); err != nil {
+		return nil, fmt.Errorf("unable to parse JSON Patch: %v", err)
+	}
+
+	for i, op := range p {
+		if err := op.validate(); err != nil {
+			return nil, fmt.Errorf("operation %d: %v", i, err)
+		}
+	}
+	return p, nil
+}
+
+// Apply applies each operation in order and returns the patched document. The document is modified in place.
+func (p JSONPatch) Apply(doc interface{}) (interface{}, error) {
+	for i, op := range p {
+		var err error
+		doc, err = op.apply(i, doc)
+		if err != nil {
+			var tfe TestFailedError
+			if errors.As(err, &tfe) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("operation %d (%s %s): %v", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+// MergePatch applies an RFC 7386 merge patch to doc and returns the patched document. Objects in the patch are merged
+// key by key, a null removes a key and any other value replaces the target.
+func MergePatch(doc, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	target, ok := doc.(map[string]interface{})
+	if !ok {
+		target = make(map[string]interface{})
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(target, k)
+			continue
+		}
+		target[k] = MergePatch(target[k], v)
+	}
+	return target
+}
+
+func (op Operation) validate() error {
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return fmt.Errorf("%s requires a value", op.Op)
+		}
+	case "move", "copy":
+		if _, err := parsePointer(op.From); err != nil {
+			return err
+		}
+	case "remove":
+	default:
+		return fmt.Errorf("unknown op %q", op.Op)
+	}
+
+	_, err := parsePointer(op.Path)
+	return err
+}
+
+func (op Operation) apply(index int, doc interface{}) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if op.Value != nil {
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, err
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		return add(doc, path, value)
+	case "remove":
+		doc, _, err := remove(doc, path)
+		return doc, err
+	case "replace":
+		if _, err := get(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "move":
+		from, _ := parsePointer(op.From)
+		if isPrefix(from, path) && len(from) < len(path) {
+			return nil, fmt.Errorf("cannot move %s into one of its children", op.From)
+		}
+		doc, moved, err := remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, moved)
+	case "copy":
+		from, _ := parsePointer(op.From)
+		v, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, deepCopy(v))
+	case "test":
+		actual, err := get(doc, path)
+		if err != nil {
+			return nil, TestFailedError{Index: index, Path: op.Path, Expected: value, Missing: true}
+		}
+		if !reflect.DeepEqual(actual, value) {
+			return nil, TestFailedError{Index: index, Path: op.Path, Expected: value, Actual: actual}
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown op %q", op.Op)
+	}
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into its unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("JSON Pointer %q must be empty or start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(t, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	node := doc
+	for i, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("%s does not exist", pointerString(path[:i+1]))
+			}
+			node = child
+		case []interface{}:
+			idx, err := arrayIndex(token, len(n)-1)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", pointerString(path[:i+1]), err)
+			}
+			node = n[idx]
+		default:
+			return nil, fmt.Errorf("%s is not an object or array", pointerString(path[:i]))
+		}
+	}
+	return node, nil
+}
+
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[last] = value
+		return doc, nil
+	case []interface{}:
+		idx := len(p)
+		if last != "-" {
+			if idx, err = arrayIndex(last, len(p)); err != nil {
+				return nil, err
+			}
+		}
+		updated := append(p[:idx:idx], append([]interface{}{value}, p[idx:]...)...)
+		return replaceParent(doc, path[:len(path)-1], updated)
+	default:
+		return nil, fmt.Errorf("%s is not an object or array", pointerString(path[:len(path)-1]))
+	}
+}
+
+// remove removes the value at path and returns the updated document and the removed value
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("cannot remove the whole document")
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	last := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		v, ok := p[last]
+		if !ok {
+			return nil, nil, fmt.Errorf("%s does not exist", pointerString(path))
+		}
+		delete(p, last)
+		return doc, v, nil
+	case []interface{}:
+		idx, err := arrayIndex(last, len(p)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		v := p[idx]
+		updated := append(p[:idx:idx], p[idx+1:]...)
+		doc, err = replaceParent(doc, path[:len(path)-1], updated)
+		return doc, v, err
+	default:
+		return nil, nil, fmt.Errorf("%s is not an object or array", pointerString(path[:len(path)-1]))
+	}
+}
+
+// replaceParent stores an array which was resized at path, since slices cannot be resized in place
+func replaceParent(doc interface{}, path []string, arr []interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return arr, nil
+	}
+
+	grandparent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch g := grandparent.(type) {
+	case map[string]interface{}:
+		g[last] = arr
+	case []interface{}:
+		idx, err := arrayIndex(last, len(g)-1)
+		if err != nil {
+			return nil, err
+		}
+		g[idx] = arr
+	}
+	return doc, nil
+}
+
+// arrayIndex parses an array index token which must be between 0 and max
+func arrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%q is not a valid array index", token)
+	}
+
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 {
+		return 0, fmt.Errorf("%q is not a valid array index", token)
+	}
+
+	if idx > max {
+		return 0, fmt.Errorf("index %d is out of range", idx)
+	}
+	return idx, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func pointerString(path []string) string {
+	if len(path) == 0 {
+		return "the document"
+	}
+
+	var sb strings.Builder
+	for _, t := range path {
+		sb.WriteByte('/')
+		sb.WriteString(strings.Replace(strings.Replace(t, "~", "~0", -1), "/", "~1", -1))
+	}
+	return sb.String()
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, child := range t {
+			m[k] = deepCopy(child)
+		}
+		return m
+	case []interface{}:
+		arr := make([]interface{}, len(t))
+		for i, child := range t {
+			arr[i] = deepCopy(child)
+		}
+		return arr
+	default:
+		return v
+	}
+}
@@ -0,0 +1,177 @@
+package patch_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/pkg/patch"
+)
+
+func decode(t *testing.T, s string) interface{} {
+	var v interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+	return v
+}
+
+func encode(t *testing.T, v interface{}) string {
+	bits, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(bits)
+}
+
+func TestJSONPatch_Apply(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Name     string
+		Doc      string
+		Patch    string
+		Expected string
+	}{
+		{
+			Name:     "AddObjectMember",
+			Doc:      `{"foo":"bar"}`,
+			Patch:    `[{"op":"add","path":"/baz","value":"qux"}]`,
+			Expected: `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			Name:     "AddArrayElement",
+			Doc:      `{"foo":["bar","baz"]}`,
+			Patch:    `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			Expected: `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			Name:     "AppendArrayElement",
+			Doc:      `{"foo":["bar"]}`,
+			Patch:    `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			Expected: `{"foo":["bar",["abc","def"]]}`,
+		},
+		{
+			Name:     "RemoveArrayElement",
+			Doc:      `{"foo":["bar","qux","baz"]}`,
+			Patch:    `[{"op":"remove","path":"/foo/1"}]`,
+			Expected: `{"foo":["bar","baz"]}`,
+		},
+		{
+			Name:     "Replace",
+			Doc:      `{"baz":"qux","foo":"bar"}`,
+			Patch:    `[{"op":"replace","path":"/baz","value":null}]`,
+			Expected: `{"baz":null,"foo":"bar"}`,
+		},
+		{
+			Name:     "Move",
+			Doc:      `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			Patch:    `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			Expected: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			Name:     "CopyIsDeep",
+			Doc:      `{"a":{"b":1}}`,
+			Patch:    `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`,
+			Expected: `{"a":{"b":1},"c":{"b":2}}`,
+		},
+		{
+			Name:     "EscapedKeys",
+			Doc:      `{"a/b":1,"m~n":2}`,
+			Patch:    `[{"op":"test","path":"/a~1b","value":1},{"op":"remove","path":"/m~0n"}]`,
+			Expected: `{"a/b":1}`,
+		},
+		{
+			Name:     "ReplaceDocument",
+			Doc:      `{"a":1}`,
+			Patch:    `[{"op":"replace","path":"","value":[1]}]`,
+			Expected: `[1]`,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			p, err := patch.ParseJSONPatch([]byte(c.Patch))
+			require.NoError(t, err)
+			doc, err := p.Apply(decode(t, c.Doc))
+			require.NoError(t, err)
+			assert.JSONEq(t, c.Expected, encode(t, doc))
+		})
+	}
+}
+
+func TestJSONPatch_ApplyFails(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"RemoveMissing":  `[{"op":"remove","path":"/missing"}]`,
+		"ReplaceMissing": `[{"op":"replace","path":"/missing","value":1}]`,
+		"IndexTooLarge":  `[{"op":"add","path":"/foo/3","value":1}]`,
+		"LeadingZero":    `[{"op":"add","path":"/foo/01","value":1}]`,
+		"MissingParent":  `[{"op":"add","path":"/a/b/c","value":1}]`,
+		"MoveIntoChild":  `[{"op":"move","from":"/foo","path":"/foo/0"}]`,
+	}
+
+	for name, ops := range cases {
+		p, err := patch.ParseJSONPatch([]byte(ops))
+		require.NoError(t, err, name)
+		_, err = p.Apply(decode(t, `{"foo":["bar"]}`))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestJSONPatch_TestFailed(t *testing.T) {
+	t.Parallel()
+
+	p, err := patch.ParseJSONPatch([]byte(`[{"op":"test","path":"/foo","value":"bar"},{"op":"test","path":"/baz","value":1}]`))
+	require.NoError(t, err)
+
+	_, err = p.Apply(decode(t, `{"foo":"qux"}`))
+	var tfe patch.TestFailedError
+	require.True(t, errors.As(err, &tfe))
+	assert.Equal(t, 0, tfe.Index)
+	assert.Equal(t, `test operation 0 failed: /foo is "qux", but expected "bar"`, err.Error())
+
+	_, err = p.Apply(decode(t, `{"foo":"bar"}`))
+	assert.EqualError(t, err, "test operation 1 failed: /baz does not exist, but expected 1")
+}
+
+func TestParseJSONPatch_Fails(t *testing.T) {
+	t.Parallel()
+
+	for _, ops := range []string{
+		`{"op":"add"}`,
+		`[{"op":"add","path":"/a"}]`,
+		`[{"op":"frob","path":"/a"}]`,
+		`[{"op":"remove","path":"a"}]`,
+		`[{"op":"move","from":"a","path":"/a"}]`,
+	} {
+		_, err := patch.ParseJSONPatch([]byte(ops))
+		assert.Error(t, err, ops)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	t.Parallel()
+
+	// examples from RFC 7386 appendix A
+	cases := [][3]string{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, c := range cases {
+		assert.JSONEq(t, c[2], encode(t, patch.MergePatch(decode(t, c[0]), decode(t, c[1]))), c[1])
+	}
+}
@@ -0,0 +1,398 @@
+// Package policy loads organization specific publishing rules from a policy file and evaluates them over offers.
+//
+// A policy file is YAML or JSON containing a list of rules. Each rule selects values from the offer with a JSONPath
+// selector and asserts properties of each selected value. Assertions and conditions may use a path relative to the
+// selected value, starting with @.
+//
+//	rules:
+//	  - id: sku-title-prefix
+//	    description: SKU titles must start with the product name
+//	    selector: $.definition.plans[*]['microsoft-azure-virtualmachines.skuTitle']
+//	    assert:
+//	      regex: ^Contoso
+//	  - id: qa-subscription
+//	    selector: $.definition.offer['microsoft-azure-marketplace.allowedSubscriptions']
+//	    assert:
+//	      contains: 00000000-0000-0000-0000-000000000000
+//	  - id: pricing-approval
+//	    selector: $.definition.plans[*]
+//	    when:
+//	      - path: "@.virtualMachinePricing.isByol"
+//	        enum: [false]
+//	    assert:
+//	      path: "@.planId"
+//	      regex: -approved$
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/devigned/pub/pkg/jsonpath"
+	"github.com/devigned/pub/pkg/lint"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+const (
+	// FileEnvVar is the environment variable used to configure the default policy file
+	FileEnvVar = "PUB_POLICY_FILE"
+)
+
+type (
+	// Policy is a set of user defined rules
+	Policy struct {
+		Rules []Rule `json:"rules" yaml:"rules"`
+	}
+
+	// Rule selects values from an offer and asserts properties of each value
+	Rule struct {
+		ID          string        `json:"id" yaml:"id"`
+		Description string        `json:"description,omitempty" yaml:"description,omitempty"`
+		Severity    lint.Severity `json:"severity,omitempty" yaml:"severity,omitempty"`
+		Selector    string        `json:"selector" yaml:"selector"`
+		// When lists conditions which must all hold for the rule to apply to a selected value. A condition over a
+		// value which is missing or empty does not hold.
+		When   []Assertion `json:"when,omitempty" yaml:"when,omitempty"`
+		Assert Assertion   `json:"assert" yaml:"assert"`
+	}
+
+	// Assertion is a set of constraints over a value. If Path is set, the constraints apply to the values selected by
+	// the path relative to the value.
+	Assertion struct {
+		Path      string        `json:"path,omitempty" yaml:"path,omitempty"`
+		Required  bool          `json:"required,omitempty" yaml:"required,omitempty"`
+		Regex     string        `json:"regex,omitempty" yaml:"regex,omitempty"`
+		MinLength *int          `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+		MaxLength *int          `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+		Enum      []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+		Contains  interface{}   `json:"contains,omitempty" yaml:"contains,omitempty"`
+
+		path  *jsonpath.Path
+		regex *regexp.Regexp
+	}
+)
+
+// ResolveFile returns the policy file path from the flag value, or from the PUB_POLICY_FILE environment variable if
+// the flag value is empty
+func ResolveFile(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return os.Getenv(FileEnvVar)
+}
+
+// Load reads and compiles a policy from a YAML or JSON file
+func Load(path string) (*Policy, error) {
+	bits, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bits)
+}
+
+// Parse reads and compiles a policy from YAML or JSON
+func Parse(bits []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.Unmarshal(bits, &p); err != nil {
+		return nil, fmt.Errorf("unable to parse policy: %v", err)
+	}
+
+	if err := p.compile(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// LintRules converts the policy rules into lint rules so they can be evaluated and reported by a lint.Linter
+func (p *Policy) LintRules() []lint.Rule {
+	rules := make([]lint.Rule, len(p.Rules))
+	for i := range p.Rules {
+		rule := p.Rules[i]
+		rules[i] = lint.Rule{
+			ID:          rule.ID,
+			Description: rule.Description,
+			Severity:    rule.Severity,
+			Check:       rule.check,
+		}
+	}
+	return rules
+}
+
+// Check evaluates the policy over an offer
+func (p *Policy) Check(offer *partner.Offer, suppress ...string) (*lint.Report, error) {
+	linter, err := lint.New(p.LintRules(), suppress...)
+	if err != nil {
+		return nil, err
+	}
+	return linter.Lint(offer), nil
+}
+
+func (p *Policy) compile() error {
+	if len(p.Rules) == 0 {
+		return errors.New("policy must contain at least one rule")
+	}
+
+	ids := make(map[string]bool, len(p.Rules))
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.ID == "" {
+			return fmt.Errorf("policy rule %d is missing an id", i)
+		}
+
+		if ids[rule.ID] {
+			return fmt.Errorf("policy rule %q is defined more than once", rule.ID)
+		}
+		ids[rule.ID] = true
+
+		switch rule.Severity {
+		case "":
+			rule.Severity = lint.ErrorSeverity
+		case lint.ErrorSeverity, lint.WarningSeverity:
+		default:
+			return fmt.Errorf("policy rule %q has unknown severity %q", rule.ID, rule.Severity)
+		}
+
+		if _, err := jsonpath.Parse(rule.Selector); err != nil {
+			return fmt.Errorf("policy rule %q has an invalid selector: %v", rule.ID, err)
+		}
+
+		for j := range rule.When {
+			if err := rule.When[j].compile(); err != nil {
+				return fmt.Errorf("policy rule %q has an invalid condition: %v", rule.ID, err)
+			}
+		}
+
+		if err := rule.Assert.compile(); err != nil {
+			return fmt.Errorf("policy rule %q has an invalid assertion: %v", rule.ID, err)
+		}
+	}
+	return nil
+}
+
+func (r Rule) check(offer *partner.Offer) []lint.Violation {
+	doc, err := jsonpath.ToDocument(offer)
+	if err != nil {
+		return []lint.Violation{{Path: "$", Message: fmt.Sprintf("unable to evaluate policy: %v", err)}}
+	}
+
+	selector, _ := jsonpath.Parse(r.Selector)
+	matches := selector.Select(doc)
+	if len(matches) == 0 && selector.IsDefinite() {
+		matches = []jsonpath.Match{{Path: selector.String()}}
+	}
+
+	var violations []lint.Violation
+	for _, m := range matches {
+		applies := true
+		for _, cond := range r.When {
+			if !cond.holds(m) {
+				applies = false
+				break
+			}
+		}
+
+		if applies {
+			violations = append(violations, r.Assert.evaluate(m)...)
+		}
+	}
+	return violations
+}
+
+func (a *Assertion) compile() error {
+	if a.Path != "" {
+		p, err := jsonpath.Parse(a.Path)
+		if err != nil {
+			return err
+		}
+		if p.Root != "@" {
+			return fmt.Errorf("path %q must be relative and start with @", a.Path)
+		}
+		a.path = p
+	}
+
+	if a.Regex != "" {
+		re, err := regexp.Compile(a.Regex)
+		if err != nil {
+			return err
+		}
+		a.regex = re
+	}
+
+	if !a.Required && a.regex == nil && a.MinLength == nil && a.MaxLength == nil && a.Enum == nil && a.Contains == nil {
+		return errors.New("must specify at least one of: required, regex, minLength, maxLength, enum, contains")
+	}
+
+	a.Enum = normalizeAll(a.Enum)
+	a.Contains = normalize(a.Contains)
+	return nil
+}
+
+// holds returns true if the assertion, used as a condition, is satisfied by the match. Unlike evaluate, a condition
+// which selects nothing, or selects a missing or empty value, does not hold.
+func (a *Assertion) holds(m jsonpath.Match) bool {
+	targets := a.targets(m)
+	if len(targets) == 0 {
+		return false
+	}
+
+	for _, t := range targets {
+		if isEmpty(t.Value) || len(a.violations(t.Value)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluate returns a violation for each constraint the selected values do not satisfy
+func (a *Assertion) evaluate(m jsonpath.Match) []lint.Violation {
+	targets := a.targets(m)
+	if len(targets) == 0 && a.path != nil && a.path.IsDefinite() {
+		targets = []jsonpath.Match{{Path: m.Path + strings.TrimPrefix(a.path.String(), "@")}}
+	}
+
+	var violations []lint.Violation
+	for _, t := range targets {
+		for _, msg := range a.violations(t.Value) {
+			violations = append(violations, lint.Violation{Path: t.Path, Message: msg})
+		}
+	}
+	return violations
+}
+
+// targets returns the values the assertion applies to, which are those selected by its path relative to the match
+func (a *Assertion) targets(m jsonpath.Match) []jsonpath.Match {
+	if a.path == nil {
+		return []jsonpath.Match{m}
+	}
+
+	targets := a.path.Select(m.Value)
+	for i := range targets {
+		targets[i].Path = m.Path + strings.TrimPrefix(targets[i].Path, "@")
+	}
+	return targets
+}
+
+func (a *Assertion) violations(value interface{}) []string {
+	if isEmpty(value) {
+		if a.Required {
+			return []string{"value is required"}
+		}
+		return nil
+	}
+
+	var msgs []string
+	if a.regex != nil {
+		if s, ok := value.(string); !ok || !a.regex.MatchString(s) {
+			msgs = append(msgs, fmt.Sprintf("value %s does not match %q", display(value), a.Regex))
+		}
+	}
+
+	if n, ok := length(value); ok {
+		if a.MinLength != nil && n < *a.MinLength {
+			msgs = append(msgs, fmt.Sprintf("length %d is less than the minimum of %d", n, *a.MinLength))
+		}
+		if a.MaxLength != nil && n > *a.MaxLength {
+			msgs = append(msgs, fmt.Sprintf("length %d is greater than the maximum of %d", n, *a.MaxLength))
+		}
+	}
+
+	if a.Enum != nil && !containsValue(a.Enum, value) {
+		msgs = append(msgs, fmt.Sprintf("value %s is not one of %s", display(value), display(a.Enum)))
+	}
+
+	if a.Contains != nil {
+		items, ok := value.([]interface{})
+		if !ok || !containsValue(items, a.Contains) {
+			msgs = append(msgs, fmt.Sprintf("value does not contain %s", display(a.Contains)))
+		}
+	}
+	return msgs
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+func length(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case string:
+		return len([]rune(v)), true
+	case []interface{}:
+		return len(v), true
+	default:
+		return 0, false
+	}
+}
+
+func containsValue(items []interface{}, value interface{}) bool {
+	for _, item := range items {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func display(value interface{}) string {
+	bits, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(bits)
+}
+
+// normalize converts values decoded from YAML into the types produced by encoding/json so they can be compared with
+// values selected from an offer
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case []interface{}:
+		return normalizeAll(v)
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprintf("%v", k)] = normalize(val)
+		}
+		return m
+	default:
+		return v
+	}
+}
+
+func normalizeAll(values []interface{}) []interface{} {
+	if values == nil {
+		return nil
+	}
+
+	normalized := make([]interface{}, len(values))
+	for i, v := range values {
+		normalized[i] = normalize(v)
+	}
+	return normalized
+}
@@ -0,0 +1,192 @@
+package policy_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/lint"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/policy"
+)
+
+func check(t *testing.T, yml string, offer *partner.Offer, suppress ...string) *lint.Report {
+	p, err := policy.Parse([]byte(yml))
+	require.NoError(t, err)
+	report, err := p.Check(offer, suppress...)
+	require.NoError(t, err)
+	return report
+}
+
+func TestParse_FailOnInvalidPolicy(t *testing.T) {
+	cases := map[string]string{
+		"NoRules":          "rules: []",
+		"NotYAML":          "rules: [",
+		"MissingID":        "rules: [{selector: $.id, assert: {required: true}}]",
+		"DuplicateID":      "rules: [{id: a, selector: $.id, assert: {required: true}}, {id: a, selector: $.id, assert: {required: true}}]",
+		"BadSeverity":      "rules: [{id: a, severity: fatal, selector: $.id, assert: {required: true}}]",
+		"BadSelector":      "rules: [{id: a, selector: id, assert: {required: true}}]",
+		"EmptyAssertion":   "rules: [{id: a, selector: $.id, assert: {}}]",
+		"AbsoluteAssert":   "rules: [{id: a, selector: $.id, assert: {path: $.id, required: true}}]",
+		"BadRegex":         "rules: [{id: a, selector: $.id, assert: {regex: '('}}]",
+		"BadWhenCondition": "rules: [{id: a, selector: $.id, when: [{path: '@.x'}], assert: {required: true}}]",
+	}
+
+	for name, yml := range cases {
+		y := yml
+		t.Run(name, func(t *testing.T) {
+			_, err := policy.Parse([]byte(y))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCheck_Regex(t *testing.T) {
+	report := check(t, `
+rules:
+  - id: sku-title-prefix
+    selector: $.definition.plans[*]['microsoft-azure-virtualmachines.skuTitle']
+    assert:
+      regex: ^Contoso
+`, test.NewMarketplaceVMOffer())
+
+	require.Len(t, report.Findings, 1)
+	f := report.Findings[0]
+	assert.Equal(t, "sku-title-prefix", f.RuleID)
+	assert.Equal(t, lint.ErrorSeverity, f.Severity)
+	assert.Equal(t, "$.definition.plans[0]['microsoft-azure-virtualmachines.skuTitle']", f.Path)
+	assert.Equal(t, `value "skuTitle" does not match "^Contoso"`, f.Message)
+	assert.True(t, report.HasErrors())
+}
+
+func TestCheck_Contains(t *testing.T) {
+	yml := `
+rules:
+  - id: qa-subscription
+    severity: warning
+    selector: $.definition.offer['microsoft-azure-marketplace.allowedSubscriptions']
+    assert:
+      contains: %s
+`
+	offer := test.NewMarketplaceVMOffer()
+	report := check(t, fmtPolicy(yml, "4145cbfe-cd94-439d-aa3c-1ec6c7e53074"), offer)
+	assert.Empty(t, report.Findings)
+
+	report = check(t, fmtPolicy(yml, "00000000-0000-0000-0000-000000000000"), offer)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, lint.WarningSeverity, report.Findings[0].Severity)
+	assert.False(t, report.HasErrors())
+}
+
+func TestCheck_LengthAndEnum(t *testing.T) {
+	report := check(t, `
+rules:
+  - id: title-length
+    selector: $.definition.offer['microsoft-azure-marketplace.title']
+    assert:
+      minLength: 10
+      maxLength: 20
+  - id: os-family
+    selector: $.definition.plans[*]['microsoft-azure-virtualmachines.operatingSystemFamily']
+    assert:
+      enum: [Windows]
+`, test.NewMarketplaceVMOffer())
+
+	require.Len(t, report.Findings, 2)
+	assert.Equal(t, "title-length", report.Findings[0].RuleID)
+	assert.Equal(t, "length 5 is less than the minimum of 10", report.Findings[0].Message)
+	assert.Equal(t, "os-family", report.Findings[1].RuleID)
+}
+
+func TestCheck_RequiredOnMissingValue(t *testing.T) {
+	offer := test.NewMarketplaceVMOffer()
+	offer.Definition.OfferDetail.PrivacyURL = ""
+
+	report := check(t, `
+rules:
+  - id: privacy
+    selector: $.definition.offer['microsoft-azure-marketplace.privacyURL']
+    assert:
+      required: true
+  - id: not-there
+    selector: $.definition.offer.notThere
+    assert:
+      required: true
+`, offer)
+
+	require.Len(t, report.Findings, 2)
+	for _, f := range report.Findings {
+		assert.Equal(t, "value is required", f.Message)
+	}
+	assert.Equal(t, "$.definition.offer.notThere", report.Findings[0].Path)
+}
+
+func TestCheck_WhenWithRelativeAssertion(t *testing.T) {
+	yml := `
+rules:
+  - id: byol-plan-id
+    selector: $.definition.plans[*]
+    when:
+      - path: "@['microsoft-azure-virtualmachines.skuTitle']"
+        enum: [skuTitle]
+    assert:
+      path: "@.planId"
+      regex: -byol$
+`
+	offer := test.NewMarketplaceVMOffer()
+	report := check(t, yml, offer)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "$.definition.plans[0].planId", report.Findings[0].Path)
+
+	offer.Definition.Plans[0].PlanVirtualMachineDetail.SKUTitle = "other"
+	report = check(t, yml, offer)
+	assert.Empty(t, report.Findings)
+}
+
+func TestCheck_WhenOnMissingValue(t *testing.T) {
+	yml := `
+rules:
+  - id: pricing-approval
+    selector: $.definition.plans[*]
+    when:
+      - path: "@.virtualMachinePricing.isByol"
+        enum: [false]
+    assert:
+      path: "@.planId"
+      regex: -approved$
+`
+	offer := test.NewMarketplaceVMOffer()
+	offer.Definition.Plans[0].ID = "nopricing"
+	offer.Definition.Plans[0].PlanVirtualMachineDetail.VirtualMachinePricing = nil
+	report := check(t, yml, offer)
+	assert.Empty(t, report.Findings, "a rule does not apply when its condition selects nothing")
+
+	offer.Definition.Plans[0].PlanVirtualMachineDetail.VirtualMachinePricing = &partner.VirtualMachinePricing{}
+	report = check(t, yml, offer)
+	assert.Empty(t, report.Findings, "a rule does not apply when its condition selects a missing value")
+
+	offer.Definition.Plans[0].PlanVirtualMachineDetail.VirtualMachinePricing.IsBringYourOwnLicense = to.BoolPtr(false)
+	report = check(t, yml, offer)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "$.definition.plans[0].planId", report.Findings[0].Path)
+}
+
+func TestCheck_Suppress(t *testing.T) {
+	p, err := policy.Parse([]byte("rules: [{id: a, selector: $.id, assert: {regex: '^nope$'}}]"))
+	require.NoError(t, err)
+
+	report, err := p.Check(test.NewMarketplaceVMOffer(), "a")
+	require.NoError(t, err)
+	assert.Empty(t, report.Findings)
+
+	_, err = p.Check(test.NewMarketplaceVMOffer(), "b")
+	assert.Error(t, err)
+}
+
+func fmtPolicy(yml, value string) string {
+	return strings.Replace(yml, "%s", value, 1)
+}
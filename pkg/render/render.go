@@ -0,0 +1,199 @@
+// Package render renders offer and SKU files as templates before they are parsed, so the same definition can be used
+// for several publishers or environments.
+//
+// Two syntaxes are supported in the same file. ${name} is replaced with a value, where name may be a dotted path into
+// nested values, and falls back to the environment variable of the same name; $${ escapes a literal ${. Go templates
+// ({{ .name }}) are executed with the values as data and the functions env, default, required and toJSON. Values are
+// inserted as text, so use toJSON to insert a value as a quoted JSON string or structure.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+type (
+	// Options control how a file is rendered
+	Options struct {
+		// Values are the data for the template
+		Values map[string]interface{}
+		// Strict fails on undefined values instead of rendering them as empty
+		Strict bool
+		// LookupEnv looks up environment variables, and defaults to os.LookupEnv
+		LookupEnv func(string) (string, bool)
+	}
+
+	// UndefinedError is returned in strict mode for each value which is not defined
+	UndefinedError struct {
+		Names []string
+	}
+)
+
+// noValue is what text/template renders for a missing map key
+const noValue = "<no value>"
+
+func (e UndefinedError) Error() string {
+	return fmt.Sprintf("undefined template values: %s", strings.Join(e.Names, ", "))
+}
+
+// Render substitutes ${name} references and then executes the result as a Go template named name
+func Render(name string, bits []byte, opts Options) ([]byte, error) {
+	if opts.LookupEnv == nil {
+		opts.LookupEnv = os.LookupEnv
+	}
+
+	substituted, err := substitute(string(bits), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	missingKey := "missingkey=default"
+	if opts.Strict {
+		missingKey = "missingkey=error"
+	}
+
+	tmpl, err := template.New(name).Option(missingKey).Funcs(funcs(opts)).Parse(substituted)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, opts.Values); err != nil {
+		return nil, err
+	}
+
+	// like Helm, values which are not defined render as empty rather than as <no value>
+	return bytes.Replace(buf.Bytes(), []byte(noValue), nil, -1), nil
+}
+
+// Lookup returns the value at a dotted path, such as publisher.id, within values
+func Lookup(values map[string]interface{}, path string) (interface{}, bool) {
+	var node interface{} = values
+	for _, key := range strings.Split(path, ".") {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if node, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// Merge deep merges src into dst, with the values in src taking precedence, and returns dst
+func Merge(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = make(map[string]interface{}, len(src))
+	}
+
+	for k, v := range src {
+		srcMap, srcOK := v.(map[string]interface{})
+		dstMap, dstOK := dst[k].(map[string]interface{})
+		if srcOK && dstOK {
+			dst[k] = Merge(dstMap, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+func substitute(text string, opts Options) (string, error) {
+	var sb strings.Builder
+	var undefined []string
+	for {
+		start := strings.Index(text, "${")
+		if start < 0 {
+			sb.WriteString(text)
+			break
+		}
+
+		if start > 0 && text[start-1] == '$' {
+			sb.WriteString(text[:start-1])
+			sb.WriteString("${")
+			text = text[start+2:]
+			continue
+		}
+
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated ${ at %q", abbreviate(text[start:]))
+		}
+
+		sb.WriteString(text[:start])
+		name := strings.TrimSpace(text[start+2 : start+end])
+		if name == "" {
+			return "", errors.New("${} must contain a value name")
+		}
+
+		if value, ok := lookup(name, opts); ok {
+			sb.WriteString(value)
+		} else {
+			undefined = append(undefined, name)
+		}
+		text = text[start+end+1:]
+	}
+
+	if opts.Strict && len(undefined) > 0 {
+		return "", UndefinedError{Names: undefined}
+	}
+	return sb.String(), nil
+}
+
+// lookup returns the text for a ${name} reference from the values or the environment
+func lookup(name string, opts Options) (string, bool) {
+	if v, ok := Lookup(opts.Values, name); ok && v != nil {
+		switch t := v.(type) {
+		case string:
+			return t, true
+		case map[string]interface{}, []interface{}:
+			bits, err := json.Marshal(t)
+			return string(bits), err == nil
+		default:
+			return fmt.Sprint(t), true
+		}
+	}
+	return opts.LookupEnv(name)
+}
+
+func funcs(opts Options) template.FuncMap {
+	return template.FuncMap{
+		"env": func(name string) (string, error) {
+			v, ok := opts.LookupEnv(name)
+			if !ok && opts.Strict {
+				return "", UndefinedError{Names: []string{name}}
+			}
+			return v, nil
+		},
+		"default": func(def, v interface{}) interface{} {
+			if v == nil || v == "" {
+				return def
+			}
+			return v
+		},
+		"required": func(msg string, v interface{}) (interface{}, error) {
+			if v == nil || v == "" {
+				return nil, errors.New(msg)
+			}
+			return v, nil
+		},
+		"toJSON": func(v interface{}) (string, error) {
+			bits, err := json.Marshal(v)
+			return string(bits), err
+		},
+	}
+}
+
+func abbreviate(s string) string {
+	if len(s) > 20 {
+		return s[:20] + "..."
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/pkg/render"
+)
+
+func lookupEnv(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	opts := render.Options{
+		Values: map[string]interface{}{
+			"publisher": map[string]interface{}{"id": "contoso-dev"},
+			"regions":   []interface{}{"US", "CA"},
+			"port":      float64(8080),
+		},
+		LookupEnv: lookupEnv(map[string]string{"VHD_URL": "https://contoso.com/dev.vhd"}),
+	}
+
+	cases := map[string]string{
+		`{"publisherId": "${publisher.id}"}`:              `{"publisherId": "contoso-dev"}`,
+		`{"regions": ${regions}, "port": ${ port }}`:      `{"regions": ["US","CA"], "port": 8080}`,
+		`{"osVhdUrl": "${VHD_URL}"}`:                      `{"osVhdUrl": "https://contoso.com/dev.vhd"}`,
+		`{"text": "$${literal}"}`:                         `{"text": "${literal}"}`,
+		`{"missing": "${missing}"}`:                       `{"missing": ""}`,
+		`{"publisherId": "{{ .publisher.id }}"}`:          `{"publisherId": "contoso-dev"}`,
+		`{"regions": {{ toJSON .regions }}}`:              `{"regions": ["US","CA"]}`,
+		`{"url": "{{ env "VHD_URL" }}"}`:                  `{"url": "https://contoso.com/dev.vhd"}`,
+		`{"missing": "{{ .missing }}"}`:                   `{"missing": ""}`,
+		`{"title": "{{ .title | default "Contoso" }}"}`:   `{"title": "Contoso"}`,
+		`{"both": "${publisher.id}-{{ .publisher.id }}"}`: `{"both": "contoso-dev-contoso-dev"}`,
+	}
+
+	for input, expected := range cases {
+		out, err := render.Render("offer.json", []byte(input), opts)
+		if assert.NoError(t, err, input) {
+			assert.Equal(t, expected, string(out), input)
+		}
+	}
+}
+
+func TestRender_Strict(t *testing.T) {
+	t.Parallel()
+
+	opts := render.Options{
+		Values:    map[string]interface{}{"id": "contoso"},
+		Strict:    true,
+		LookupEnv: lookupEnv(nil),
+	}
+
+	_, err := render.Render("offer.json", []byte(`{"id": "${id}", "a": "${a}", "b": "${b.c}"}`), opts)
+	assert.Equal(t, render.UndefinedError{Names: []string{"a", "b.c"}}, err)
+
+	for _, input := range []string{`{{ .missing }}`, `{{ env "MISSING" }}`, `{{ required "title is required" .title }}`} {
+		_, err := render.Render("offer.json", []byte(input), opts)
+		assert.Error(t, err, input)
+	}
+
+	out, err := render.Render("offer.json", []byte(`{"id": "${id}"}`), opts)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id": "contoso"}`, string(out))
+}
+
+func TestRender_Fails(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"${unterminated", "${}", "{{ .id "} {
+		_, err := render.Render("offer.json", []byte(input), render.Options{LookupEnv: lookupEnv(nil)})
+		assert.Error(t, err, input)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	dst := map[string]interface{}{
+		"publisher": map[string]interface{}{"id": "contoso", "name": "Contoso"},
+		"regions":   []interface{}{"US"},
+	}
+	src := map[string]interface{}{
+		"publisher": map[string]interface{}{"id": "contoso-dev"},
+		"regions":   []interface{}{"CA"},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"publisher": map[string]interface{}{"id": "contoso-dev", "name": "Contoso"},
+		"regions":   []interface{}{"CA"},
+	}, render.Merge(dst, src))
+}
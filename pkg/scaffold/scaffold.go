@@ -0,0 +1,217 @@
+// Package scaffold generates minimal offer skeletons for each offer type. Required fields which are not given are set
+// to placeholders starting with TODO, so they are easy to find and fail validation until they are filled in.
+package scaffold
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/to"
+
+	"github.com/devigned/pub/pkg/partner"
+)
+
+// Placeholder prefixes the values of required fields which still need to be filled in
+const Placeholder = "TODO"
+
+type (
+	// Options are the details of the new offer. Empty fields are set to placeholders.
+	Options struct {
+		TypeID             string
+		PublisherID        string
+		OfferID            string
+		Title              string
+		Summary            string
+		Description        string
+		SupportContact     Contact
+		EngineeringContact Contact
+		SKU                SKU
+	}
+
+	// Contact is a name, email and phone number
+	Contact struct {
+		Name  string
+		Email string
+		Phone string
+	}
+
+	// SKU are the details of the first SKU of the offer
+	SKU struct {
+		ID      string
+		Title   string
+		Summary string
+	}
+)
+
+var (
+	// planSkeletons create the first plan of an offer with the fields required by the offer type
+	planSkeletons = map[string]func(sku SKU) partner.Plan{
+		partner.VirtualMachineOfferType: func(sku SKU) partner.Plan {
+			return partner.Plan{
+				ID: sku.ID,
+				PlanVirtualMachineDetail: partner.PlanVirtualMachineDetail{
+					SKUTitle:              sku.Title,
+					SKUSummary:            sku.Summary,
+					SKUDescription:        placeholder("SKU description"),
+					CloudAvailability:     []string{string(partner.PublicOption)},
+					OperatingSystemFamily: placeholder("Linux or Windows"),
+					OSType:                placeholder("operating system type, for example Ubuntu"),
+					VirtualMachinePricingV2: &partner.VirtualMachinePricing{
+						IsBringYourOwnLicense:     to.BoolPtr(true),
+						FreeTrialDurationInMonths: to.IntPtr(0),
+					},
+				},
+			}
+		},
+		partner.CoreVMOfferType: func(sku SKU) partner.Plan {
+			return partner.Plan{
+				ID: sku.ID,
+				PlanCoreVMDetail: partner.PlanCoreVMDetail{
+					SKUTitle:                  sku.Title,
+					SKUSummary:                sku.Summary,
+					SKULongSummary:            placeholder("SKU long summary"),
+					SKUDescriptionPublicAzure: placeholder("SKU description"),
+					CloudAvailability:         []partner.CloudAvailabilityOption{partner.PublicOption},
+					DeploymentModels:          []partner.DeploymentModelOption{partner.ARMDeploymentOption},
+					OperatingSystemFamily:     placeholder("Linux or Windows"),
+					OSType:                    placeholder("operating system type, for example Ubuntu"),
+					SmallLogo:                 placeholder("https URL of the 48x48 PNG small logo"),
+					MediumLogo:                placeholder("https URL of the 90x90 PNG medium logo"),
+					LargeLogo:                 placeholder("https URL of the 216x216 PNG large logo"),
+					WideLogo:                  placeholder("https URL of the 255x115 PNG wide logo"),
+				},
+			}
+		},
+		partner.ApplicationOfferType: func(sku SKU) partner.Plan {
+			return partner.Plan{
+				ID: sku.ID,
+				PlanApplicationDetail: partner.PlanApplicationDetail{
+					SKUTitle:          sku.Title,
+					SKUSummary:        sku.Summary,
+					SKUDescription:    placeholder("SKU description"),
+					SolutionType:      partner.SolutionTemplateType,
+					CloudAvailability: []string{string(partner.PublicOption)},
+				},
+			}
+		},
+		partner.ContainerOfferType: func(sku SKU) partner.Plan {
+			return partner.Plan{
+				ID: sku.ID,
+				PlanContainerDetail: partner.PlanContainerDetail{
+					SKUTitle:        sku.Title,
+					SKUSummary:      sku.Summary,
+					SKULongSummary:  placeholder("SKU long summary"),
+					ImageRepository: placeholder("container image repository"),
+				},
+			}
+		},
+		partner.SaaSOfferType: func(sku SKU) partner.Plan {
+			return partner.Plan{
+				ID: sku.ID,
+				PlanSaaSDetail: partner.PlanSaaSDetail{
+					SKUTitle:       sku.Title,
+					SKUSummary:     sku.Summary,
+					SKUDescription: placeholder("SKU description"),
+				},
+			}
+		},
+	}
+)
+
+// TypeIDs returns the offer type IDs which can be scaffolded
+func TypeIDs() []string {
+	ids := make([]string, 0, len(planSkeletons))
+	for id := range planSkeletons {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ResolveTypeID returns the offer type ID for a full ID like microsoft-azure-corevm or a short name like corevm
+func ResolveTypeID(name string) (string, error) {
+	for _, id := range TypeIDs() {
+		if name == id || name == strings.TrimPrefix(id, "microsoft-azure-") {
+			return id, nil
+		}
+	}
+
+	names := make([]string, len(TypeIDs()))
+	for i, id := range TypeIDs() {
+		names[i] = strings.TrimPrefix(id, "microsoft-azure-")
+	}
+	return "", fmt.Errorf("offer type %q must be one of %s", name, strings.Join(names, ", "))
+}
+
+// NewOffer returns a skeleton offer of the type with the given details and placeholders for the rest
+func NewOffer(opts Options) (*partner.Offer, error) {
+	newPlan, ok := planSkeletons[opts.TypeID]
+	if !ok {
+		return nil, &partner.UnsupportedOfferTypeError{TypeID: opts.TypeID}
+	}
+
+	offerType, err := partner.GetOfferType(opts.TypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	offer := &partner.Offer{
+		Entity:      partner.Entity{ID: orPlaceholder(opts.OfferID, "offer-id")},
+		TypeID:      opts.TypeID,
+		PublisherID: orPlaceholder(opts.PublisherID, "publisher-id"),
+		Definition: partner.OfferDefinition{
+			DisplayText: orPlaceholder(opts.Title, "offer title"),
+			OfferDetail: new(partner.OfferDetail),
+		},
+	}
+
+	offerType.SetTitle(offer, orPlaceholder(opts.Title, "offer title"))
+	offerType.SetSummary(offer, orPlaceholder(opts.Summary, "offer summary"))
+	offerType.SetDescription(offer, orPlaceholder(opts.Description, "offer description"))
+
+	if HasMarketplaceDetail(opts.TypeID) {
+		md := &offer.Definition.OfferDetail.MarketplaceDetail
+		md.SmallLogo = placeholder("https URL of the 48x48 PNG small logo")
+		md.MediumLogo = placeholder("https URL of the 90x90 PNG medium logo")
+		md.WideLogo = placeholder("https URL of the 255x115 PNG wide logo")
+		md.PrivacyURL = placeholder("https URL of the privacy policy")
+		md.TermsOfUse = placeholder("terms of use")
+		md.SupportContactName = orPlaceholder(opts.SupportContact.Name, "support contact name")
+		md.SupportContactEmail = orPlaceholder(opts.SupportContact.Email, "support contact email")
+		md.SupportContactPhone = orPlaceholder(opts.SupportContact.Phone, "support contact phone")
+		md.EngineeringContactName = orPlaceholder(opts.EngineeringContact.Name, "engineering contact name")
+		md.EngineeringContactEmail = orPlaceholder(opts.EngineeringContact.Email, "engineering contact email")
+		md.EngineeringContactPhone = orPlaceholder(opts.EngineeringContact.Phone, "engineering contact phone")
+	}
+
+	offer.Definition.Plans = []partner.Plan{newPlan(SKU{
+		ID:      orPlaceholder(opts.SKU.ID, "sku-id"),
+		Title:   orPlaceholder(opts.SKU.Title, "SKU title"),
+		Summary: orPlaceholder(opts.SKU.Summary, "SKU summary"),
+	})}
+	return offer, nil
+}
+
+// HasMarketplaceDetail returns true if offers of the type have the marketplace details, such as the logos and the
+// support and engineering contacts
+func HasMarketplaceDetail(typeID string) bool {
+	return typeID != partner.CoreVMOfferType
+}
+
+func placeholder(what string) string {
+	return fmt.Sprintf("%s: %s", Placeholder, what)
+}
+
+// orPlaceholder returns the value if it is set, otherwise a placeholder. IDs use a dashed placeholder, since the
+// Cloud Partner Portal only allows lowercase letters, digits and dashes in them.
+func orPlaceholder(value, what string) string {
+	switch {
+	case value != "":
+		return value
+	case strings.HasSuffix(what, "-id"):
+		return strings.ToLower(Placeholder) + "-" + what
+	default:
+		return placeholder(what)
+	}
+}
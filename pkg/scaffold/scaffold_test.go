@@ -0,0 +1,66 @@
+package scaffold
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/pkg/partner"
+)
+
+func TestResolveTypeID(t *testing.T) {
+	id, err := ResolveTypeID("corevm")
+	require.NoError(t, err)
+	assert.Equal(t, partner.CoreVMOfferType, id)
+
+	id, err = ResolveTypeID(partner.VirtualMachineOfferType)
+	require.NoError(t, err)
+	assert.Equal(t, partner.VirtualMachineOfferType, id)
+
+	_, err = ResolveTypeID("unknown")
+	assert.Error(t, err)
+}
+
+func TestNewOffer_Placeholders(t *testing.T) {
+	for _, id := range TypeIDs() {
+		offer, err := NewOffer(Options{TypeID: id})
+		require.NoError(t, err, id)
+		assert.Equal(t, id, offer.TypeID)
+		assert.Equal(t, "todo-offer-id", offer.ID)
+		assert.Equal(t, "todo-publisher-id", offer.PublisherID)
+		require.Len(t, offer.Definition.Plans, 1, id)
+		assert.Equal(t, "todo-sku-id", offer.Definition.Plans[0].ID)
+		assert.True(t, strings.HasPrefix(offer.Definition.DisplayText, Placeholder), id)
+	}
+}
+
+func TestNewOffer_Options(t *testing.T) {
+	offer, err := NewOffer(Options{
+		TypeID:         partner.VirtualMachineOfferType,
+		PublisherID:    "contoso",
+		OfferID:        "widget",
+		Title:          "Widget",
+		SupportContact: Contact{Email: "support@contoso.com"},
+		SKU:            SKU{ID: "widget-basic", Title: "Widget Basic"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "widget", offer.ID)
+	assert.Equal(t, "contoso", offer.PublisherID)
+	assert.Equal(t, "Widget", offer.Definition.DisplayText)
+
+	md := offer.Definition.OfferDetail.MarketplaceDetail
+	assert.Equal(t, "support@contoso.com", md.SupportContactEmail)
+	assert.Equal(t, "TODO: support contact name", md.SupportContactName)
+
+	plan := offer.Definition.Plans[0]
+	assert.Equal(t, "widget-basic", plan.ID)
+	assert.Equal(t, "Widget Basic", plan.PlanVirtualMachineDetail.SKUTitle)
+	assert.Equal(t, "TODO: SKU summary", plan.PlanVirtualMachineDetail.SKUSummary)
+}
+
+func TestNewOffer_UnsupportedType(t *testing.T) {
+	_, err := NewOffer(Options{TypeID: "unknown"})
+	assert.Error(t, err)
+}
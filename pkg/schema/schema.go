@@ -0,0 +1,264 @@
+// Package schema generates JSON Schemas from the partner types for editor autocompletion, and validates offer and SKU
+// files against them so mistakes are reported with the line and column where they were made.
+//
+// Schemas are generated by reflection from the json tags of the types. Embedded structs, like the plan details of each
+// offer type, are flattened into their parent, and other structs are shared through definitions. Properties which are
+// not in the schema are allowed, since the Cloud Partner Portal returns fields which are not modeled.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/date"
+
+	"github.com/devigned/pub/pkg/partner"
+)
+
+// Draft is the JSON Schema version of the generated schemas
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+type (
+	// Schema is a JSON Schema
+	Schema struct {
+		Schema               string             `json:"$schema,omitempty"`
+		Ref                  string             `json:"$ref,omitempty"`
+		Title                string             `json:"title,omitempty"`
+		Type                 Types              `json:"type,omitempty"`
+		Format               string             `json:"format,omitempty"`
+		Enum                 []interface{}      `json:"enum,omitempty"`
+		Properties           map[string]*Schema `json:"properties,omitempty"`
+		AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+		Items                *Schema            `json:"items,omitempty"`
+		AnyOf                []*Schema          `json:"anyOf,omitempty"`
+		Definitions          map[string]*Schema `json:"definitions,omitempty"`
+
+		// Extensions are keys of the object which the Cloud Partner Portal accepts, but which are not modeled
+		Extensions []string `json:"-"`
+	}
+
+	// Types are the JSON types allowed for a value, which marshal as a single string when there is only one
+	Types []string
+
+	generator struct {
+		definitions map[string]*Schema
+	}
+)
+
+var (
+	// types are the partner types which have schemas, by the name used to print them
+	types = map[string]reflect.Type{
+		"offer":                 reflect.TypeOf(partner.Offer{}),
+		"plan":                  reflect.TypeOf(partner.Plan{}),
+		"publisher":             reflect.TypeOf(partner.Publisher{}),
+		"virtualmachineimage":   reflect.TypeOf(partner.VirtualMachineImage{}),
+		"virtualmachinepricing": reflect.TypeOf(partner.VirtualMachinePricing{}),
+		"applicationpackage":    reflect.TypeOf(partner.ApplicationPackage{}),
+		"saaspricing":           reflect.TypeOf(partner.SaaSPricing{}),
+	}
+
+	// enums are the allowed values of the partner string types with a constrained set of values
+	enums = map[reflect.Type][]interface{}{
+		reflect.TypeOf(partner.DeploymentModelOption("")): {
+			partner.ARMDeploymentOption,
+			partner.RDFEDeploymentOption,
+		},
+		reflect.TypeOf(partner.CloudAvailabilityOption("")): {
+			partner.PublicOption,
+			partner.ChinaOption,
+			partner.GovCloud,
+			partner.Blackforest,
+		},
+		reflect.TypeOf(partner.ApplicationSolutionType("")): {
+			partner.SolutionTemplateType,
+			partner.ManagedApplicationType,
+		},
+	}
+
+	// extensions are the types with the keys which the Cloud Partner Portal accepts in an object, but which are not
+	// modeled by the partner type of the object, so they are dropped when a file is read
+	extensions = map[reflect.Type]reflect.Type{
+		reflect.TypeOf(partner.OfferDetail{}): reflect.TypeOf(partner.TestDriveDetail{}),
+	}
+
+	// formats are the types which marshal as strings in a JSON Schema format
+	formats = map[reflect.Type]string{
+		reflect.TypeOf(date.Time{}): "date-time",
+	}
+)
+
+// Names returns the names of the types which have schemas
+func Names() []string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ForName returns the schema of the partner type with the name, ignoring case, such as offer or VirtualMachineImage
+func ForName(name string) (*Schema, error) {
+	t, ok := types[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("there is no schema for %q; it must be one of %s", name, strings.Join(Names(), ", "))
+	}
+	return For(t), nil
+}
+
+// Offer returns the schema of an offer file
+func Offer() *Schema {
+	return For(types["offer"])
+}
+
+// Plan returns the schema of a SKU file
+func Plan() *Schema {
+	return For(types["plan"])
+}
+
+// For generates the schema of a struct type. The structs it refers to are in the definitions of the schema.
+func For(t reflect.Type) *Schema {
+	g := generator{definitions: make(map[string]*Schema)}
+	s := g.object(t)
+	s.Schema = Draft
+	s.Title = t.Name()
+	if len(g.definitions) > 0 {
+		s.Definitions = g.definitions
+	}
+	return s
+}
+
+// MarshalJSON writes a single type as a string and several as an array
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON reads a type written as a string or an array
+func (t *Types) UnmarshalJSON(bits []byte) error {
+	var single string
+	if err := json.Unmarshal(bits, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	return json.Unmarshal(bits, (*[]string)(t))
+}
+
+// Allows returns true if the types are not constrained or include the type
+func (t Types) Allows(name string) bool {
+	if len(t) == 0 {
+		return true
+	}
+
+	for _, allowed := range t {
+		if allowed == name || (allowed == "number" && name == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve returns the definition a schema refers to, or the schema if it is not a reference
+func (s *Schema) resolve(root *Schema) *Schema {
+	if s.Ref == "" {
+		return s
+	}
+
+	if def, ok := root.Definitions[strings.TrimPrefix(s.Ref, "#/definitions/")]; ok {
+		return def
+	}
+	return root
+}
+
+func (g generator) schema(t reflect.Type) *Schema {
+	if format, ok := formats[t]; ok {
+		return &Schema{Type: Types{"string"}, Format: format}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(g.schema(t.Elem()))
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}, Enum: enums[t]}
+	case reflect.Slice, reflect.Array:
+		return nullable(&Schema{Type: Types{"array"}, Items: g.schema(t.Elem())})
+	case reflect.Map:
+		return nullable(&Schema{Type: Types{"object"}, AdditionalProperties: g.schema(t.Elem())})
+	case reflect.Struct:
+		if _, ok := g.definitions[t.Name()]; !ok {
+			// reserve the name first, so a type which refers to itself does not recurse forever
+			g.definitions[t.Name()] = &Schema{}
+			*g.definitions[t.Name()] = *g.object(t)
+		}
+		return &Schema{Ref: "#/definitions/" + t.Name()}
+	default:
+		return &Schema{}
+	}
+}
+
+// object returns the schema of a struct, with the fields of embedded structs flattened into it
+func (g generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: Types{"object"}, Properties: make(map[string]*Schema)}
+	g.addFields(s, t)
+
+	if ext, ok := extensions[t]; ok {
+		keys := &Schema{Properties: make(map[string]*Schema)}
+		g.addFields(keys, ext)
+		for key := range keys.Properties {
+			s.Extensions = append(s.Extensions, key)
+		}
+		sort.Strings(s.Extensions)
+	}
+	return s
+}
+
+func (g generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addFields(s, field.Type)
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = g.schema(field.Type)
+	}
+}
+
+// nullable allows null for a value which json.Unmarshal leaves unset, like a pointer, slice or map
+func nullable(s *Schema) *Schema {
+	switch {
+	case s.Ref != "":
+		return &Schema{AnyOf: []*Schema{s, {Type: Types{"null"}}}}
+	case len(s.Type) == 0:
+		return s
+	}
+	s.Type = append(s.Type, "null")
+	return s
+}
@@ -0,0 +1,73 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForName(t *testing.T) {
+	s, err := ForName("VirtualMachineImage")
+	require.NoError(t, err)
+	assert.Equal(t, "VirtualMachineImage", s.Title)
+	assert.Equal(t, Types{"boolean", "null"}, s.Properties["showInGui"].Type)
+
+	_, err = ForName("unknown")
+	assert.Error(t, err)
+}
+
+func TestPlan_FlattensEmbeddedDetails(t *testing.T) {
+	s := Plan()
+	assert.Equal(t, Draft, s.Schema)
+	assert.Contains(t, s.Properties, "planId")
+	assert.Contains(t, s.Properties, "microsoft-azure-virtualmachines.skuTitle")
+	assert.Contains(t, s.Properties, "microsoft-azure-corevm.skuTitle")
+	assert.NotContains(t, s.Properties, "PlanVirtualMachineDetail")
+
+	images := s.Properties["microsoft-azure-corevm.vmImagesPublicAzure"]
+	assert.Equal(t, "#/definitions/VirtualMachineImage", images.AdditionalProperties.Ref)
+	assert.Contains(t, s.Definitions, "VirtualMachineImage")
+}
+
+func TestPlan_Enums(t *testing.T) {
+	s := Plan()
+	models := s.Properties["microsoft-azure-corevm.deploymentModels"].Items
+	bits, err := json.Marshal(models)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "string", "enum": ["ARM", "RDFE"]}`, string(bits))
+
+	clouds := s.Properties["microsoft-azure-corevm.cloudAvailability"].Items
+	bits, err = json.Marshal(clouds.Enum)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["PublicAzure", "Mooncake", "Fairfax", "Blackforest"]`, string(bits))
+}
+
+func TestOffer_Definitions(t *testing.T) {
+	s := Offer()
+	assert.Equal(t, Types{"string"}, s.Properties["id"].Type)
+	assert.Equal(t, "date-time", s.Properties["changedTime"].Format)
+
+	definition := s.Properties["definition"]
+	require.Equal(t, "#/definitions/OfferDefinition", definition.Ref)
+	plans := s.Definitions["OfferDefinition"].Properties["plans"]
+	assert.Equal(t, "#/definitions/Plan", plans.Items.Ref)
+	assert.Contains(t, s.Definitions["Plan"].Properties, "microsoft-azure-saas.skuTitle")
+}
+
+func TestTypes_JSON(t *testing.T) {
+	bits, err := json.Marshal(Types{"string"})
+	require.NoError(t, err)
+	assert.Equal(t, `"string"`, string(bits))
+
+	bits, err = json.Marshal(Types{"boolean", "null"})
+	require.NoError(t, err)
+	assert.Equal(t, `["boolean","null"]`, string(bits))
+
+	var types Types
+	require.NoError(t, json.Unmarshal([]byte(`"string"`), &types))
+	assert.Equal(t, Types{"string"}, types)
+	require.NoError(t, json.Unmarshal([]byte(`["array","null"]`), &types))
+	assert.Equal(t, Types{"array", "null"}, types)
+}
@@ -0,0 +1,274 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/devigned/pub/pkg/document"
+	"github.com/devigned/pub/pkg/jsonpath"
+)
+
+type (
+	// ValidationError is a value which does not match the schema
+	ValidationError struct {
+		// Path is the JSONPath of the value
+		Path    string
+		Message string
+		// File is the file of the value, when it was validated with ValidateFile
+		File string
+		// Document is the index of the document in a file with several YAML documents
+		Document int
+		// Position is the location of the value in its file, when it was validated with ValidateFile
+		Position document.Position
+
+		keys []interface{}
+	}
+
+	// FileError is returned for a file with values which do not match the schema
+	FileError struct {
+		Path   string
+		Errors []ValidationError
+	}
+
+	// Validator validates files against a schema
+	Validator struct {
+		Schema *Schema
+		// Strict reports keys which are not in the schema as errors, with a suggestion of the key which was probably
+		// meant. Keys of the Cloud Partner Portal which are not modeled are reported as warnings.
+		Strict bool
+		// Warn is called with each warning, if it is set
+		Warn func(ValidationError)
+	}
+
+	// validation is the state of validating a single document
+	validation struct {
+		root     *Schema
+		strict   bool
+		errs     []ValidationError
+		warnings []ValidationError
+	}
+)
+
+func (e ValidationError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("%s: %s", e.Path, e.Message)
+	}
+
+	name := e.File
+	if name == document.Stdin {
+		name = "stdin"
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", name, e.Position.Line, e.Position.Column, e.Path, e.Message)
+}
+
+func (e *FileError) Error() string {
+	name := e.Path
+	if name == document.Stdin {
+		name = "stdin"
+	}
+
+	lines := make([]string, len(e.Errors))
+	for i, ve := range e.Errors {
+		lines[i] = ve.Error()
+	}
+	return fmt.Sprintf("%s does not match the schema:\n%s", name, strings.Join(lines, "\n"))
+}
+
+// ValidateFile validates each document in the JSON or YAML content of the file at path against the schema, and returns
+// a *FileError with the line and column of each value which does not match, in the order they appear in the file
+func ValidateFile(s *Schema, path string, bits []byte) error {
+	return Validator{Schema: s}.ValidateFile(path, bits)
+}
+
+// ValidateFile validates each document in the JSON or YAML content of the file at path, and returns a *FileError with
+// the line and column of each value which does not match, in the order they appear in the file
+func (v Validator) ValidateFile(path string, bits []byte) error {
+	docs, err := document.ToJSON(path, bits)
+	if err != nil {
+		return err
+	}
+
+	var errs, warnings []ValidationError
+	for i, docBits := range docs {
+		decoder := json.NewDecoder(bytes.NewReader(docBits))
+		decoder.UseNumber()
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			return fmt.Errorf("unable to read %s: %v", path, err)
+		}
+
+		val := &validation{root: v.Schema, strict: v.Strict}
+		val.validate(v.Schema, doc, "$", nil)
+		errs = append(errs, locate(val.errs, path, bits, i)...)
+		warnings = append(warnings, locate(val.warnings, path, bits, i)...)
+	}
+
+	if v.Warn != nil {
+		for _, w := range warnings {
+			v.Warn(w)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &FileError{Path: path, Errors: errs}
+	}
+	return nil
+}
+
+// Validate returns the values in the decoded JSON document which do not match the schema. Numbers may be float64 or
+// json.Number.
+func (s *Schema) Validate(doc interface{}) []ValidationError {
+	val := &validation{root: s}
+	val.validate(s, doc, "$", nil)
+	return val.errs
+}
+
+// locate sets the file and position of the errors found in the document with the index, and sorts them by position
+func locate(errs []ValidationError, path string, bits []byte, index int) []ValidationError {
+	for i := range errs {
+		errs[i].File = path
+		errs[i].Document = index
+		errs[i].Position = document.Locate(path, bits, index, errs[i].keys)
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		a, b := errs[i].Position, errs[j].Position
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return errs
+}
+
+func (v *validation) validate(s *Schema, value interface{}, path string, keys []interface{}) {
+	s = s.resolve(v.root)
+	if len(s.AnyOf) > 0 {
+		// report the errors of the first option, which is the value that is not null
+		var first *validation
+		for i, option := range s.AnyOf {
+			optionVal := &validation{root: v.root, strict: v.strict}
+			optionVal.validate(option, value, path, keys)
+			if len(optionVal.errs) == 0 {
+				v.warnings = append(v.warnings, optionVal.warnings...)
+				return
+			}
+
+			if i == 0 {
+				first = optionVal
+			}
+		}
+		v.errs = append(v.errs, first.errs...)
+		v.warnings = append(v.warnings, first.warnings...)
+		return
+	}
+
+	fail := func(format string, args ...interface{}) {
+		v.errs = append(v.errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...), keys: keys})
+	}
+
+	if t := typeOf(value); !s.Type.Allows(t) {
+		fail("expected %s, but got %s", strings.Join(s.Type, " or "), t)
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		allowed := make([]string, len(s.Enum))
+		for i, e := range s.Enum {
+			allowed[i] = fmt.Sprint(e)
+		}
+		fail("must be one of %s, but is %v", strings.Join(allowed, ", "), value)
+		return
+	}
+
+	switch t := value.(type) {
+	case map[string]interface{}:
+		names := make([]string, 0, len(t))
+		for name := range t {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			childPath, childKeys := path+jsonpath.Child(name), append(keys[:len(keys):len(keys)], name)
+			child, ok := s.Properties[name]
+			if !ok {
+				child = s.AdditionalProperties
+			}
+
+			if child != nil {
+				v.validate(child, t[name], childPath, childKeys)
+				continue
+			}
+
+			if v.strict && len(s.Properties) > 0 {
+				v.unknown(s, name, childPath, childKeys)
+			}
+		}
+	case []interface{}:
+		if s.Items == nil {
+			return
+		}
+
+		for i, item := range t {
+			v.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i), append(keys[:len(keys):len(keys)], i))
+		}
+	}
+}
+
+// unknown reports a key which is not a property of the object schema
+func (v *validation) unknown(s *Schema, name, path string, keys []interface{}) {
+	for _, ext := range s.Extensions {
+		if name == ext {
+			v.warnings = append(v.warnings, ValidationError{
+				Path:    path,
+				Message: "is a Cloud Partner Portal key which is not supported, so it will not be put",
+				keys:    keys,
+			})
+			return
+		}
+	}
+
+	message := "is not a known key"
+	if suggestion := suggest(name, s.Properties); suggestion != "" {
+		message = fmt.Sprintf("is not a known key; did you mean %s?", suggestion)
+	}
+	v.errs = append(v.errs, ValidationError{Path: path, Message: message, keys: keys})
+}
+
+// typeOf returns the JSON Schema type of a decoded JSON value
+func typeOf(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case float64:
+		if t == float64(int64(t)) {
+			return "integer"
+		}
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
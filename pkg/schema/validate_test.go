@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/pkg/document"
+)
+
+func TestValidate(t *testing.T) {
+	doc := map[string]interface{}{
+		"planId":  "basic",
+		"regions": []interface{}{"us", 1.0},
+		"microsoft-azure-saas.pricing": map[string]interface{}{
+			"price":    "free",
+			"minUsers": 1.5,
+		},
+		"microsoft-azure-virtualmachines.hideSKUForSolutionTemplate": nil,
+		"unknown": true,
+	}
+
+	errs := Plan().Validate(doc)
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	assert.ElementsMatch(t, []string{
+		"$.regions[1]: expected string, but got integer",
+		"$['microsoft-azure-saas.pricing'].price: expected number or null, but got string",
+		"$['microsoft-azure-saas.pricing'].minUsers: expected integer or null, but got number",
+	}, messages)
+}
+
+func TestValidateFile_YAML(t *testing.T) {
+	content := `# SKUs
+planId: one
+microsoft-azure-corevm.deploymentModels: [ARM]
+---
+planId: two
+microsoft-azure-corevm.deploymentModels:
+- ARM
+- Classic
+microsoft-azure-corevm.vmImagesPublicAzure:
+  "1.0":
+    showInGui: sometimes
+`
+	err := ValidateFile(Plan(), "skus.yaml", []byte(content))
+	require.Error(t, err)
+
+	fileErr, ok := err.(*FileError)
+	require.True(t, ok)
+	require.Len(t, fileErr.Errors, 2)
+	assert.Equal(t, 1, fileErr.Errors[0].Document)
+	assert.Equal(t, document.Position{Line: 8, Column: 1}, fileErr.Errors[0].Position)
+	assert.Equal(t, document.Position{Line: 11, Column: 5}, fileErr.Errors[1].Position)
+	assert.Contains(t, err.Error(), "skus.yaml:8:1: $['microsoft-azure-corevm.deploymentModels'][1]: must be one of ARM, RDFE, but is Classic")
+}
+
+func TestValidateFile_JSON(t *testing.T) {
+	content := `{
+  "id": "widget",
+  "definition": {
+    "plans": [
+      {"planId": "one"},
+      {"planId": 2}
+    ]
+  }
+}`
+	err := ValidateFile(Offer(), document.Stdin, []byte(content))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stdin:6:8: $.definition.plans[1].planId: expected string, but got integer")
+}
+
+func TestValidateFile_Valid(t *testing.T) {
+	assert.NoError(t, ValidateFile(Offer(), "offer.yaml", []byte("id: widget\ndefinition:\n  offer:\n  plans:\n  - planId: one\n")))
+}
+
+func TestValidator_Strict(t *testing.T) {
+	content := `id: widget
+definition:
+  offer:
+    microsoft-azure-marketplace-testdrive.enabled: true
+    microsoft-azure-marketplace.titel: Widget
+  plans:
+  - planId: basic
+    microsoft-azure-virtualmachine.skuTitle: Basic
+    whatever: 1
+    microsoft-azure-virtualmachines.vmImages:
+      "1.0":
+        mediaName: image
+`
+	var warnings []ValidationError
+	v := Validator{Schema: Offer(), Strict: true, Warn: func(w ValidationError) {
+		warnings = append(warnings, w)
+	}}
+
+	err := v.ValidateFile("offer.yaml", []byte(content))
+	require.Error(t, err)
+
+	fileErr, ok := err.(*FileError)
+	require.True(t, ok)
+	messages := make([]string, len(fileErr.Errors))
+	for i, ve := range fileErr.Errors {
+		messages[i] = ve.Error()
+	}
+	assert.Equal(t, []string{
+		"offer.yaml:5:5: $.definition.offer['microsoft-azure-marketplace.titel']: is not a known key; did you mean microsoft-azure-marketplace.title?",
+		"offer.yaml:8:5: $.definition.plans[0]['microsoft-azure-virtualmachine.skuTitle']: is not a known key; did you mean microsoft-azure-virtualmachines.skuTitle?",
+		"offer.yaml:9:5: $.definition.plans[0].whatever: is not a known key",
+	}, messages)
+
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "$.definition.offer['microsoft-azure-marketplace-testdrive.enabled']", warnings[0].Path)
+	assert.Equal(t, document.Position{Line: 4, Column: 5}, warnings[0].Position)
+}
+
+func TestValidator_NotStrict(t *testing.T) {
+	v := Validator{Schema: Plan()}
+	assert.NoError(t, v.ValidateFile("sku.json", []byte(`{"planId": "basic", "planID": "typo"}`)))
+}
+
+func TestSuggest(t *testing.T) {
+	properties := Plan().Properties
+	assert.Equal(t, "planId", suggest("PlanID", properties))
+	assert.Equal(t, "regions", suggest("region", properties))
+	assert.Equal(t, "microsoft-azure-corevm.skuTitle", suggest("microsoft-azure-corevm.skuTitel", properties))
+	assert.Empty(t, suggest("somethingElse", properties))
+}
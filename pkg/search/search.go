@@ -0,0 +1,195 @@
+// Package search answers questions across many offers, such as which plans still use an image version or which
+// offers list a support email, by evaluating a query over each offer.
+//
+// A query is a JSONPath expression, as supported by the jsonpath package, which selects values from each offer. The
+// selected values may be filtered by a regular expression over the value and by a regular expression over the image
+// version the value is in. Each result is reported with the offer, plan and image version it was found in.
+//
+//	$.definition.plans[*]['microsoft-azure-virtualmachines.vmImages'].*
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/devigned/pub/pkg/jsonpath"
+	"github.com/devigned/pub/pkg/parallel"
+	"github.com/devigned/pub/pkg/partner"
+)
+
+type (
+	// Query selects values from offers and filters them
+	Query struct {
+		Path *jsonpath.Path
+		// Regex, if set, must match the value. Strings are matched as is and other values as JSON.
+		Regex *regexp.Regexp
+		// Version, if set, must match the image version the value is in
+		Version *regexp.Regexp
+	}
+
+	// Result is a value selected from an offer, with the plan and image version it is in, if any
+	Result struct {
+		PublisherID string      `json:"publisherId"`
+		OfferID     string      `json:"offerId"`
+		PlanID      string      `json:"planId,omitempty"`
+		Version     string      `json:"version,omitempty"`
+		Path        string      `json:"path"`
+		Value       interface{} `json:"value"`
+	}
+
+	// Client is the part of the Cloud Partner Portal client used to load offers
+	Client interface {
+		ListPublishers(ctx context.Context) ([]partner.Publisher, error)
+		ListOffers(ctx context.Context, params partner.ListOffersParams) ([]partner.Offer, error)
+		GetOffer(ctx context.Context, params partner.ShowOfferParams) (*partner.Offer, error)
+	}
+)
+
+// NewQuery parses the JSONPath expression and the optional value and version regular expressions
+func NewQuery(expr, valueRegex, versionRegex string) (*Query, error) {
+	p, err := jsonpath.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{Path: p}
+	if valueRegex != "" {
+		if q.Regex, err = regexp.Compile(valueRegex); err != nil {
+			return nil, fmt.Errorf("invalid value regex: %v", err)
+		}
+	}
+
+	if versionRegex != "" {
+		if q.Version, err = regexp.Compile(versionRegex); err != nil {
+			return nil, fmt.Errorf("invalid version regex: %v", err)
+		}
+	}
+	return q, nil
+}
+
+// Run evaluates the query over each offer, and returns the results in the order of the offers
+func (q *Query) Run(offers []*partner.Offer) ([]Result, error) {
+	results := []Result{}
+	for _, offer := range offers {
+		offerResults, err := q.Evaluate(offer)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, offerResults...)
+	}
+	return results, nil
+}
+
+// Evaluate returns the values selected from the offer which match the filters of the query
+func (q *Query) Evaluate(offer *partner.Offer) ([]Result, error) {
+	doc, err := jsonpath.ToDocument(offer)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, m := range q.Path.Select(doc) {
+		r := Result{PublisherID: offer.PublisherID, OfferID: offer.ID, Path: m.Path, Value: m.Value}
+		if p, err := jsonpath.Parse(m.Path); err == nil {
+			r.PlanID, r.Version = locate(offer, p.Selectors)
+		}
+
+		if q.Version != nil && (r.Version == "" || !q.Version.MatchString(r.Version)) {
+			continue
+		}
+
+		if q.Regex != nil && !q.Regex.MatchString(toString(m.Value)) {
+			continue
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// Load fetches the draft of every offer of the publishers, or of every publisher if none are given, with at most
+// limit offers fetched at once. The offers are sorted by publisher and offer ID.
+func Load(ctx context.Context, client Client, publisherIDs []string, limit int) ([]*partner.Offer, error) {
+	if len(publisherIDs) == 0 {
+		publishers, err := client.ListPublishers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list publishers: %v", err)
+		}
+
+		for _, p := range publishers {
+			publisherIDs = append(publisherIDs, p.ID)
+		}
+	}
+
+	var listed []partner.ShowOfferParams
+	for _, publisherID := range publisherIDs {
+		offers, err := client.ListOffers(ctx, partner.ListOffersParams{PublisherID: publisherID})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list offers of %s: %v", publisherID, err)
+		}
+
+		for _, o := range offers {
+			listed = append(listed, partner.ShowOfferParams{PublisherID: publisherID, OfferID: o.ID})
+		}
+	}
+
+	offers := make([]*partner.Offer, len(listed))
+	err := parallel.ForEach(ctx, len(listed), limit, func(ctx context.Context, i int) error {
+		offer, err := client.GetOffer(ctx, listed[i])
+		if err != nil {
+			return fmt.Errorf("unable to get offer %s/%s: %v", listed[i].PublisherID, listed[i].OfferID, err)
+		}
+		offers[i] = offer
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	Sort(offers)
+	return offers, nil
+}
+
+// Sort sorts offers by publisher and offer ID
+func Sort(offers []*partner.Offer) {
+	sort.SliceStable(offers, func(i, j int) bool {
+		if offers[i].PublisherID != offers[j].PublisherID {
+			return offers[i].PublisherID < offers[j].PublisherID
+		}
+		return offers[i].ID < offers[j].ID
+	})
+}
+
+// locate returns the ID of the plan and the image version which the path of a value is in, if any
+func locate(offer *partner.Offer, selectors []jsonpath.Selector) (string, string) {
+	var planID, version string
+	if len(selectors) >= 3 && selectors[0].Name == "definition" && selectors[1].Name == "plans" && selectors[2].IsIndex {
+		if i := selectors[2].Index; i < len(offer.Definition.Plans) {
+			planID = offer.Definition.Plans[i].ID
+		}
+	}
+
+	for i := 0; i+1 < len(selectors); i++ {
+		next := selectors[i+1]
+		if strings.Contains(selectors[i].Name, ".vmImages") && !next.IsIndex && !next.Wildcard {
+			version = next.Name
+		}
+	}
+	return planID, version
+}
+
+// toString returns a string as is, and other values as JSON
+func toString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	bits, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(bits)
+}
@@ -0,0 +1,175 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/internal/test"
+	"github.com/devigned/pub/pkg/partner"
+	"github.com/devigned/pub/pkg/search"
+)
+
+const imagesQuery = "$.definition.plans[*]['microsoft-azure-virtualmachines.vmImages'].*.osVhdUrl"
+
+func TestQuery_Evaluate(t *testing.T) {
+	cases := map[string]struct {
+		expr     string
+		regex    string
+		version  string
+		expected []search.Result
+	}{
+		"images": {
+			expr: imagesQuery,
+			expected: []search.Result{
+				{
+					PublisherID: "publisherId",
+					OfferID:     "test",
+					PlanID:      "planId_one",
+					Version:     "2018.1.1",
+					Path:        "$.definition.plans[0]['microsoft-azure-virtualmachines.vmImages']['2018.1.1'].osVhdUrl",
+					Value:       "osVhdUrl_one",
+				},
+				{
+					PublisherID: "publisherId",
+					OfferID:     "test",
+					PlanID:      "planId_one",
+					Version:     "2019.10.11",
+					Path:        "$.definition.plans[0]['microsoft-azure-virtualmachines.vmImages']['2019.10.11'].osVhdUrl",
+					Value:       "osVhdUrl_two",
+				},
+			},
+		},
+		"version": {
+			expr:    imagesQuery,
+			version: `^2019\.`,
+			expected: []search.Result{
+				{
+					PublisherID: "publisherId",
+					OfferID:     "test",
+					PlanID:      "planId_one",
+					Version:     "2019.10.11",
+					Path:        "$.definition.plans[0]['microsoft-azure-virtualmachines.vmImages']['2019.10.11'].osVhdUrl",
+					Value:       "osVhdUrl_two",
+				},
+			},
+		},
+		"regex": {
+			expr:  "$.definition.offer['microsoft-azure-marketplace.supportContactEmail']",
+			regex: "^support",
+			expected: []search.Result{
+				{
+					PublisherID: "publisherId",
+					OfferID:     "test",
+					Path:        "$.definition.offer['microsoft-azure-marketplace.supportContactEmail']",
+					Value:       "supportContactEmail",
+				},
+			},
+		},
+		"regex over JSON": {
+			expr:  "$.definition.plans[*]['microsoft-azure-virtualmachines.hideSKUForSolutionTemplate']",
+			regex: "^true$",
+			expected: []search.Result{
+				{
+					PublisherID: "publisherId",
+					OfferID:     "test",
+					PlanID:      "planId_one",
+					Path:        "$.definition.plans[0]['microsoft-azure-virtualmachines.hideSKUForSolutionTemplate']",
+					Value:       true,
+				},
+			},
+		},
+		"no match": {
+			expr:  "$.definition.offer['microsoft-azure-marketplace.supportContactEmail']",
+			regex: "contoso",
+		},
+		"version outside of images": {
+			expr:    "$.definition.plans[*].planId",
+			version: ".*",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			q, err := search.NewQuery(c.expr, c.regex, c.version)
+			require.NoError(t, err)
+			results, err := q.Evaluate(test.NewMarketplaceVMOffer())
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, results)
+		})
+	}
+}
+
+func TestNewQuery_FailOnInvalidExpressions(t *testing.T) {
+	_, err := search.NewQuery("definition[", "", "")
+	assert.Error(t, err)
+	_, err = search.NewQuery("$.id", "(", "")
+	assert.Error(t, err)
+	_, err = search.NewQuery("$.id", "", "(")
+	assert.Error(t, err)
+}
+
+func TestQuery_Run(t *testing.T) {
+	q, err := search.NewQuery("$.id", "", "")
+	require.NoError(t, err)
+
+	results, err := q.Run(nil)
+	require.NoError(t, err)
+	assert.NotNil(t, results)
+	assert.Empty(t, results)
+
+	a, b := test.NewMarketplaceVMOffer(), test.NewMarketplaceVMOffer()
+	b.ID = "other"
+	results, err = q.Run([]*partner.Offer{a, b})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "test", results[0].Value)
+	assert.Equal(t, "other", results[1].Value)
+}
+
+func newOffer(publisherID, id string) *partner.Offer {
+	offer := test.NewMarketplaceVMOffer()
+	offer.PublisherID = publisherID
+	offer.ID = id
+	return offer
+}
+
+func TestLoad(t *testing.T) {
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("ListPublishers", mock.Anything).Return([]partner.Publisher{
+		{Entity: partner.Entity{ID: "b"}},
+		{Entity: partner.Entity{ID: "a"}},
+	}, nil)
+	svcMock.On("ListOffers", mock.Anything, partner.ListOffersParams{PublisherID: "a"}).Return([]partner.Offer{*newOffer("a", "two"), *newOffer("a", "one")}, nil)
+	svcMock.On("ListOffers", mock.Anything, partner.ListOffersParams{PublisherID: "b"}).Return([]partner.Offer{*newOffer("b", "one")}, nil)
+	for _, o := range []*partner.Offer{newOffer("a", "one"), newOffer("a", "two"), newOffer("b", "one")} {
+		svcMock.On("GetOffer", mock.Anything, partner.ShowOfferParams{PublisherID: o.PublisherID, OfferID: o.ID}).Return(o, nil)
+	}
+
+	offers, err := search.Load(context.Background(), svcMock, nil, 2)
+	require.NoError(t, err)
+	ids := make([]string, len(offers))
+	for i, o := range offers {
+		ids[i] = o.PublisherID + "/" + o.ID
+	}
+	assert.Equal(t, []string{"a/one", "a/two", "b/one"}, ids)
+
+	offers, err = search.Load(context.Background(), svcMock, []string{"b"}, 2)
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	assert.Equal(t, "b", offers[0].PublisherID)
+	svcMock.AssertNumberOfCalls(t, "ListPublishers", 1)
+}
+
+func TestLoad_FailOnGetOfferError(t *testing.T) {
+	svcMock := new(test.CloudPartnerServiceMock)
+	svcMock.On("ListOffers", mock.Anything, mock.Anything).Return([]partner.Offer{*newOffer("a", "one")}, nil)
+	svcMock.On("GetOffer", mock.Anything, mock.Anything).Return((*partner.Offer)(nil), errors.New("boom"))
+
+	_, err := search.Load(context.Background(), svcMock, []string{"a"}, 2)
+	assert.EqualError(t, err, "unable to get offer a/one: boom")
+}
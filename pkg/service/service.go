@@ -29,6 +29,7 @@ type (
 		GoLiveWithOffer(ctx context.Context, params partner.GoLiveParams) (string, error)
 		GetOfferStatus(ctx context.Context, params partner.ShowOfferParams) (*partner.OfferStatus, error)
 		PutOffer(ctx context.Context, offer *partner.Offer) (*partner.Offer, error)
+		PutOfferIfMatch(ctx context.Context, offer *partner.Offer) (*partner.Offer, error)
 		PublishOffer(ctx context.Context, params partner.PublishOfferParams) (string, error)
 
 		ListOperations(ctx context.Context, params partner.ListOperationsParams) ([]partner.Operation, error)
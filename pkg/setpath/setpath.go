@@ -0,0 +1,431 @@
+// Package setpath edits decoded JSON documents with the paths given to --set and --unset.
+//
+// A path is a sequence of names separated by dots, each of which may be followed by any number of bracket selectors:
+//
+//	[2]            the element at index 2 of an array; negative indexes count from the end
+//	[planId=foo]   the first element of an array of objects whose planId is foo, which must exist
+//	[]             a new element appended to an array
+//	['a.b']        a name which contains dots, brackets or quotes
+//
+// The Cloud Partner Portal uses keys which contain dots, such as "microsoft-azure-virtualmachines.skuTitle". These may
+// be written without quotes: when a run of dotted names matches an existing key, the longest matching key is used, and
+// when a key is created, a name containing a dash is joined with the name which follows it.
+//
+//	definition.plans[planId=foo].microsoft-azure-virtualmachines.skuTitle
+package setpath
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Path is a parsed --set path
+	Path struct {
+		Steps []Step
+	}
+
+	// Step is a single name or bracket selector in a path
+	Step struct {
+		Kind StepKind
+		// Name is the key for a name step
+		Name string
+		// Quoted is true if the name was given in bracket form and must not be joined with the names around it
+		Quoted bool
+		// Index is the array index for an index step
+		Index int
+		// Key and Value are the field and value an element must have for a match step
+		Key   string
+		Value string
+	}
+
+	// StepKind is the kind of a path step
+	StepKind int
+)
+
+const (
+	// NameStep selects a key of an object
+	NameStep StepKind = iota
+	// IndexStep selects an element of an array by index
+	IndexStep
+	// MatchStep selects the first element of an array of objects with a field equal to a value
+	MatchStep
+	// AppendStep appends a new element to an array
+	AppendStep
+)
+
+// Parse parses a path
+func Parse(expr string) (*Path, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, errors.New("path must not be empty")
+	}
+
+	p := new(Path)
+	rest := expr
+	expectName := true
+	for len(rest) > 0 {
+		switch {
+		case rest[0] == '[':
+			if expectName && len(p.Steps) > 0 {
+				return nil, fmt.Errorf("path %q has an empty name before %q", expr, rest)
+			}
+			step, n, err := parseBracket(rest)
+			if err != nil {
+				return nil, fmt.Errorf("path %q: %v", expr, err)
+			}
+			p.Steps = append(p.Steps, step)
+			rest = rest[n:]
+			expectName = false
+		case rest[0] == '.':
+			if expectName {
+				return nil, fmt.Errorf("path %q has an empty name at %q", expr, rest)
+			}
+			rest = rest[1:]
+			expectName = true
+			if rest == "" {
+				return nil, fmt.Errorf("path %q must not end with a dot", expr)
+			}
+		default:
+			if !expectName {
+				return nil, fmt.Errorf("path %q expected . or [ at %q", expr, rest)
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			p.Steps = append(p.Steps, Step{Kind: NameStep, Name: rest[:end]})
+			rest = rest[end:]
+			expectName = false
+		}
+	}
+	return p, nil
+}
+
+// ParseAssignment splits a path=value assignment on the first = which is not within brackets, so both selectors
+// like [planId=foo] and values which contain = are allowed
+func ParseAssignment(assignment string) (*Path, string, error) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(assignment); i++ {
+		c := assignment[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case depth > 0 && (c == '\'' || c == '"'):
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+		case c == '=' && depth == 0:
+			p, err := Parse(assignment[:i])
+			if err != nil {
+				return nil, "", err
+			}
+			return p, assignment[i+1:], nil
+		}
+	}
+	return nil, "", fmt.Errorf("%q is not in path=value format", assignment)
+}
+
+// ParseValue converts a --set value into a boolean, number or null if it is one of those JSON literals, otherwise
+// the value is a string
+func ParseValue(s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+
+	switch v.(type) {
+	case nil, bool, float64:
+		return v
+	default:
+		return s
+	}
+}
+
+// String returns the normalized form of the path
+func (p *Path) String() string {
+	var sb strings.Builder
+	for i, s := range p.Steps {
+		if s.Kind == NameStep && !s.Quoted {
+			if i > 0 {
+				sb.WriteByte('.')
+			}
+			sb.WriteString(s.Name)
+			continue
+		}
+		sb.WriteString(s.String())
+	}
+	return sb.String()
+}
+
+// String returns the bracket form of the step
+func (s Step) String() string {
+	switch s.Kind {
+	case IndexStep:
+		return fmt.Sprintf("[%d]", s.Index)
+	case MatchStep:
+		return fmt.Sprintf("[%s=%s]", s.Key, s.Value)
+	case AppendStep:
+		return "[]"
+	default:
+		return fmt.Sprintf("['%s']", strings.Replace(s.Name, "'", `\'`, -1))
+	}
+}
+
+// Set sets the value at the path, creating any missing objects and arrays along the way, and returns the updated
+// document. It is an error if a [field=value] selector matches no element, so a mistyped selector does not silently
+// add an element; use [] to append one.
+func Set(doc interface{}, p *Path, value interface{}) (interface{}, error) {
+	return set(doc, p.Steps, value, p)
+}
+
+// Unset removes the key or array element at the path and returns the updated document. It is an error if the path
+// does not exist.
+func Unset(doc interface{}, p *Path) (interface{}, error) {
+	return unset(doc, p.Steps, p)
+}
+
+func set(node interface{}, steps []Step, value interface{}, p *Path) (interface{}, error) {
+	if len(steps) == 0 {
+		return value, nil
+	}
+
+	step := steps[0]
+	if step.Kind == NameStep {
+		if node == nil {
+			node = make(map[string]interface{})
+		}
+
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unable to set %s: %s is not an object", p, pathBefore(p, steps))
+		}
+
+		key, n := resolveKey(obj, steps, true)
+		child, err := set(obj[key], steps[n:], value, p)
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = child
+		return obj, nil
+	}
+
+	if node == nil {
+		node = []interface{}{}
+	}
+
+	arr, ok := node.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unable to set %s: %s is not an array", p, pathBefore(p, steps))
+	}
+
+	idx, err := elementIndex(arr, step, p, steps)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case idx >= 0:
+	case step.Kind == MatchStep:
+		return nil, fmt.Errorf("unable to set %s: no element of %s has %s=%s; use [] to append an element", p, pathBefore(p, steps), step.Key, step.Value)
+	default:
+		arr = append(arr, nil)
+		idx = len(arr) - 1
+	}
+
+	child, err := set(arr[idx], steps[1:], value, p)
+	if err != nil {
+		return nil, err
+	}
+	arr[idx] = child
+	return arr, nil
+}
+
+func unset(node interface{}, steps []Step, p *Path) (interface{}, error) {
+	step := steps[0]
+	if step.Kind == NameStep {
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unable to unset %s: %s is not an object", p, pathBefore(p, steps))
+		}
+
+		key, n := resolveKey(obj, steps, false)
+		child, ok := obj[key]
+		if !ok {
+			return nil, fmt.Errorf("unable to unset %s: it does not exist", p)
+		}
+
+		if n == len(steps) {
+			delete(obj, key)
+			return obj, nil
+		}
+
+		updated, err := unset(child, steps[n:], p)
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = updated
+		return obj, nil
+	}
+
+	if step.Kind == AppendStep {
+		return nil, fmt.Errorf("unable to unset %s: [] may only be used to set a value", p)
+	}
+
+	arr, ok := node.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unable to unset %s: %s is not an array", p, pathBefore(p, steps))
+	}
+
+	idx, err := elementIndex(arr, step, p, steps)
+	if err != nil {
+		return nil, err
+	}
+
+	if idx < 0 {
+		return nil, fmt.Errorf("unable to unset %s: it does not exist", p)
+	}
+
+	if len(steps) == 1 {
+		return append(arr[:idx], arr[idx+1:]...), nil
+	}
+
+	updated, err := unset(arr[idx], steps[1:], p)
+	if err != nil {
+		return nil, err
+	}
+	arr[idx] = updated
+	return arr, nil
+}
+
+// resolveKey returns the key in obj for the leading name steps and the number of steps used. The longest run of
+// unquoted names which joins into an existing key wins. Otherwise, when creating, a name containing a dash is
+// joined with the name which follows it.
+func resolveKey(obj map[string]interface{}, steps []Step, creating bool) (string, int) {
+	if steps[0].Quoted {
+		return steps[0].Name, 1
+	}
+
+	run := 1
+	for run < len(steps) && steps[run].Kind == NameStep && !steps[run].Quoted {
+		run++
+	}
+
+	for n := run; n > 0; n-- {
+		key := joinNames(steps[:n])
+		if _, ok := obj[key]; ok {
+			return key, n
+		}
+	}
+
+	if creating && run > 1 && strings.Contains(steps[0].Name, "-") {
+		return joinNames(steps[:2]), 2
+	}
+	return steps[0].Name, 1
+}
+
+// elementIndex returns the index of the array element selected by step, or -1 if there is no such element
+func elementIndex(arr []interface{}, step Step, p *Path, steps []Step) (int, error) {
+	switch step.Kind {
+	case IndexStep:
+		idx := step.Index
+		if idx < 0 {
+			idx += len(arr)
+		}
+		if idx < 0 || idx >= len(arr) {
+			return 0, fmt.Errorf("index %d of %s is out of range for %d elements", step.Index, p, len(arr))
+		}
+		return idx, nil
+	case MatchStep:
+		for i, item := range arr {
+			if obj, ok := item.(map[string]interface{}); ok && scalarString(obj[step.Key]) == step.Value {
+				return i, nil
+			}
+		}
+	}
+	return -1, nil
+}
+
+func joinNames(steps []Step) string {
+	names := make([]string, len(steps))
+	for i, s := range steps {
+		names[i] = s.Name
+	}
+	return strings.Join(names, ".")
+}
+
+func pathBefore(p *Path, steps []Step) string {
+	prefix := &Path{Steps: p.Steps[:len(p.Steps)-len(steps)]}
+	if len(prefix.Steps) == 0 {
+		return "the document"
+	}
+	return prefix.String()
+}
+
+func scalarString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	default:
+		return ""
+	}
+}
+
+func parseBracket(s string) (Step, int, error) {
+	if len(s) >= 2 && (s[1] == '\'' || s[1] == '"') {
+		quote := s[1]
+		var sb strings.Builder
+		for i := 2; i < len(s); i++ {
+			switch {
+			case s[i] == '\\' && i+1 < len(s):
+				i++
+				sb.WriteByte(s[i])
+			case s[i] == quote:
+				if i+1 >= len(s) || s[i+1] != ']' {
+					return Step{}, 0, fmt.Errorf("expected ] after quoted name at %q", s)
+				}
+				return Step{Kind: NameStep, Name: sb.String(), Quoted: true}, i + 2, nil
+			default:
+				sb.WriteByte(s[i])
+			}
+		}
+		return Step{}, 0, fmt.Errorf("unterminated quoted name at %q", s)
+	}
+
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return Step{}, 0, fmt.Errorf("unterminated bracket at %q", s)
+	}
+
+	inner := strings.TrimSpace(s[1:end])
+	if inner == "" {
+		return Step{Kind: AppendStep}, end + 1, nil
+	}
+
+	if eq := strings.IndexByte(inner, '='); eq >= 0 {
+		key, value := strings.TrimSpace(inner[:eq]), strings.TrimSpace(inner[eq+1:])
+		if key == "" {
+			return Step{}, 0, fmt.Errorf("selector %q has an empty field name", inner)
+		}
+		return Step{Kind: MatchStep, Key: key, Value: value}, end + 1, nil
+	}
+
+	idx, err := strconv.Atoi(inner)
+	if err != nil {
+		return Step{}, 0, fmt.Errorf("%q is not an index, field=value selector or quoted name", inner)
+	}
+	return Step{Kind: IndexStep, Index: idx}, end + 1, nil
+}
@@ -0,0 +1,219 @@
+package setpath_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/pkg/setpath"
+)
+
+const doc = `{
+  "definition": {
+    "displayText": "display",
+    "plans": [
+      {
+        "planId": "one",
+        "microsoft-azure-virtualmachines.skuTitle": "One",
+        "regions": ["US"]
+      },
+      {
+        "planId": "two",
+        "microsoft-azure-virtualmachines.vmImages": {
+          "2019.10.11": {"osVhdUrl": "vhd"}
+        }
+      }
+    ]
+  }
+}`
+
+func newDoc(t *testing.T) interface{} {
+	var v interface{}
+	require.NoError(t, json.Unmarshal([]byte(doc), &v))
+	return v
+}
+
+func toJSON(t *testing.T, v interface{}) string {
+	bits, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(bits)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"definition.displayText":                   "definition.displayText",
+		"definition.plans[0].regions[]":            "definition.plans[0].regions[]",
+		"definition.plans[planId = foo].skuTitle":  "definition.plans[planId=foo].skuTitle",
+		"definition['a.b'][-1]":                    "definition['a.b'][-1]",
+		`['it\'s']`:                                `['it\'s']`,
+		"plans[planId=foo].microsoft-azure-vm.sku": "plans[planId=foo].microsoft-azure-vm.sku",
+	}
+
+	for input, expected := range cases {
+		p, err := setpath.Parse(input)
+		if assert.NoError(t, err, input) {
+			assert.Equal(t, expected, p.String(), input)
+		}
+	}
+
+	for _, input := range []string{"", "a.", ".a", "a..b", "a[", "a['b'", "a[b]", "a[=b]", "a.[0]", "a[0]b"} {
+		_, err := setpath.Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestParseAssignment(t *testing.T) {
+	t.Parallel()
+
+	p, value, err := setpath.ParseAssignment("definition.plans[planId=foo].skuTitle=a=b")
+	require.NoError(t, err)
+	assert.Equal(t, "definition.plans[planId=foo].skuTitle", p.String())
+	assert.Equal(t, "a=b", value)
+
+	p, value, err = setpath.ParseAssignment("['a=]'].b=")
+	require.NoError(t, err)
+	assert.Equal(t, "['a=]'].b", p.String())
+	assert.Equal(t, "", value)
+
+	_, _, err = setpath.ParseAssignment("definition.displayText")
+	assert.Error(t, err)
+}
+
+func TestParseValue(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, true, setpath.ParseValue("true"))
+	assert.Equal(t, float64(3), setpath.ParseValue("3"))
+	assert.Equal(t, 0.5, setpath.ParseValue("0.5"))
+	assert.Nil(t, setpath.ParseValue("null"))
+	assert.Equal(t, "2019.10.11", setpath.ParseValue("2019.10.11"))
+	assert.Equal(t, "007", setpath.ParseValue("007"))
+	assert.Equal(t, "[1]", setpath.ParseValue("[1]"))
+}
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Name     string
+		Path     string
+		Value    interface{}
+		Selector string
+		Expected string
+	}{
+		{
+			Name:     "ExistingKey",
+			Path:     "definition.displayText",
+			Value:    "foo",
+			Selector: "definition.displayText",
+			Expected: `"foo"`,
+		},
+		{
+			Name:     "DottedKeyBySelector",
+			Path:     "definition.plans[planId=one].microsoft-azure-virtualmachines.skuTitle",
+			Value:    false,
+			Selector: "definition.plans[0]['microsoft-azure-virtualmachines.skuTitle']",
+			Expected: `false`,
+		},
+		{
+			Name:     "DottedVersionKey",
+			Path:     "definition.plans[1].microsoft-azure-virtualmachines.vmImages.2019.10.11.osVhdUrl",
+			Value:    "updated",
+			Selector: "definition.plans[1]['microsoft-azure-virtualmachines.vmImages']['2019.10.11'].osVhdUrl",
+			Expected: `"updated"`,
+		},
+		{
+			Name:     "Append",
+			Path:     "definition.plans[planId=one].regions[]",
+			Value:    "CA",
+			Selector: "definition.plans[0].regions",
+			Expected: `["US","CA"]`,
+		},
+		{
+			Name:     "NewElement",
+			Path:     "definition.plans[].planId",
+			Value:    "three",
+			Selector: "definition.plans[2]",
+			Expected: `{"planId":"three"}`,
+		},
+		{
+			Name:     "NewNestedObjects",
+			Path:     "definition.offer.tags[]",
+			Value:    []interface{}{"a"},
+			Selector: "definition.offer",
+			Expected: `{"tags":[["a"]]}`,
+		},
+		{
+			Name:     "NegativeIndex",
+			Path:     "definition.plans[-1].planId",
+			Value:    "last",
+			Selector: "definition.plans[1].planId",
+			Expected: `"last"`,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			p, err := setpath.Parse(c.Path)
+			require.NoError(t, err)
+			updated, err := setpath.Set(newDoc(t), p, c.Value)
+			require.NoError(t, err)
+			assert.JSONEq(t, c.Expected, toJSON(t, get(t, updated, c.Selector)))
+		})
+	}
+}
+
+func TestSet_Fails(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{"definition.displayText.foo", "definition.plans.foo", "definition[0]", "definition.plans[5]", "definition.plans[planId=three].planId"} {
+		p, err := setpath.Parse(path)
+		require.NoError(t, err)
+		_, err = setpath.Set(newDoc(t), p, "foo")
+		assert.Error(t, err, path)
+	}
+}
+
+func TestUnset(t *testing.T) {
+	t.Parallel()
+
+	d := newDoc(t)
+	for _, path := range []string{"definition.plans[planId=two]", "definition.plans[0].microsoft-azure-virtualmachines.skuTitle", "definition.plans[0].regions[0]"} {
+		p, err := setpath.Parse(path)
+		require.NoError(t, err)
+		d, err = setpath.Unset(d, p)
+		require.NoError(t, err, path)
+	}
+	assert.JSONEq(t, `{"definition":{"displayText":"display","plans":[{"planId":"one","regions":[]}]}}`, toJSON(t, d))
+
+	for _, path := range []string{"definition.missing", "definition.plans[planId=nine]", "definition.plans[]", "definition.plans[3]", "definition.displayText[0]"} {
+		p, err := setpath.Parse(path)
+		require.NoError(t, err)
+		_, err = setpath.Unset(newDoc(t), p)
+		assert.Error(t, err, path)
+	}
+}
+
+// get reads the value at a path made only of quoted or undotted names and indexes
+func get(t *testing.T, d interface{}, path string) interface{} {
+	p, err := setpath.Parse(path)
+	require.NoError(t, err)
+
+	node := d
+	for i, s := range p.Steps {
+		switch s.Kind {
+		case setpath.NameStep:
+			node = node.(map[string]interface{})[s.Name]
+		case setpath.IndexStep:
+			node = node.([]interface{})[s.Index]
+		default:
+			t.Fatalf("unsupported step %d in %s", i, path)
+		}
+	}
+	return node
+}
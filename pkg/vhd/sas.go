@@ -0,0 +1,206 @@
+package vhd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/devigned/tab"
+)
+
+const (
+	// DefaultMinExpiry is the minimum remaining lifetime of a SAS token. Certification of an image can take weeks, so
+	// the Cloud Partner Portal recommends a SAS which is valid for at least three weeks.
+	DefaultMinExpiry = 3 * 7 * 24 * time.Hour
+
+	// BlobAlignment is the size, in bytes, that a VHD must be a multiple of
+	BlobAlignment = 1024 * 1024
+
+	pageBlobType = "PageBlob"
+)
+
+type (
+	// Options configures SAS URL validation
+	Options struct {
+		// MinExpiry is the minimum duration from Now until the SAS token expires
+		MinExpiry time.Duration
+		// Now is the time to validate expiry against; if zero, time.Now() is used
+		Now time.Time
+	}
+
+	// SASInfo describes a parsed VHD SAS URL without the signature
+	SASInfo struct {
+		Account     string    `json:"account,omitempty"`
+		Host        string    `json:"host,omitempty"`
+		Container   string    `json:"container,omitempty"`
+		Blob        string    `json:"blob,omitempty"`
+		Expiry      time.Time `json:"expiry,omitempty"`
+		Permissions string    `json:"permissions,omitempty"`
+		Resource    string    `json:"resource,omitempty"`
+		Version     string    `json:"version,omitempty"`
+	}
+
+	// BlobInfo describes the blob found at a VHD SAS URL
+	BlobInfo struct {
+		Size     int64  `json:"size"`
+		BlobType string `json:"blobType,omitempty"`
+	}
+
+	// ValidationError contains each of the problems found while validating a VHD SAS URL
+	ValidationError struct {
+		Problems []string
+	}
+)
+
+var (
+	blobHostRegex = regexp.MustCompile(`^([a-z0-9]{3,24})\.blob\.core\.(windows\.net|usgovcloudapi\.net|chinacloudapi\.cn|cloudapi\.de)$`)
+
+	expiryLayouts = []string{
+		"2006-01-02T15:04:05Z",
+		"2006-01-02T15:04:05.0000000Z",
+		"2006-01-02T15:04Z",
+		"2006-01-02",
+	}
+)
+
+func (ve *ValidationError) Error() string {
+	return fmt.Sprintf("invalid VHD SAS URL:\n  - %s", strings.Join(ve.Problems, "\n  - "))
+}
+
+// ValidateSASURL verifies that a VHD URL is a container SAS URL for a .vhd blob in Azure storage which grants read and
+// list permissions and will not expire before opts.MinExpiry has elapsed. All problems found are returned in a
+// *ValidationError.
+func ValidateSASURL(rawURL string, opts Options) (*SASInfo, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, &ValidationError{Problems: []string{fmt.Sprintf("unable to parse URL: %v", err)}}
+	}
+
+	var problems []string
+	info := &SASInfo{Host: u.Host}
+
+	if u.Scheme != "https" {
+		problems = append(problems, fmt.Sprintf("scheme must be https, but was %q", u.Scheme))
+	}
+
+	if matches := blobHostRegex.FindStringSubmatch(strings.ToLower(u.Host)); matches != nil {
+		info.Account = matches[1]
+	} else {
+		problems = append(problems, fmt.Sprintf("host %q is not an Azure storage blob endpoint like <account>.blob.core.windows.net", u.Host))
+	}
+
+	segments := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)
+	if len(segments) != 2 || segments[0] == "" || segments[1] == "" {
+		problems = append(problems, fmt.Sprintf("path %q must be in the form /<container>/<blob>.vhd", u.Path))
+	} else {
+		info.Container = segments[0]
+		info.Blob = segments[1]
+	}
+
+	if !strings.HasSuffix(strings.ToLower(u.Path), ".vhd") {
+		problems = append(problems, "blob name must end in .vhd")
+	}
+
+	query := u.Query()
+	if query.Get("sig") == "" {
+		problems = append(problems, "missing SAS signature (sig)")
+	}
+
+	info.Version = query.Get("sv")
+	if info.Version == "" {
+		problems = append(problems, "missing SAS signed version (sv)")
+	}
+
+	info.Resource = query.Get("sr")
+	if info.Resource != "c" {
+		problems = append(problems, fmt.Sprintf("SAS signed resource (sr) must be a container (c), but was %q", info.Resource))
+	}
+
+	info.Permissions = query.Get("sp")
+	for _, perm := range []string{"r", "l"} {
+		if !strings.Contains(info.Permissions, perm) {
+			problems = append(problems, fmt.Sprintf("SAS signed permissions (sp) must include %q, but was %q", perm, info.Permissions))
+		}
+	}
+
+	expiry, err := parseExpiry(query.Get("se"))
+	if err != nil {
+		problems = append(problems, err.Error())
+	} else {
+		info.Expiry = expiry
+		now := opts.Now
+		if now.IsZero() {
+			now = time.Now()
+		}
+
+		if expiry.Before(now.Add(opts.MinExpiry)) {
+			problems = append(problems, fmt.Sprintf("SAS expires at %s, but must be valid until at least %s", expiry.Format(time.RFC3339), now.Add(opts.MinExpiry).Format(time.RFC3339)))
+		}
+	}
+
+	if len(problems) > 0 {
+		return info, &ValidationError{Problems: problems}
+	}
+	return info, nil
+}
+
+// CheckBlob issues a HEAD request to the VHD SAS URL to verify the blob exists, is a page blob and its size is aligned
+// to BlobAlignment.
+func CheckBlob(ctx context.Context, client *http.Client, rawURL string) (*BlobInfo, error) {
+	ctx, span := tab.StartSpan(ctx, "vhd.CheckBlob")
+	defer span.End()
+
+	req, err := http.NewRequest(http.MethodHead, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		tab.For(ctx).Error(err)
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode > 299 {
+		return nil, &ValidationError{Problems: []string{fmt.Sprintf("HEAD request for the blob returned status %d", res.StatusCode)}}
+	}
+
+	info := &BlobInfo{
+		BlobType: res.Header.Get("x-ms-blob-type"),
+		Size:     res.ContentLength,
+	}
+
+	var problems []string
+	if info.BlobType != "" && info.BlobType != pageBlobType {
+		problems = append(problems, fmt.Sprintf("blob type must be %s, but was %s", pageBlobType, info.BlobType))
+	}
+
+	if info.Size <= 0 || info.Size%BlobAlignment != 0 {
+		problems = append(problems, fmt.Sprintf("blob size %d bytes is not a multiple of 1 MB", info.Size))
+	}
+
+	if len(problems) > 0 {
+		return info, &ValidationError{Problems: problems}
+	}
+	return info, nil
+}
+
+func parseExpiry(se string) (time.Time, error) {
+	if se == "" {
+		return time.Time{}, fmt.Errorf("missing SAS signed expiry (se)")
+	}
+
+	for _, layout := range expiryLayouts {
+		if t, err := time.Parse(layout, se); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to parse SAS signed expiry (se) %q", se)
+}
@@ -0,0 +1,99 @@
+package vhd_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devigned/pub/pkg/vhd"
+)
+
+var now = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestValidateSASURL_Success(t *testing.T) {
+	t.Parallel()
+
+	info, err := vhd.ValidateSASURL(
+		"https://account.blob.core.windows.net/vhds/os.vhd?sv=2019-02-02&sr=c&sp=rl&se=2020-02-01T00:00:00Z&sig=abc",
+		vhd.Options{MinExpiry: vhd.DefaultMinExpiry, Now: now},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, &vhd.SASInfo{
+		Account:     "account",
+		Host:        "account.blob.core.windows.net",
+		Container:   "vhds",
+		Blob:        "os.vhd",
+		Expiry:      time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+		Permissions: "rl",
+		Resource:    "c",
+		Version:     "2019-02-02",
+	}, info)
+}
+
+func TestValidateSASURL_Problems(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"expires too soon":   "https://account.blob.core.windows.net/vhds/os.vhd?sv=2019-02-02&sr=c&sp=rl&se=2020-01-10&sig=abc",
+		"missing list":       "https://account.blob.core.windows.net/vhds/os.vhd?sv=2019-02-02&sr=c&sp=r&se=2020-02-01&sig=abc",
+		"blob resource":      "https://account.blob.core.windows.net/vhds/os.vhd?sv=2019-02-02&sr=b&sp=rl&se=2020-02-01&sig=abc",
+		"wrong host":         "https://account.file.core.windows.net/vhds/os.vhd?sv=2019-02-02&sr=c&sp=rl&se=2020-02-01&sig=abc",
+		"not a vhd":          "https://account.blob.core.windows.net/vhds/os.img?sv=2019-02-02&sr=c&sp=rl&se=2020-02-01&sig=abc",
+		"no container":       "https://account.blob.core.windows.net/os.vhd?sv=2019-02-02&sr=c&sp=rl&se=2020-02-01&sig=abc",
+		"http":               "http://account.blob.core.windows.net/vhds/os.vhd?sv=2019-02-02&sr=c&sp=rl&se=2020-02-01&sig=abc",
+		"missing signature":  "https://account.blob.core.windows.net/vhds/os.vhd?sv=2019-02-02&sr=c&sp=rl&se=2020-02-01",
+		"missing version":    "https://account.blob.core.windows.net/vhds/os.vhd?sr=c&sp=rl&se=2020-02-01&sig=abc",
+		"unparsable expiry":  "https://account.blob.core.windows.net/vhds/os.vhd?sv=2019-02-02&sr=c&sp=rl&se=tomorrow&sig=abc",
+		"missing everything": "uri",
+	}
+
+	for name, uri := range cases {
+		_, err := vhd.ValidateSASURL(uri, vhd.Options{MinExpiry: vhd.DefaultMinExpiry, Now: now})
+		if assert.Error(t, err, name) {
+			assert.IsType(t, &vhd.ValidationError{}, err, name)
+		}
+	}
+}
+
+func TestCheckBlob(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Name     string
+		Status   int
+		Size     int
+		BlobType string
+		Err      bool
+	}{
+		{Name: "aligned page blob", Status: http.StatusOK, Size: 30 * vhd.BlobAlignment, BlobType: "PageBlob"},
+		{Name: "unaligned", Status: http.StatusOK, Size: 30*vhd.BlobAlignment + 512, BlobType: "PageBlob", Err: true},
+		{Name: "block blob", Status: http.StatusOK, Size: vhd.BlobAlignment, BlobType: "BlockBlob", Err: true},
+		{Name: "not found", Status: http.StatusNotFound, Err: true},
+	}
+
+	for _, c := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodHead, r.Method)
+			w.Header().Set("Content-Length", strconv.Itoa(c.Size))
+			w.Header().Set("x-ms-blob-type", c.BlobType)
+			w.WriteHeader(c.Status)
+		}))
+
+		info, err := vhd.CheckBlob(context.Background(), srv.Client(), srv.URL+"/vhds/os.vhd")
+		srv.Close()
+		if c.Err {
+			assert.Error(t, err, c.Name)
+			continue
+		}
+
+		if assert.NoError(t, err, c.Name) {
+			assert.Equal(t, int64(c.Size), info.Size, c.Name)
+		}
+	}
+}